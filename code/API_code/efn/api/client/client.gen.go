@@ -112,6 +112,20 @@ type ClientInterface interface {
 	CalculateEnergyConsumptionWithBody(ctx context.Context, params *CalculateEnergyConsumptionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
 
 	CalculateEnergyConsumption(ctx context.Context, params *CalculateEnergyConsumptionParams, body CalculateEnergyConsumptionJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ListReports request
+	ListReports(ctx context.Context, params *ListReportsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CancelReport request
+	CancelReport(ctx context.Context, requestId RequestId, params *CancelReportParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetReport request
+	GetReport(ctx context.Context, requestId RequestId, params *GetReportParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ValidateReportWithBody request with any body
+	ValidateReportWithBody(ctx context.Context, params *ValidateReportParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	ValidateReport(ctx context.Context, params *ValidateReportParams, body ValidateReportJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
 }
 
 func (c *Client) CalculateCarbonFootprintWithBody(ctx context.Context, params *CalculateCarbonFootprintParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
@@ -162,6 +176,66 @@ func (c *Client) CalculateEnergyConsumption(ctx context.Context, params *Calcula
 	return c.Client.Do(req)
 }
 
+func (c *Client) ListReports(ctx context.Context, params *ListReportsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListReportsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CancelReport(ctx context.Context, requestId RequestId, params *CancelReportParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCancelReportRequest(c.Server, requestId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetReport(ctx context.Context, requestId RequestId, params *GetReportParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetReportRequest(c.Server, requestId, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ValidateReportWithBody(ctx context.Context, params *ValidateReportParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewValidateReportRequestWithBody(c.Server, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ValidateReport(ctx context.Context, params *ValidateReportParams, body ValidateReportJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewValidateReportRequest(c.Server, params, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
 // NewCalculateCarbonFootprintRequest calls the generic CalculateCarbonFootprint builder with application/json body
 func NewCalculateCarbonFootprintRequest(server string, params *CalculateCarbonFootprintParams, body CalculateCarbonFootprintJSONRequestBody) (*http.Request, error) {
 	var bodyReader io.Reader
@@ -212,6 +286,28 @@ func NewCalculateCarbonFootprintRequestWithBody(server string, params *Calculate
 			req.Header.Set("x-correlator", headerParam0)
 		}
 
+		if params.IdempotencyKey != nil {
+			var headerParam1 string
+
+			headerParam1, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Idempotency-Key", headerParam1)
+		}
+
+		if params.Prefer != nil {
+			var headerParam2 string
+
+			headerParam2, err = runtime.StyleParamWithLocation("simple", false, "Prefer", runtime.ParamLocationHeader, *params.Prefer)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Prefer", headerParam2)
+		}
+
 	}
 
 	return req, nil
@@ -267,136 +363,628 @@ func NewCalculateEnergyConsumptionRequestWithBody(server string, params *Calcula
 			req.Header.Set("x-correlator", headerParam0)
 		}
 
-	}
+		if params.IdempotencyKey != nil {
+			var headerParam1 string
 
-	return req, nil
-}
+			headerParam1, err = runtime.StyleParamWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, *params.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
 
-func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
-	for _, r := range c.RequestEditors {
-		if err := r(ctx, req); err != nil {
-			return err
+			req.Header.Set("Idempotency-Key", headerParam1)
 		}
-	}
-	for _, r := range additionalEditors {
-		if err := r(ctx, req); err != nil {
-			return err
+
+		if params.Prefer != nil {
+			var headerParam2 string
+
+			headerParam2, err = runtime.StyleParamWithLocation("simple", false, "Prefer", runtime.ParamLocationHeader, *params.Prefer)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("Prefer", headerParam2)
 		}
+
 	}
-	return nil
-}
 
-// ClientWithResponses builds on ClientInterface to offer response payloads
-type ClientWithResponses struct {
-	ClientInterface
+	return req, nil
 }
 
-// NewClientWithResponses creates a new ClientWithResponses, which wraps
-// Client with return type handling
-func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
-	client, err := NewClient(server, opts...)
+// NewListReportsRequest generates requests for ListReports
+func NewListReportsRequest(server string, params *ListReportsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
-	return &ClientWithResponses{client}, nil
-}
 
-// WithBaseURL overrides the baseURL.
-func WithBaseURL(baseURL string) ClientOption {
-	return func(c *Client) error {
-		newBaseURL, err := url.Parse(baseURL)
-		if err != nil {
-			return err
-		}
-		c.Server = newBaseURL.String()
-		return nil
+	operationPath := fmt.Sprintf("/reports")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
 	}
-}
 
-// ClientWithResponsesInterface is the interface specification for the client with responses above.
-type ClientWithResponsesInterface interface {
-	// CalculateCarbonFootprintWithBodyWithResponse request with any body
-	CalculateCarbonFootprintWithBodyWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error)
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
 
-	CalculateCarbonFootprintWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, body CalculateCarbonFootprintJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error)
+	if params != nil {
+		queryValues := queryURL.Query()
 
-	// CalculateEnergyConsumptionWithBodyWithResponse request with any body
-	CalculateEnergyConsumptionWithBodyWithResponse(ctx context.Context, params *CalculateEnergyConsumptionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateEnergyConsumptionResponse, error)
+		if params.Status != nil {
 
-	CalculateEnergyConsumptionWithResponse(ctx context.Context, params *CalculateEnergyConsumptionParams, body CalculateEnergyConsumptionJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateEnergyConsumptionResponse, error)
-}
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "status", runtime.ParamLocationQuery, *params.Status); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
 
-type CalculateCarbonFootprintResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON201      *ReportCreationRequest
-	JSON400      *Generic400
-	JSON401      *Generic401
-	JSON403      *Generic403
-	JSON404      *Generic404
-}
+		}
 
-// Status returns HTTPResponse.Status
-func (r CalculateCarbonFootprintResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
+		if params.Kind != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "kind", runtime.ParamLocationQuery, *params.Kind); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.CreatedFrom != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "createdFrom", runtime.ParamLocationQuery, *params.CreatedFrom); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.CreatedTo != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "createdTo", runtime.ParamLocationQuery, *params.CreatedTo); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.AppInstanceId != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "appInstanceId", runtime.ParamLocationQuery, *params.AppInstanceId); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.ParentRequestId != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "parentRequestId", runtime.ParamLocationQuery, *params.ParentRequestId); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Cursor != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cursor", runtime.ParamLocationQuery, *params.Cursor); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
 	}
-	return http.StatusText(0)
-}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r CalculateCarbonFootprintResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
 	}
-	return 0
-}
 
-type CalculateEnergyConsumptionResponse struct {
-	Body         []byte
-	HTTPResponse *http.Response
-	JSON201      *ReportCreationRequest
-	JSON400      *Generic400
-	JSON401      *Generic401
-	JSON403      *Generic403
-	JSON404      *Generic404
-}
+	if params != nil {
 
-// Status returns HTTPResponse.Status
-func (r CalculateEnergyConsumptionResponse) Status() string {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.Status
-	}
-	return http.StatusText(0)
-}
+		if params.XCorrelator != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "x-correlator", runtime.ParamLocationHeader, *params.XCorrelator)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("x-correlator", headerParam0)
+		}
 
-// StatusCode returns HTTPResponse.StatusCode
-func (r CalculateEnergyConsumptionResponse) StatusCode() int {
-	if r.HTTPResponse != nil {
-		return r.HTTPResponse.StatusCode
 	}
-	return 0
+
+	return req, nil
 }
 
-// CalculateCarbonFootprintWithBodyWithResponse request with arbitrary body returning *CalculateCarbonFootprintResponse
-func (c *ClientWithResponses) CalculateCarbonFootprintWithBodyWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error) {
-	rsp, err := c.CalculateCarbonFootprintWithBody(ctx, params, contentType, body, reqEditors...)
+// NewCancelReportRequest generates requests for CancelReport
+func NewCancelReportRequest(server string, requestId RequestId, params *CancelReportParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "requestId", runtime.ParamLocationPath, requestId)
 	if err != nil {
 		return nil, err
 	}
-	return ParseCalculateCarbonFootprintResponse(rsp)
-}
 
-func (c *ClientWithResponses) CalculateCarbonFootprintWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, body CalculateCarbonFootprintJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error) {
-	rsp, err := c.CalculateCarbonFootprint(ctx, params, body, reqEditors...)
+	serverURL, err := url.Parse(server)
 	if err != nil {
 		return nil, err
 	}
-	return ParseCalculateCarbonFootprintResponse(rsp)
-}
 
-// CalculateEnergyConsumptionWithBodyWithResponse request with arbitrary body returning *CalculateEnergyConsumptionResponse
-func (c *ClientWithResponses) CalculateEnergyConsumptionWithBodyWithResponse(ctx context.Context, params *CalculateEnergyConsumptionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateEnergyConsumptionResponse, error) {
+	operationPath := fmt.Sprintf("/reports/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XCorrelator != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "x-correlator", runtime.ParamLocationHeader, *params.XCorrelator)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("x-correlator", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewGetReportRequest generates requests for GetReport
+func NewGetReportRequest(server string, requestId RequestId, params *GetReportParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "requestId", runtime.ParamLocationPath, requestId)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/reports/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		if params.XCorrelator != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "x-correlator", runtime.ParamLocationHeader, *params.XCorrelator)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("x-correlator", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+// NewValidateReportRequest calls the generic ValidateReport builder with application/json body
+func NewValidateReportRequest(server string, params *ValidateReportParams, body ValidateReportJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewValidateReportRequestWithBody(server, params, "application/json", bodyReader)
+}
+
+// NewValidateReportRequestWithBody generates requests for ValidateReport with any type of body
+func NewValidateReportRequestWithBody(server string, params *ValidateReportParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/reports:validate")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "kind", runtime.ParamLocationQuery, params.Kind); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		if params.XCorrelator != nil {
+			var headerParam0 string
+
+			headerParam0, err = runtime.StyleParamWithLocation("simple", false, "x-correlator", runtime.ParamLocationHeader, *params.XCorrelator)
+			if err != nil {
+				return nil, err
+			}
+
+			req.Header.Set("x-correlator", headerParam0)
+		}
+
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// CalculateCarbonFootprintWithBodyWithResponse request with any body
+	CalculateCarbonFootprintWithBodyWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error)
+
+	CalculateCarbonFootprintWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, body CalculateCarbonFootprintJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error)
+
+	// CalculateEnergyConsumptionWithBodyWithResponse request with any body
+	CalculateEnergyConsumptionWithBodyWithResponse(ctx context.Context, params *CalculateEnergyConsumptionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateEnergyConsumptionResponse, error)
+
+	CalculateEnergyConsumptionWithResponse(ctx context.Context, params *CalculateEnergyConsumptionParams, body CalculateEnergyConsumptionJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateEnergyConsumptionResponse, error)
+
+	// ListReportsWithResponse request
+	ListReportsWithResponse(ctx context.Context, params *ListReportsParams, reqEditors ...RequestEditorFn) (*ListReportsResponse, error)
+
+	// CancelReportWithResponse request
+	CancelReportWithResponse(ctx context.Context, requestId RequestId, params *CancelReportParams, reqEditors ...RequestEditorFn) (*CancelReportResponse, error)
+
+	// GetReportWithResponse request
+	GetReportWithResponse(ctx context.Context, requestId RequestId, params *GetReportParams, reqEditors ...RequestEditorFn) (*GetReportResponse, error)
+
+	// ValidateReportWithBodyWithResponse request with any body
+	ValidateReportWithBodyWithResponse(ctx context.Context, params *ValidateReportParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ValidateReportResponse, error)
+
+	ValidateReportWithResponse(ctx context.Context, params *ValidateReportParams, body ValidateReportJSONRequestBody, reqEditors ...RequestEditorFn) (*ValidateReportResponse, error)
+}
+
+type CalculateCarbonFootprintResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Report
+	JSON201      *ReportCreationRequest
+	JSON400      *Generic400
+	JSON401      *Generic401
+	JSON403      *Generic403
+	JSON404      *Generic404
+	JSON409      *Generic409
+	JSON429      *Generic429
+}
+
+// Status returns HTTPResponse.Status
+func (r CalculateCarbonFootprintResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CalculateCarbonFootprintResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CalculateEnergyConsumptionResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Report
+	JSON201      *ReportCreationRequest
+	JSON400      *Generic400
+	JSON401      *Generic401
+	JSON403      *Generic403
+	JSON404      *Generic404
+	JSON409      *Generic409
+	JSON429      *Generic429
+}
+
+// Status returns HTTPResponse.Status
+func (r CalculateEnergyConsumptionResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CalculateEnergyConsumptionResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ListReportsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReportList
+	JSON400      *Generic400
+	JSON401      *Generic401
+	JSON403      *Generic403
+}
+
+// Status returns HTTPResponse.Status
+func (r ListReportsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListReportsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CancelReportResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON202      *Report
+	JSON400      *Generic400
+	JSON401      *Generic401
+	JSON403      *Generic403
+	JSON404      *Generic404
+	JSON409      *Generic409
+}
+
+// Status returns HTTPResponse.Status
+func (r CancelReportResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CancelReportResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetReportResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Report
+	JSON400      *Generic400
+	JSON401      *Generic401
+	JSON403      *Generic403
+	JSON404      *Generic404
+}
+
+// Status returns HTTPResponse.Status
+func (r GetReportResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetReportResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ValidateReportResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ReportValidation
+	JSON400      *Generic400
+	JSON401      *Generic401
+	JSON403      *Generic403
+}
+
+// Status returns HTTPResponse.Status
+func (r ValidateReportResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ValidateReportResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// CalculateCarbonFootprintWithBodyWithResponse request with arbitrary body returning *CalculateCarbonFootprintResponse
+func (c *ClientWithResponses) CalculateCarbonFootprintWithBodyWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error) {
+	rsp, err := c.CalculateCarbonFootprintWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCalculateCarbonFootprintResponse(rsp)
+}
+
+func (c *ClientWithResponses) CalculateCarbonFootprintWithResponse(ctx context.Context, params *CalculateCarbonFootprintParams, body CalculateCarbonFootprintJSONRequestBody, reqEditors ...RequestEditorFn) (*CalculateCarbonFootprintResponse, error) {
+	rsp, err := c.CalculateCarbonFootprint(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCalculateCarbonFootprintResponse(rsp)
+}
+
+// CalculateEnergyConsumptionWithBodyWithResponse request with arbitrary body returning *CalculateEnergyConsumptionResponse
+func (c *ClientWithResponses) CalculateEnergyConsumptionWithBodyWithResponse(ctx context.Context, params *CalculateEnergyConsumptionParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*CalculateEnergyConsumptionResponse, error) {
 	rsp, err := c.CalculateEnergyConsumptionWithBody(ctx, params, contentType, body, reqEditors...)
 	if err != nil {
 		return nil, err
@@ -412,6 +1000,50 @@ func (c *ClientWithResponses) CalculateEnergyConsumptionWithResponse(ctx context
 	return ParseCalculateEnergyConsumptionResponse(rsp)
 }
 
+// ListReportsWithResponse request returning *ListReportsResponse
+func (c *ClientWithResponses) ListReportsWithResponse(ctx context.Context, params *ListReportsParams, reqEditors ...RequestEditorFn) (*ListReportsResponse, error) {
+	rsp, err := c.ListReports(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListReportsResponse(rsp)
+}
+
+// CancelReportWithResponse request returning *CancelReportResponse
+func (c *ClientWithResponses) CancelReportWithResponse(ctx context.Context, requestId RequestId, params *CancelReportParams, reqEditors ...RequestEditorFn) (*CancelReportResponse, error) {
+	rsp, err := c.CancelReport(ctx, requestId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCancelReportResponse(rsp)
+}
+
+// GetReportWithResponse request returning *GetReportResponse
+func (c *ClientWithResponses) GetReportWithResponse(ctx context.Context, requestId RequestId, params *GetReportParams, reqEditors ...RequestEditorFn) (*GetReportResponse, error) {
+	rsp, err := c.GetReport(ctx, requestId, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetReportResponse(rsp)
+}
+
+// ValidateReportWithBodyWithResponse request with arbitrary body returning *ValidateReportResponse
+func (c *ClientWithResponses) ValidateReportWithBodyWithResponse(ctx context.Context, params *ValidateReportParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*ValidateReportResponse, error) {
+	rsp, err := c.ValidateReportWithBody(ctx, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseValidateReportResponse(rsp)
+}
+
+func (c *ClientWithResponses) ValidateReportWithResponse(ctx context.Context, params *ValidateReportParams, body ValidateReportJSONRequestBody, reqEditors ...RequestEditorFn) (*ValidateReportResponse, error) {
+	rsp, err := c.ValidateReport(ctx, params, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseValidateReportResponse(rsp)
+}
+
 // ParseCalculateCarbonFootprintResponse parses an HTTP response from a CalculateCarbonFootprintWithResponse call
 func ParseCalculateCarbonFootprintResponse(rsp *http.Response) (*CalculateCarbonFootprintResponse, error) {
 	bodyBytes, err := io.ReadAll(rsp.Body)
@@ -426,6 +1058,13 @@ func ParseCalculateCarbonFootprintResponse(rsp *http.Response) (*CalculateCarbon
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Report
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
 		var dest ReportCreationRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -461,6 +1100,20 @@ func ParseCalculateCarbonFootprintResponse(rsp *http.Response) (*CalculateCarbon
 		}
 		response.JSON404 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Generic409
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest Generic429
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
 	}
 
 	return response, nil
@@ -480,6 +1133,13 @@ func ParseCalculateEnergyConsumptionResponse(rsp *http.Response) (*CalculateEner
 	}
 
 	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Report
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
 	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 201:
 		var dest ReportCreationRequest
 		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
@@ -515,6 +1175,229 @@ func ParseCalculateEnergyConsumptionResponse(rsp *http.Response) (*CalculateEner
 		}
 		response.JSON404 = &dest
 
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Generic409
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 429:
+		var dest Generic429
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON429 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseListReportsResponse parses an HTTP response from a ListReportsWithResponse call
+func ParseListReportsResponse(rsp *http.Response) (*ListReportsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListReportsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReportList
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Generic400
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Generic401
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Generic403
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseCancelReportResponse parses an HTTP response from a CancelReportWithResponse call
+func ParseCancelReportResponse(rsp *http.Response) (*CancelReportResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CancelReportResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 202:
+		var dest Report
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON202 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Generic400
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Generic401
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Generic403
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Generic404
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Generic409
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetReportResponse parses an HTTP response from a GetReportWithResponse call
+func ParseGetReportResponse(rsp *http.Response) (*GetReportResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetReportResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Report
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Generic400
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Generic401
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Generic403
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Generic404
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseValidateReportResponse parses an HTTP response from a ValidateReportWithResponse call
+func ParseValidateReportResponse(rsp *http.Response) (*ValidateReportResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ValidateReportResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ReportValidation
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Generic400
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Generic401
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 403:
+		var dest Generic403
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON403 = &dest
+
 	}
 
 	return response, nil
@@ -27,7 +27,6 @@ const (
 
 // Defines values for AMQPSettingsSenderSettlementMode.
 const (
-	Settled   AMQPSettingsSenderSettlementMode = "settled"
 	Unsettled AMQPSettingsSenderSettlementMode = "unsettled"
 )
 
@@ -43,6 +42,12 @@ const (
 	AccessTokenCredentialCredentialTypeREFRESHTOKEN AccessTokenCredentialCredentialType = "REFRESHTOKEN"
 )
 
+// Defines values for ApacheKafkaSettingsContentMode.
+const (
+	Binary     ApacheKafkaSettingsContentMode = "binary"
+	Structured ApacheKafkaSettingsContentMode = "structured"
+)
+
 // Defines values for CloudEventDatacontenttype.
 const (
 	Applicationjson CloudEventDatacontenttype = "application/json"
@@ -53,12 +58,25 @@ const (
 	N10 CloudEventSpecversion = "1.0"
 )
 
+// Defines values for EstimatedNetworkElementMethod.
+const (
+	EqualSplit EstimatedNetworkElementMethod = "equal-split"
+	Zero       EstimatedNetworkElementMethod = "zero"
+)
+
 // Defines values for EventTypeNotification.
 const (
 	EventTypeNotificationOrgCamaraprojectEnergyFootprintNotificationV1CarbonFootprint EventTypeNotification = "org.camaraproject.energy-footprint-notification.v1.carbon-footprint"
 	EventTypeNotificationOrgCamaraprojectEnergyFootprintNotificationV1Energy          EventTypeNotification = "org.camaraproject.energy-footprint-notification.v1.energy"
 )
 
+// Defines values for FailureReasonCause.
+const (
+	BackendError     FailureReasonCause = "backend-error"
+	RetriesExhausted FailureReasonCause = "retries-exhausted"
+	Timeout          FailureReasonCause = "timeout"
+)
+
 // Defines values for HTTPSettingsMethod.
 const (
 	POST HTTPSettingsMethod = "POST"
@@ -93,21 +111,58 @@ const (
 	REFRESHTOKEN RefreshTokenCredentialCredentialType = "REFRESHTOKEN"
 )
 
+// Defines values for ReportKind.
+const (
+	CarbonFootprint   ReportKind = "carbon-footprint"
+	EnergyConsumption ReportKind = "energy-consumption"
+)
+
+// Defines values for ReportStatus.
+const (
+	Calculating ReportStatus = "calculating"
+	Cancelled   ReportStatus = "cancelled"
+	Completed   ReportStatus = "completed"
+	Created     ReportStatus = "created"
+	Failed      ReportStatus = "failed"
+	Gathering   ReportStatus = "gathering"
+	Notifying   ReportStatus = "notifying"
+)
+
+// Defines values for ReportingPeriod.
+const (
+	Daily  ReportingPeriod = "daily"
+	Hourly ReportingPeriod = "hourly"
+	Weekly ReportingPeriod = "weekly"
+)
+
 // Defines values for SubscriptionEventType.
 const (
 	SubscriptionEventTypeOrgCamaraprojectEnergyFootprintNotificationV1CarbonFootprint SubscriptionEventType = "org.camaraproject.energy-footprint-notification.v1.carbon-footprint"
 	SubscriptionEventTypeOrgCamaraprojectEnergyFootprintNotificationV1Energy          SubscriptionEventType = "org.camaraproject.energy-footprint-notification.v1.energy"
 )
 
-// AMQPSettings defines model for AMQPSettings.
+// AMQPSettings The client authenticates with SASL PLAIN: the identifier and the secret of a `PLAIN` credential, or the
+// `accessTokenType` (`bearer`) and the access token of an `ACCESSTOKEN` credential. It uses SASL ANONYMOUS
+// without `sinkCredential`.
 type AMQPSettings struct {
-	Address              *string                           `json:"address,omitempty"`
-	LinkName             *string                           `json:"linkName,omitempty"`
-	LinkProperties       *map[string]string                `json:"linkProperties,omitempty"`
+	// Address Target address of the link the notifications are sent on, such as a queue or a topic. Defaults to the path of the sink.
+	Address *string `json:"address,omitempty"`
+
+	// LinkName Name of the link the notifications are sent on. Defaults to a generated one.
+	LinkName *string `json:"linkName,omitempty"`
+
+	// LinkProperties Properties of the link the notifications are sent on.
+	LinkProperties *map[string]string `json:"linkProperties,omitempty"`
+
+	// SenderSettlementMode Settlement mode of the notifications. They are sent `unsettled`, and delivered once the receiver settles
+	// them as accepted. Pre-settled notifications are not offered, as nothing would confirm their delivery.
+	// Defaults to `unsettled`.
 	SenderSettlementMode *AMQPSettingsSenderSettlementMode `json:"senderSettlementMode,omitempty"`
 }
 
-// AMQPSettingsSenderSettlementMode defines model for AMQPSettings.SenderSettlementMode.
+// AMQPSettingsSenderSettlementMode Settlement mode of the notifications. They are sent `unsettled`, and delivered once the receiver settles
+// them as accepted. Pre-settled notifications are not offered, as nothing would confirm their delivery.
+// Defaults to `unsettled`.
 type AMQPSettingsSenderSettlementMode string
 
 // AMQPSubscriptionRequest defines model for AMQPSubscriptionRequest.
@@ -118,11 +173,21 @@ type AMQPSubscriptionRequest struct {
 	// Note: if a request is performed for several event type, all subscribed event will use same `config` parameters.
 	Config Config `json:"config"`
 
-	// Protocol Identifier of a delivery protocol. Only HTTP is allowed for now
-	Protocol         Protocol      `json:"protocol"`
+	// Protocol Identifier of a delivery protocol. HTTP, MQTT3, MQTT5, KAFKA, AMQP and NATS are allowed
+	Protocol Protocol `json:"protocol"`
+
+	// ProtocolSettings The client authenticates with SASL PLAIN: the identifier and the secret of a `PLAIN` credential, or the
+	// `accessTokenType` (`bearer`) and the access token of an `ACCESSTOKEN` credential. It uses SASL ANONYMOUS
+	// without `sinkCredential`.
 	ProtocolSettings *AMQPSettings `json:"protocolSettings,omitempty"`
 
-	// Sink The address to which events shall be delivered using the selected protocol.
+	// Sink The address to which events shall be delivered using the selected protocol:
+	// an `https` URL for `HTTP`, the `mqtt` or `mqtts` URL of the broker for `MQTT3` and `MQTT5`,
+	// a `kafka` or `kafkas` URL with the comma-separated bootstrap brokers for `KAFKA`,
+	// the `amqp` or `amqps` URL of the container for `AMQP`,
+	// a `nats` or `tls` URL with the comma-separated servers for `NATS`.
+	// A subscription with a `sinkCredential` requires a TLS scheme (`https`, `mqtts`, `kafkas`, `amqps` or `tls`),
+	// unless the sink is a service internal to the cluster of the implementation, not to send the credential in cleartext.
 	Sink string `json:"sink"`
 
 	// SinkCredential A sink credential provides authentication or authorization information necessary to enable delivery of events to a target.
@@ -161,12 +226,31 @@ type AccessTokenCredentialCredentialType string
 
 // ApacheKafkaSettings defines model for ApacheKafkaSettings.
 type ApacheKafkaSettings struct {
-	AckMode               *int    `json:"ackMode,omitempty"`
-	ClientId              *string `json:"clientId,omitempty"`
+	// AckMode Acknowledgements required from the brokers, as the Kafka `acks` setting: `-1` for all in-sync replicas,
+	// `1` for the partition leader only, `0` for none. A notification is delivered once acknowledged, or once
+	// written to the broker with `0`. Defaults to `-1`.
+	AckMode *int `json:"ackMode,omitempty"`
+
+	// ClientId Client ID the notifications are produced with.
+	ClientId *string `json:"clientId,omitempty"`
+
+	// ContentMode CloudEvents content mode of the messages. In `structured` mode the value is the whole event; in `binary`
+	// mode it is the event data, with the attributes in `ce_` headers. Defaults to `structured`.
+	ContentMode *ApacheKafkaSettingsContentMode `json:"contentMode,omitempty"`
+
+	// PartitionKeyExtractor Where the key of the messages comes from: the name of a CloudEvent attribute (`id`, `source`, `type`,
+	// `specversion` or `time`), or `data.` followed by the dot-separated path of a field of the event data,
+	// such as `data.requestId`. Messages have no key when it is not set or the field is missing.
 	PartitionKeyExtractor *string `json:"partitionKeyExtractor,omitempty"`
-	TopicName             string  `json:"topicName"`
+
+	// TopicName Topic the notifications are produced to.
+	TopicName string `json:"topicName"`
 }
 
+// ApacheKafkaSettingsContentMode CloudEvents content mode of the messages. In `structured` mode the value is the whole event; in `binary`
+// mode it is the event data, with the attributes in `ce_` headers. Defaults to `structured`.
+type ApacheKafkaSettingsContentMode string
+
 // ApacheKafkaSubscriptionRequest defines model for ApacheKafkaSubscriptionRequest.
 type ApacheKafkaSubscriptionRequest struct {
 	// Config Implementation-specific configuration parameters needed by the subscription manager for acquiring events.
@@ -175,11 +259,17 @@ type ApacheKafkaSubscriptionRequest struct {
 	// Note: if a request is performed for several event type, all subscribed event will use same `config` parameters.
 	Config Config `json:"config"`
 
-	// Protocol Identifier of a delivery protocol. Only HTTP is allowed for now
+	// Protocol Identifier of a delivery protocol. HTTP, MQTT3, MQTT5, KAFKA, AMQP and NATS are allowed
 	Protocol         Protocol             `json:"protocol"`
 	ProtocolSettings *ApacheKafkaSettings `json:"protocolSettings,omitempty"`
 
-	// Sink The address to which events shall be delivered using the selected protocol.
+	// Sink The address to which events shall be delivered using the selected protocol:
+	// an `https` URL for `HTTP`, the `mqtt` or `mqtts` URL of the broker for `MQTT3` and `MQTT5`,
+	// a `kafka` or `kafkas` URL with the comma-separated bootstrap brokers for `KAFKA`,
+	// the `amqp` or `amqps` URL of the container for `AMQP`,
+	// a `nats` or `tls` URL with the comma-separated servers for `NATS`.
+	// A subscription with a `sinkCredential` requires a TLS scheme (`https`, `mqtts`, `kafkas`, `amqps` or `tls`),
+	// unless the sink is a service internal to the cluster of the implementation, not to send the credential in cleartext.
 	Sink string `json:"sink"`
 
 	// SinkCredential A sink credential provides authentication or authorization information necessary to enable delivery of events to a target.
@@ -196,6 +286,39 @@ type ApacheKafkaSubscriptionRequest struct {
 // instantiation in the Edge Cloud Zone is successful
 type AppInstanceId = openapi_types.UUID
 
+// ApplicationBreakdown Share of the result attributed to an application instance.
+type ApplicationBreakdown struct {
+	// AppInstanceId A globally unique identifier associated with a running
+	// instance of an application.
+	// Edge Cloud Platform generates this identifier when the
+	// instantiation in the Edge Cloud Zone is successful
+	AppInstanceId AppInstanceId `json:"appInstanceId"`
+
+	// Application Value of the application instance alone.
+	Application     float64                   `json:"application"`
+	NetworkElements []NetworkElementBreakdown `json:"networkElements"`
+
+	// Total Share of the result attributed to the application instance, i.e. its own value plus the share allocated to it by every network element.
+	Total float64 `json:"total"`
+}
+
+// ApplicationTopology Topology resolved by the orchestrator for an application instance.
+type ApplicationTopology struct {
+	// AppInstanceId A globally unique identifier associated with a running
+	// instance of an application.
+	// Edge Cloud Platform generates this identifier when the
+	// instantiation in the Edge Cloud Zone is successful
+	AppInstanceId AppInstanceId `json:"appInstanceId"`
+	Error         *ErrorInfo    `json:"error,omitempty"`
+
+	// InfraType Type of the infrastructure hosting the application instance.
+	InfraType *string `json:"infraType,omitempty"`
+
+	// IpList Externally reachable IPs of the application instance.
+	IpList          *[]string                 `json:"ipList,omitempty"`
+	NetworkElements *[]NetworkElementTopology `json:"networkElements,omitempty"`
+}
+
 // CloudEvent The notification callback
 type CloudEvent struct {
 	// Data Event details payload described in each CAMARA API and referenced by its type
@@ -264,7 +387,37 @@ type Config struct {
 }
 
 // CreateSubscriptionDetail The detail of the requested event subscription.
-type CreateSubscriptionDetail = map[string]interface{}
+type CreateSubscriptionDetail struct {
+	// AllowPartialResults If true, data that cannot be retrieved for some application instances or network elements does not fail the report. The result is calculated with the data that is available and comes with its `coverage`. Partial results may also be enabled for all reports by the API Provider.
+	AllowPartialResults *bool `json:"allowPartialResults,omitempty"`
+
+	// IncludeBreakdown If true, the notification and the report include the breakdown of the result per application instance and per network element.
+	IncludeBreakdown *bool `json:"includeBreakdown,omitempty"`
+
+	// ReportingPeriod Turns the request into a periodic report. The report is calculated
+	// again at every period over the period that just elapsed, and each
+	// result is sent as a new notification, until `subscriptionExpireTime`
+	// or `subscriptionMaxEvents` is reached. At least one of them is
+	// required and `timePeriod` must not be set.
+	// The first run happens when the request is accepted if `initialEvent`
+	// is true, one period later otherwise.
+	ReportingPeriod *ReportingPeriod `json:"reportingPeriod,omitempty"`
+}
+
+// DataCoverage Share of the data a result is based on, and the data missing from it or estimated. Only provided for results calculated with partial results allowed, or with estimated data.
+type DataCoverage struct {
+	// EstimatedNetworkElements Network elements whose traffic was missing from the traffic volume data. Their share is included in the result, as estimated by the method given.
+	EstimatedNetworkElements []EstimatedNetworkElement `json:"estimatedNetworkElements"`
+
+	// MissingApplications Application instances whose own energy consumption could not be retrieved. The share of their network elements is still included when available.
+	MissingApplications []AppInstanceId `json:"missingApplications"`
+
+	// MissingNetworkElements Network elements whose energy or traffic could not be retrieved. They are left out of the result.
+	MissingNetworkElements []MissingNetworkElement `json:"missingNetworkElements"`
+
+	// Ratio Ratio between the measurements the result is based on and the expected ones. The energy consumption of an application instance counts as one measurement, and so do the energy and traffic of a network element together. A network element whose traffic was estimated does not count as measured.
+	Ratio float64 `json:"ratio"`
+}
 
 // DateTime Timestamp of when the occurrence happened. Must adhere to RFC 3339.
 // WARN: This optional field in CloudEvents specification is required in
@@ -283,9 +436,75 @@ type ErrorInfo struct {
 	Status int `json:"status"`
 }
 
+// EstimatedFanOut Number of backend calls and internal events the report would cause, for the application instances whose topology has been resolved. The data of a long time period is fetched in several windows, each counted separately. For a periodic report, the counts apply to each run.
+type EstimatedFanOut struct {
+	// BackendCalls Sum of the calls to all the backends.
+	BackendCalls            int `json:"backendCalls"`
+	CloudObservabilityCalls int `json:"cloudObservabilityCalls"`
+
+	// Events Internal events exchanged between the services.
+	Events             int `json:"events"`
+	OrchestratorCalls  int `json:"orchestratorCalls"`
+	TrafficVolumeCalls int `json:"trafficVolumeCalls"`
+}
+
+// EstimatedNetworkElement A network element whose share of a result is estimated.
+type EstimatedNetworkElement struct {
+	// AppInstanceId A globally unique identifier associated with a running
+	// instance of an application.
+	// Edge Cloud Platform generates this identifier when the
+	// instantiation in the Edge Cloud Zone is successful
+	AppInstanceId AppInstanceId `json:"appInstanceId"`
+
+	// Method How the traffic share was estimated:
+	// - `zero`: no share of the network element is allocated to the application instance.
+	// - `equal-split`: the network element is split equally between the application instances of the report it serves.
+	Method EstimatedNetworkElementMethod `json:"method"`
+
+	// NetworkElementId Identifier of the network element.
+	NetworkElementId string `json:"networkElementId"`
+
+	// VendorId Identifier of the vendor of the network element, network elements of different vendors may share an identifier.
+	VendorId *string `json:"vendorId,omitempty"`
+}
+
+// EstimatedNetworkElementMethod How the traffic share was estimated:
+// - `zero`: no share of the network element is allocated to the application instance.
+// - `equal-split`: the network element is split equally between the application instances of the report it serves.
+type EstimatedNetworkElementMethod string
+
 // EventTypeNotification Event triggered when an event-type event occurred.
 type EventTypeNotification string
 
+// FailureReason Why a report failed.
+type FailureReason struct {
+	// Cause Cause of the failure:
+	// - `backend-error`: a backend returned an error that cannot be retried (permanent, not found or unauthorized).
+	// - `retries-exhausted`: an event could not be processed after all retries.
+	// - `timeout`: the report did not complete in time, even after its missing data was requested again.
+	Cause FailureReasonCause `json:"cause"`
+
+	// EventType Type of the event whose processing failed, if any.
+	EventType *string `json:"eventType,omitempty"`
+
+	// Stage Processing status of a report:
+	// - `created`: the report has been accepted but processing has not started yet.
+	// - `gathering`: energy and traffic data of the application instances is being gathered.
+	//   A periodic report stays in this status while its runs are started.
+	// - `calculating`: all data has been gathered and the result is being calculated.
+	// - `notifying`: the result has been calculated and is being sent to the sink.
+	// - `completed`: the result has been calculated and notified.
+	// - `failed`: the report could not be calculated, see `error` and `failure`.
+	// - `cancelled`: the report has been cancelled by the API Consumer.
+	Stage ReportStatus `json:"stage"`
+}
+
+// FailureReasonCause Cause of the failure:
+// - `backend-error`: a backend returned an error that cannot be retried (permanent, not found or unauthorized).
+// - `retries-exhausted`: an event could not be processed after all retries.
+// - `timeout`: the report did not complete in time, even after its missing data was requested again.
+type FailureReasonCause string
+
 // HTTPSettings defines model for HTTPSettings.
 type HTTPSettings struct {
 	// Headers A set of key/value pairs that is copied into the HTTP request as custom headers.
@@ -300,12 +519,22 @@ type HTTPSettings struct {
 // HTTPSettingsMethod The HTTP method to use for sending the message.
 type HTTPSettingsMethod string
 
-// MQTTSettings defines model for MQTTSettings.
+// MQTTSettings The client authenticates with the `sinkCredential` as user name and password: the identifier and the secret
+// of a `PLAIN` credential, or the `accessTokenType` (`bearer`) and the access token of an `ACCESSTOKEN` credential.
 type MQTTSettings struct {
-	Expiry         *int32                  `json:"expiry,omitempty"`
-	Qos            *int32                  `json:"qos,omitempty"`
-	Retain         *bool                   `json:"retain,omitempty"`
-	TopicName      string                  `json:"topicName"`
+	// Expiry Message expiry interval, in seconds. MQTT5 only.
+	Expiry *int32 `json:"expiry,omitempty"`
+
+	// Qos QoS the notifications are published with. A notification is delivered once acknowledged by the broker with QoS 1 or 2. Defaults to 1.
+	Qos *int32 `json:"qos,omitempty"`
+
+	// Retain Whether the broker retains the last notification of the topic.
+	Retain *bool `json:"retain,omitempty"`
+
+	// TopicName Topic the notifications are published to. It cannot contain wildcards.
+	TopicName string `json:"topicName"`
+
+	// UserProperties User properties added to the notifications. MQTT5 only.
 	UserProperties *map[string]interface{} `json:"userProperties,omitempty"`
 }
 
@@ -317,11 +546,20 @@ type MQTTSubscriptionRequest struct {
 	// Note: if a request is performed for several event type, all subscribed event will use same `config` parameters.
 	Config Config `json:"config"`
 
-	// Protocol Identifier of a delivery protocol. Only HTTP is allowed for now
-	Protocol         Protocol      `json:"protocol"`
+	// Protocol Identifier of a delivery protocol. HTTP, MQTT3, MQTT5, KAFKA, AMQP and NATS are allowed
+	Protocol Protocol `json:"protocol"`
+
+	// ProtocolSettings The client authenticates with the `sinkCredential` as user name and password: the identifier and the secret
+	// of a `PLAIN` credential, or the `accessTokenType` (`bearer`) and the access token of an `ACCESSTOKEN` credential.
 	ProtocolSettings *MQTTSettings `json:"protocolSettings,omitempty"`
 
-	// Sink The address to which events shall be delivered using the selected protocol.
+	// Sink The address to which events shall be delivered using the selected protocol:
+	// an `https` URL for `HTTP`, the `mqtt` or `mqtts` URL of the broker for `MQTT3` and `MQTT5`,
+	// a `kafka` or `kafkas` URL with the comma-separated bootstrap brokers for `KAFKA`,
+	// the `amqp` or `amqps` URL of the container for `AMQP`,
+	// a `nats` or `tls` URL with the comma-separated servers for `NATS`.
+	// A subscription with a `sinkCredential` requires a TLS scheme (`https`, `mqtts`, `kafkas`, `amqps` or `tls`),
+	// unless the sink is a service internal to the cluster of the implementation, not to send the credential in cleartext.
 	Sink string `json:"sink"`
 
 	// SinkCredential A sink credential provides authentication or authorization information necessary to enable delivery of events to a target.
@@ -332,8 +570,29 @@ type MQTTSubscriptionRequest struct {
 	Types []SubscriptionEventType `json:"types"`
 }
 
+// MissingNetworkElement A network element left out of a result.
+type MissingNetworkElement struct {
+	// AppInstanceId A globally unique identifier associated with a running
+	// instance of an application.
+	// Edge Cloud Platform generates this identifier when the
+	// instantiation in the Edge Cloud Zone is successful
+	AppInstanceId AppInstanceId `json:"appInstanceId"`
+
+	// NetworkElementId Identifier of the network element.
+	NetworkElementId string `json:"networkElementId"`
+
+	// Reason Why the network element data could not be retrieved.
+	Reason string `json:"reason"`
+
+	// VendorId Identifier of the vendor of the network element, network elements of different vendors may share an identifier.
+	VendorId *string `json:"vendorId,omitempty"`
+}
+
 // NATSSettings defines model for NATSSettings.
 type NATSSettings struct {
+	// Subject Subject the notifications are published to. It cannot contain wildcards. When a JetStream stream captures it,
+	// a notification is delivered once the stream acknowledges it; otherwise, or when the `sinkCredential` is not
+	// allowed the JetStream API, once the server receives it.
 	Subject string `json:"subject"`
 }
 
@@ -345,11 +604,17 @@ type NATSSubscriptionRequest struct {
 	// Note: if a request is performed for several event type, all subscribed event will use same `config` parameters.
 	Config Config `json:"config"`
 
-	// Protocol Identifier of a delivery protocol. Only HTTP is allowed for now
+	// Protocol Identifier of a delivery protocol. HTTP, MQTT3, MQTT5, KAFKA, AMQP and NATS are allowed
 	Protocol         Protocol      `json:"protocol"`
 	ProtocolSettings *NATSSettings `json:"protocolSettings,omitempty"`
 
-	// Sink The address to which events shall be delivered using the selected protocol.
+	// Sink The address to which events shall be delivered using the selected protocol:
+	// an `https` URL for `HTTP`, the `mqtt` or `mqtts` URL of the broker for `MQTT3` and `MQTT5`,
+	// a `kafka` or `kafkas` URL with the comma-separated bootstrap brokers for `KAFKA`,
+	// the `amqp` or `amqps` URL of the container for `AMQP`,
+	// a `nats` or `tls` URL with the comma-separated servers for `NATS`.
+	// A subscription with a `sinkCredential` requires a TLS scheme (`https`, `mqtts`, `kafkas`, `amqps` or `tls`),
+	// unless the sink is a service internal to the cluster of the implementation, not to send the credential in cleartext.
 	Sink string `json:"sink"`
 
 	// SinkCredential A sink credential provides authentication or authorization information necessary to enable delivery of events to a target.
@@ -360,6 +625,32 @@ type NATSSubscriptionRequest struct {
 	Types []SubscriptionEventType `json:"types"`
 }
 
+// NetworkElementBreakdown Share of a network element allocated to an application instance, proportionally to the traffic of the application instance.
+type NetworkElementBreakdown struct {
+	// Allocated Value of the network element allocated to the application instance.
+	Allocated float64 `json:"allocated"`
+
+	// Estimated True when the traffic of the network element was missing from the traffic volume data, in at least one window, and the share allocated was estimated instead of measured.
+	Estimated *bool `json:"estimated,omitempty"`
+
+	// NetworkElementId Identifier of the network element.
+	NetworkElementId string `json:"networkElementId"`
+
+	// TrafficShare Ratio between the traffic of the application instance and the total traffic of the network element. When the traffic was estimated in every window, the estimated share.
+	TrafficShare float64 `json:"trafficShare"`
+
+	// VendorId Identifier of the vendor of the network element, network elements of different vendors may share an identifier.
+	VendorId *string `json:"vendorId,omitempty"`
+}
+
+// NetworkElementTopology Network element serving an application instance.
+type NetworkElementTopology struct {
+	InfraType  *string `json:"infraType,omitempty"`
+	InstanceId string  `json:"instanceId"`
+	NetworkId  *string `json:"networkId,omitempty"`
+	VendorId   *string `json:"vendorId,omitempty"`
+}
+
 // PlainCredential defines model for PlainCredential.
 type PlainCredential struct {
 	// CredentialType The type of the credential.
@@ -377,7 +668,7 @@ type PlainCredential struct {
 // Note: Type of the credential - MUST be set to ACCESSTOKEN for now
 type PlainCredentialCredentialType string
 
-// Protocol Identifier of a delivery protocol. Only HTTP is allowed for now
+// Protocol Identifier of a delivery protocol. HTTP, MQTT3, MQTT5, KAFKA, AMQP and NATS are allowed
 type Protocol string
 
 // RefreshTokenCredential defines model for RefreshTokenCredential.
@@ -412,9 +703,78 @@ type RefreshTokenCredentialAccessTokenType string
 // Note: Type of the credential - MUST be set to ACCESSTOKEN for now
 type RefreshTokenCredentialCredentialType string
 
+// Report Status and result of a report created through one of the calculate endpoints.
+type Report struct {
+	// Breakdown Split of the result per application instance and, for each of them, per network element. Values are expressed in the same unit as the result. Only provided when `includeBreakdown` is requested in the `subscriptionDetail`.
+	Breakdown *ResultBreakdown `json:"breakdown,omitempty"`
+
+	// CarbonFootprint The carbon footprint for all the instances of the service, in tonnes. Only present once the result of a carbon footprint report has been calculated.
+	CarbonFootprint *float64 `json:"carbonFootprint,omitempty"`
+
+	// CompletedAt Time at which the report reached a final status.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+
+	// Coverage Share of the data a result is based on, and the data missing from it or estimated. Only provided for results calculated with partial results allowed, or with estimated data.
+	Coverage *DataCoverage `json:"coverage,omitempty"`
+
+	// CreatedAt Time at which the report was created.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// EnergyConsumption The energy consumption for all the instances of the service, in Kw/h. Only present once the result of an energy consumption report has been calculated.
+	EnergyConsumption *float64   `json:"energyConsumption,omitempty"`
+	Error             *ErrorInfo `json:"error,omitempty"`
+
+	// Failure Why a report failed.
+	Failure *FailureReason `json:"failure,omitempty"`
+
+	// GatheredApplications Number of application instances whose energy data has been fully gathered.
+	GatheredApplications *int `json:"gatheredApplications,omitempty"`
+
+	// Kind Kind of a report, matching the endpoint used to create it:
+	// - `energy-consumption`: created with `/calculate-energy-consumption`.
+	// - `carbon-footprint`: created with `/calculate-carbon-footprint`.
+	Kind *ReportKind `json:"kind,omitempty"`
+
+	// NextRunAt Time of the next run. Only present for periodic reports that have not ended.
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+
+	// ParentRequestId Identifier of the periodic report this report is a run of. Only present for the runs of a periodic report.
+	ParentRequestId *string `json:"parentRequestId,omitempty"`
+
+	// RequestId Identifier for the request.
+	RequestId string `json:"requestId"`
+
+	// Runs Number of runs started so far. Only present for periodic reports.
+	Runs *int `json:"runs,omitempty"`
+
+	// Service list of Application Instance Identifiers under analysis.
+	Service *[]AppInstanceId `json:"service,omitempty"`
+
+	// Status Processing status of a report:
+	// - `created`: the report has been accepted but processing has not started yet.
+	// - `gathering`: energy and traffic data of the application instances is being gathered.
+	//   A periodic report stays in this status while its runs are started.
+	// - `calculating`: all data has been gathered and the result is being calculated.
+	// - `notifying`: the result has been calculated and is being sent to the sink.
+	// - `completed`: the result has been calculated and notified.
+	// - `failed`: the report could not be calculated, see `error` and `failure`.
+	// - `cancelled`: the report has been cancelled by the API Consumer.
+	Status ReportStatus `json:"status"`
+
+	// StatusHistory Status changes of the report, oldest first.
+	StatusHistory *[]StatusChange `json:"statusHistory,omitempty"`
+	TimePeriod    *TimePeriod     `json:"timePeriod,omitempty"`
+
+	// TotalApplications Number of application instances under analysis.
+	TotalApplications *int `json:"totalApplications,omitempty"`
+
+	// UpdatedAt Time of the last status change of the report.
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
 // ReportCreationRequest resource containing the service under analysis and the callback information for the API Consumer to be notified with the results of the analysis. If no "timePeriod" is provided the analysis is performed from the activation of the first instance of the Application.
 type ReportCreationRequest struct {
-	// RequestId Identifier for the request. This parameter is returned by the API and must be used to update it.
+	// RequestId Identifier for the request. This parameter is returned by the API and must be used to update it. It can be supplied by the API Consumer to make the submission idempotent, otherwise it is generated by the API. A supplied value is scoped to the API Consumer, the API returning the identifier of the report derived from it.
 	RequestId *string `json:"requestId,omitempty"`
 
 	// Service list of Application Instance Identifiers. This are the instances of the applications producing the service under analysis.
@@ -425,6 +785,55 @@ type ReportCreationRequest struct {
 	TimePeriod          *TimePeriod         `json:"timePeriod,omitempty"`
 }
 
+// ReportKind Kind of a report, matching the endpoint used to create it:
+// - `energy-consumption`: created with `/calculate-energy-consumption`.
+// - `carbon-footprint`: created with `/calculate-carbon-footprint`.
+type ReportKind string
+
+// ReportList A page of reports.
+type ReportList struct {
+	// NextCursor Cursor to pass as `cursor` to fetch the next page. Absent when there are no more reports.
+	NextCursor *string  `json:"nextCursor,omitempty"`
+	Reports    []Report `json:"reports"`
+}
+
+// ReportStatus Processing status of a report:
+//   - `created`: the report has been accepted but processing has not started yet.
+//   - `gathering`: energy and traffic data of the application instances is being gathered.
+//     A periodic report stays in this status while its runs are started.
+//   - `calculating`: all data has been gathered and the result is being calculated.
+//   - `notifying`: the result has been calculated and is being sent to the sink.
+//   - `completed`: the result has been calculated and notified.
+//   - `failed`: the report could not be calculated, see `error` and `failure`.
+//   - `cancelled`: the report has been cancelled by the API Consumer.
+type ReportStatus string
+
+// ReportValidation Outcome of the dry run of a report request.
+type ReportValidation struct {
+	Applications []ApplicationTopology `json:"applications"`
+
+	// EstimatedFanOut Number of backend calls and internal events the report would cause, for the application instances whose topology has been resolved. The data of a long time period is fetched in several windows, each counted separately. For a periodic report, the counts apply to each run.
+	EstimatedFanOut EstimatedFanOut `json:"estimatedFanOut"`
+	TimePeriod      *TimePeriod     `json:"timePeriod,omitempty"`
+
+	// Valid True when the topology of every application instance has been resolved.
+	Valid bool `json:"valid"`
+}
+
+// ReportingPeriod Turns the request into a periodic report. The report is calculated
+// again at every period over the period that just elapsed, and each
+// result is sent as a new notification, until `subscriptionExpireTime`
+// or `subscriptionMaxEvents` is reached. At least one of them is
+// required and `timePeriod` must not be set.
+// The first run happens when the request is accepted if `initialEvent`
+// is true, one period later otherwise.
+type ReportingPeriod string
+
+// ResultBreakdown Split of the result per application instance and, for each of them, per network element. Values are expressed in the same unit as the result. Only provided when `includeBreakdown` is requested in the `subscriptionDetail`.
+type ResultBreakdown struct {
+	Applications []ApplicationBreakdown `json:"applications"`
+}
+
 // Source Identifies the context in which an event happened - be a non-empty
 // `URI-reference` like:
 // - URI with a DNS authority:
@@ -439,6 +848,23 @@ type ReportCreationRequest struct {
 //   - 1-555-123-4567
 type Source = string
 
+// StatusChange A change of the status of a report.
+type StatusChange struct {
+	// At Time of the change.
+	At time.Time `json:"at"`
+
+	// Status Processing status of a report:
+	// - `created`: the report has been accepted but processing has not started yet.
+	// - `gathering`: energy and traffic data of the application instances is being gathered.
+	//   A periodic report stays in this status while its runs are started.
+	// - `calculating`: all data has been gathered and the result is being calculated.
+	// - `notifying`: the result has been calculated and is being sent to the sink.
+	// - `completed`: the result has been calculated and notified.
+	// - `failed`: the report could not be calculated, see `error` and `failure`.
+	// - `cancelled`: the report has been cancelled by the API Consumer.
+	Status ReportStatus `json:"status"`
+}
+
 // SubscriptionEventType event-type that could be subscribed through this subscription. Several event-type could be defined.
 type SubscriptionEventType string
 
@@ -454,6 +880,15 @@ type TimePeriod struct {
 // XCorrelator defines model for XCorrelator.
 type XCorrelator = string
 
+// IdempotencyKey defines model for idempotency-key.
+type IdempotencyKey = string
+
+// Prefer defines model for prefer.
+type Prefer = string
+
+// RequestId defines model for requestId.
+type RequestId = string
+
 // Generic400 defines model for Generic400.
 type Generic400 struct {
 	Code interface{} `json:"code"`
@@ -530,12 +965,75 @@ type Generic429 struct {
 type CalculateCarbonFootprintParams struct {
 	// XCorrelator Correlation id for the different services
 	XCorrelator *XCorrelator `json:"x-correlator,omitempty"`
+
+	// IdempotencyKey Key making the submission idempotent for the API Consumer. Retrying a submission with the same key and the same body returns the original response without creating a new report, while reusing the key with a different body is rejected with a 409 error. A retry while the original submission is still being processed is rejected with a 409 ABORTED error, unless that submission was interrupted, in which case the retry resumes it. When absent, the `requestId` supplied in the body, if any, is used as the key.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// Prefer Asks for the report to be calculated synchronously, waiting at most the given number of seconds, as in `wait=30` (RFC 7240). The preference is honoured only for small one-shot requests; when the report cannot be calculated in time, the request falls back to the asynchronous flow and a 201 response is returned.
+	Prefer *Prefer `json:"Prefer,omitempty"`
 }
 
 // CalculateEnergyConsumptionParams defines parameters for CalculateEnergyConsumption.
 type CalculateEnergyConsumptionParams struct {
 	// XCorrelator Correlation id for the different services
 	XCorrelator *XCorrelator `json:"x-correlator,omitempty"`
+
+	// IdempotencyKey Key making the submission idempotent for the API Consumer. Retrying a submission with the same key and the same body returns the original response without creating a new report, while reusing the key with a different body is rejected with a 409 error. A retry while the original submission is still being processed is rejected with a 409 ABORTED error, unless that submission was interrupted, in which case the retry resumes it. When absent, the `requestId` supplied in the body, if any, is used as the key.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+
+	// Prefer Asks for the report to be calculated synchronously, waiting at most the given number of seconds, as in `wait=30` (RFC 7240). The preference is honoured only for small one-shot requests; when the report cannot be calculated in time, the request falls back to the asynchronous flow and a 201 response is returned.
+	Prefer *Prefer `json:"Prefer,omitempty"`
+}
+
+// ListReportsParams defines parameters for ListReports.
+type ListReportsParams struct {
+	// Status Only return reports with this status.
+	Status *ReportStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Kind Only return reports of this kind.
+	Kind *ReportKind `form:"kind,omitempty" json:"kind,omitempty"`
+
+	// CreatedFrom Only return reports created at or after this instant. It must follow RFC 3339 and must have time zone.
+	CreatedFrom *time.Time `form:"createdFrom,omitempty" json:"createdFrom,omitempty"`
+
+	// CreatedTo Only return reports created before this instant. It must follow RFC 3339 and must have time zone.
+	CreatedTo *time.Time `form:"createdTo,omitempty" json:"createdTo,omitempty"`
+
+	// AppInstanceId Only return reports including this application instance.
+	AppInstanceId *AppInstanceId `form:"appInstanceId,omitempty" json:"appInstanceId,omitempty"`
+
+	// ParentRequestId Only return the runs of this periodic report.
+	ParentRequestId *string `form:"parentRequestId,omitempty" json:"parentRequestId,omitempty"`
+
+	// Limit Maximum number of reports to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor returned as `nextCursor` by a previous call, used to fetch the next page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// XCorrelator Correlation id for the different services
+	XCorrelator *XCorrelator `json:"x-correlator,omitempty"`
+}
+
+// CancelReportParams defines parameters for CancelReport.
+type CancelReportParams struct {
+	// XCorrelator Correlation id for the different services
+	XCorrelator *XCorrelator `json:"x-correlator,omitempty"`
+}
+
+// GetReportParams defines parameters for GetReport.
+type GetReportParams struct {
+	// XCorrelator Correlation id for the different services
+	XCorrelator *XCorrelator `json:"x-correlator,omitempty"`
+}
+
+// ValidateReportParams defines parameters for ValidateReport.
+type ValidateReportParams struct {
+	// Kind Kind of report the request would be submitted for.
+	Kind ReportKind `form:"kind" json:"kind"`
+
+	// XCorrelator Correlation id for the different services
+	XCorrelator *XCorrelator `json:"x-correlator,omitempty"`
 }
 
 // CalculateCarbonFootprintJSONRequestBody defines body for CalculateCarbonFootprint for application/json ContentType.
@@ -543,3 +1041,6 @@ type CalculateCarbonFootprintJSONRequestBody = ReportCreationRequest
 
 // CalculateEnergyConsumptionJSONRequestBody defines body for CalculateEnergyConsumption for application/json ContentType.
 type CalculateEnergyConsumptionJSONRequestBody = ReportCreationRequest
+
+// ValidateReportJSONRequestBody defines body for ValidateReport for application/json ContentType.
+type ValidateReportJSONRequestBody = ReportCreationRequest
@@ -16,7 +16,13 @@ limitations under the License.
 package models
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 )
 
 // Manual polymorphic types implementation.
@@ -32,7 +38,9 @@ import (
 //
 // Currently, only the following variants are supported:
 //   - SinkCredential with credentialType = "ACCESSTOKEN" (provides bearer token elsewhere in spec)
-//   - SubscriptionRequest with protocol = "HTTP"
+//     or "PLAIN" (identifier and secret, MQTT only)
+//   - SubscriptionRequest with protocol = "HTTP", "MQTT3", "MQTT5", "KAFKA", "AMQP" or "NATS",
+//     whose protocolSettings are decoded according to the protocol
 //
 // If future generator releases support these discriminators natively, this file
 // can be removed and exclusion entries deleted.
@@ -49,9 +57,18 @@ type SubscriptionRequest struct {
 	// Note: if a request is performed for several event type, all subscribed event will use same `config` parameters.
 	Config Config `json:"config" bson:"config"`
 
-	// Protocol Identifier of a delivery protocol. Only HTTP is allowed for now
-	Protocol         Protocol      `json:"protocol" bson:"protocol"`
+	// Protocol Identifier of a delivery protocol.
+	Protocol Protocol `json:"protocol" bson:"protocol"`
+	// ProtocolSettings are the protocolSettings of an HTTP subscription.
 	ProtocolSettings *HTTPSettings `json:"protocolSettings,omitempty" bson:"protocolSettings,omitempty"`
+	// MQTTSettings are the protocolSettings of an MQTT3 or MQTT5 subscription.
+	MQTTSettings *MQTTSettings `json:"-" bson:"mqttSettings,omitempty"`
+	// KafkaSettings are the protocolSettings of a KAFKA subscription.
+	KafkaSettings *ApacheKafkaSettings `json:"-" bson:"kafkaSettings,omitempty"`
+	// AMQPSettings are the protocolSettings of an AMQP subscription.
+	AMQPSettings *AMQPSettings `json:"-" bson:"amqpSettings,omitempty"`
+	// NATSSettings are the protocolSettings of a NATS subscription.
+	NATSSettings *NATSSettings `json:"-" bson:"natsSettings,omitempty"`
 
 	// Sink The address to which events shall be delivered using the selected protocol.
 	Sink string `json:"sink" bson:"sink"`
@@ -64,12 +81,75 @@ type SubscriptionRequest struct {
 	Types []SubscriptionEventType `json:"types" bson:"types"`
 }
 
+// subscriptionRequestFields has the fields of SubscriptionRequest without its JSON methods.
+type subscriptionRequestFields SubscriptionRequest
+
+// UnmarshalJSON decodes the protocolSettings into the settings of the protocol of the subscription.
+// The settings of a protocol that is not implemented are decoded as HTTP ones, and rejected by
+// ValidateProtocol.
+func (sr *SubscriptionRequest) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		*subscriptionRequestFields
+		ProtocolSettings json.RawMessage `json:"protocolSettings,omitempty"`
+	}{subscriptionRequestFields: (*subscriptionRequestFields)(sr)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	sr.ProtocolSettings, sr.MQTTSettings, sr.KafkaSettings, sr.AMQPSettings, sr.NATSSettings = nil, nil, nil, nil, nil
+	if len(raw.ProtocolSettings) == 0 || string(raw.ProtocolSettings) == "null" {
+		return nil
+	}
+	switch sr.Protocol {
+	case MQTT3, MQTT5:
+		sr.MQTTSettings = &MQTTSettings{}
+		return json.Unmarshal(raw.ProtocolSettings, sr.MQTTSettings)
+	case KAFKA:
+		sr.KafkaSettings = &ApacheKafkaSettings{}
+		return json.Unmarshal(raw.ProtocolSettings, sr.KafkaSettings)
+	case AMQP:
+		sr.AMQPSettings = &AMQPSettings{}
+		return json.Unmarshal(raw.ProtocolSettings, sr.AMQPSettings)
+	case NATS:
+		sr.NATSSettings = &NATSSettings{}
+		return json.Unmarshal(raw.ProtocolSettings, sr.NATSSettings)
+	default:
+		sr.ProtocolSettings = &HTTPSettings{}
+		return json.Unmarshal(raw.ProtocolSettings, sr.ProtocolSettings)
+	}
+}
+
+// MarshalJSON encodes the settings of the protocol of the subscription as its protocolSettings.
+func (sr SubscriptionRequest) MarshalJSON() ([]byte, error) {
+	out := struct {
+		subscriptionRequestFields
+		ProtocolSettings any `json:"protocolSettings,omitempty"`
+	}{subscriptionRequestFields: subscriptionRequestFields(sr)}
+	switch {
+	case sr.MQTTSettings != nil:
+		out.ProtocolSettings = sr.MQTTSettings
+	case sr.KafkaSettings != nil:
+		out.ProtocolSettings = sr.KafkaSettings
+	case sr.AMQPSettings != nil:
+		out.ProtocolSettings = sr.AMQPSettings
+	case sr.NATSSettings != nil:
+		out.ProtocolSettings = sr.NATSSettings
+	case sr.ProtocolSettings != nil:
+		out.ProtocolSettings = sr.ProtocolSettings
+	}
+	return json.Marshal(out)
+}
+
 // SinkCredential A sink credential provides authentication or authorization information necessary to enable delivery of events to a target.
 type SinkCredential struct {
 	// CredentialType The type of the credential.
-	// Note: Type of the credential - MUST be set to ACCESSTOKEN for now
 	CredentialType SinkCredentialCredentialType `json:"credentialType"`
 	AccessTokenCredential
+
+	// Identifier The identifier of a PLAIN credential, an account or username.
+	Identifier string `json:"identifier,omitempty"`
+
+	// Secret The secret of a PLAIN credential, a password or passphrase.
+	Secret string `json:"secret,omitempty"`
 }
 
 // SinkCredentialCredentialType The type of the credential.
@@ -82,15 +162,21 @@ const (
 	SinkCredentialCredentialTypeREFRESHTOKEN SinkCredentialCredentialType = "REFRESHTOKEN"
 )
 
-// Validate enforces only ACCESSTOKEN is currently supported and required fields present.
+// Validate enforces only ACCESSTOKEN and PLAIN are currently supported and required fields present.
 func (sc *SinkCredential) Validate() error {
 	if sc == nil {
 		return nil
 	}
-	if sc.CredentialType != SinkCredentialCredentialTypeACCESSTOKEN {
-		return fmt.Errorf("sink credential type '%s' not implemented (only ACCESSTOKEN supported)", sc.CredentialType)
+	switch sc.CredentialType {
+	case SinkCredentialCredentialTypeACCESSTOKEN:
+		return nil
+	case SinkCredentialCredentialTypePLAIN:
+		if sc.Identifier == "" || sc.Secret == "" {
+			return fmt.Errorf("sink credential type '%s' requires an identifier and a secret", sc.CredentialType)
+		}
+		return nil
 	}
-	return nil
+	return fmt.Errorf("sink credential type '%s' not implemented (only ACCESSTOKEN and PLAIN supported)", sc.CredentialType)
 }
 
 // AuthorizationHeader builds Authorization header value if valid.
@@ -107,10 +193,190 @@ func (sc *SinkCredential) AuthorizationHeader() (string, bool) {
 	return "Bearer " + sc.AccessToken, true
 }
 
-// ValidateProtocol enforces only HTTP protocol supported in subscription.
+// ValidateProtocol enforces only HTTP, MQTT3, MQTT5, KAFKA, AMQP and NATS protocols supported in
+// subscription, with the credential types each of them implements.
 func (sr *SubscriptionRequest) ValidateProtocol() error {
-	if sr.Protocol != HTTP {
-		return fmt.Errorf("subscription protocol '%s' not implemented; only HTTP supported", sr.Protocol)
+	switch sr.Protocol {
+	case HTTP:
+		if sr.SinkCredential != nil && sr.SinkCredential.CredentialType == SinkCredentialCredentialTypePLAIN {
+			return fmt.Errorf("sink credential type '%s' not implemented for protocol '%s'", sr.SinkCredential.CredentialType, sr.Protocol)
+		}
+		return nil
+	case MQTT3, MQTT5, KAFKA, AMQP, NATS:
+		return nil
+	}
+	return fmt.Errorf("subscription protocol '%s' not implemented; only HTTP, MQTT3, MQTT5, KAFKA, AMQP and NATS supported", sr.Protocol)
+}
+
+// ValidateProtocolSettings checks the sink and the protocolSettings of a subscription against its protocol.
+// The protocol is expected to be implemented.
+func (sr *SubscriptionRequest) ValidateProtocolSettings() error {
+	sink, err := url.Parse(sr.Sink)
+	if err != nil {
+		return fmt.Errorf("invalid sink: %w", err)
+	}
+	if err := sr.validateCredentialTransport(sink); err != nil {
+		return err
+	}
+	switch sr.Protocol {
+	case MQTT3, MQTT5:
+		if sink.Scheme != "mqtt" && sink.Scheme != "mqtts" {
+			return fmt.Errorf("sink of protocol '%s' must be an mqtt or mqtts URL", sr.Protocol)
+		}
+		return sr.MQTTSettings.validate()
+	case KAFKA:
+		if sink.Scheme != "kafka" && sink.Scheme != "kafkas" {
+			return fmt.Errorf("sink of protocol '%s' must be a kafka or kafkas URL", sr.Protocol)
+		}
+		for _, broker := range strings.Split(sink.Host, ",") {
+			if broker == "" {
+				return fmt.Errorf("sink of protocol '%s' must list its bootstrap brokers", sr.Protocol)
+			}
+		}
+		return sr.KafkaSettings.validate()
+	case AMQP:
+		if sink.Scheme != "amqp" && sink.Scheme != "amqps" {
+			return fmt.Errorf("sink of protocol '%s' must be an amqp or amqps URL", sr.Protocol)
+		}
+		if sr.AMQPTargetAddress() == "" {
+			return fmt.Errorf("protocolSettings.address is required when the sink has no path")
+		}
+		return sr.AMQPSettings.validate()
+	case NATS:
+		if sink.Scheme != "nats" && sink.Scheme != "tls" {
+			return fmt.Errorf("sink of protocol '%s' must be a nats or tls URL", sr.Protocol)
+		}
+		for _, server := range strings.Split(sink.Host, ",") {
+			if server == "" {
+				return fmt.Errorf("sink of protocol '%s' must list its servers", sr.Protocol)
+			}
+		}
+		return sr.NATSSettings.validate()
+	default:
+		if sink.Scheme != "https" {
+			return fmt.Errorf("sink of protocol '%s' must be an https URL", sr.Protocol)
+		}
+	}
+	return nil
+}
+
+// cleartextSchemes are the sink schemes of the protocols connecting without TLS.
+var cleartextSchemes = []string{"mqtt", "kafka", "amqp", "nats"}
+
+// validateCredentialTransport rejects a sink credential that would be sent in cleartext: with a sink
+// credential, a sink without TLS must only list internal Kubernetes services.
+func (sr *SubscriptionRequest) validateCredentialTransport(sink *url.URL) error {
+	if sr.SinkCredential == nil || !slices.Contains(cleartextSchemes, sink.Scheme) {
+		return nil
+	}
+	for _, server := range strings.Split(sink.Host, ",") {
+		host, _, err := net.SplitHostPort(server)
+		if err != nil {
+			host = server
+		}
+		if !IsInternalClusterHost(host) {
+			return fmt.Errorf("sink credential cannot be sent in cleartext to %s: use a TLS sink", server)
+		}
+	}
+	return nil
+}
+
+// IsInternalClusterHost checks if a hostname is the one of an internal Kubernetes service, ending in .svc
+// or .svc.cluster.local. A public name merely containing .svc., such as broker.svc.example.com, is not.
+func IsInternalClusterHost(hostname string) bool {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	return strings.HasSuffix(hostname, ".svc.cluster.local") ||
+		strings.HasSuffix(hostname, ".svc")
+}
+
+// validate checks the topic the notifications are published to and the QoS they are published with.
+func (s *MQTTSettings) validate() error {
+	if s == nil || s.TopicName == "" {
+		return fmt.Errorf("protocolSettings.topicName is required")
+	}
+	if strings.ContainsAny(s.TopicName, "+#") {
+		return fmt.Errorf("protocolSettings.topicName cannot contain wildcards")
+	}
+	if s.Qos != nil && (*s.Qos < 0 || *s.Qos > 2) {
+		return fmt.Errorf("protocolSettings.qos must be 0, 1 or 2")
+	}
+	if s.Expiry != nil && *s.Expiry < 0 {
+		return fmt.Errorf("protocolSettings.expiry cannot be negative")
+	}
+	return nil
+}
+
+// kafkaTopicName matches the names Kafka accepts for a topic.
+var kafkaTopicName = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// kafkaKeyAttributes are the CloudEvent attributes a partitionKeyExtractor can name.
+var kafkaKeyAttributes = []string{"id", "source", "type", "specversion", "time"}
+
+// validate checks the topic the notifications are produced to, the acknowledgements they require and
+// where their key comes from.
+func (s *ApacheKafkaSettings) validate() error {
+	if s == nil || s.TopicName == "" {
+		return fmt.Errorf("protocolSettings.topicName is required")
+	}
+	if !kafkaTopicName.MatchString(s.TopicName) || s.TopicName == "." || s.TopicName == ".." {
+		return fmt.Errorf("protocolSettings.topicName is not a valid Kafka topic name")
+	}
+	if s.AckMode != nil && (*s.AckMode < -1 || *s.AckMode > 1) {
+		return fmt.Errorf("protocolSettings.ackMode must be -1, 0 or 1")
+	}
+	if s.ContentMode != nil && *s.ContentMode != Structured && *s.ContentMode != Binary {
+		return fmt.Errorf("protocolSettings.contentMode must be structured or binary")
+	}
+	if s.PartitionKeyExtractor != nil {
+		extractor := *s.PartitionKeyExtractor
+		path, isData := strings.CutPrefix(extractor, "data.")
+		if isData && (path == "" || slices.Contains(strings.Split(path, "."), "")) {
+			return fmt.Errorf("protocolSettings.partitionKeyExtractor has an invalid data path")
+		}
+		if !isData && !slices.Contains(kafkaKeyAttributes, extractor) {
+			return fmt.Errorf("protocolSettings.partitionKeyExtractor must be one of %s or a data path", strings.Join(kafkaKeyAttributes, ", "))
+		}
+	}
+	return nil
+}
+
+// AMQPTargetAddress returns the address the notifications of an AMQP subscription are sent to: the address
+// of its protocol settings, or else the path of its sink.
+func (sr *SubscriptionRequest) AMQPTargetAddress() string {
+	if sr.AMQPSettings != nil && sr.AMQPSettings.Address != nil && *sr.AMQPSettings.Address != "" {
+		return *sr.AMQPSettings.Address
+	}
+	sink, err := url.Parse(sr.Sink)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(sink.Path, "/")
+}
+
+// validate checks the settlement mode of the link the notifications are sent on. The settings are
+// optional. Notifications are only sent unsettled, so that the receiver confirms them.
+func (s *AMQPSettings) validate() error {
+	if s == nil || s.SenderSettlementMode == nil {
+		return nil
+	}
+	if *s.SenderSettlementMode != Unsettled {
+		return fmt.Errorf("unsupported protocolSettings.senderSettlementMode '%s', notifications are sent unsettled", *s.SenderSettlementMode)
+	}
+	return nil
+}
+
+// validate checks the subject the notifications are published to.
+func (s *NATSSettings) validate() error {
+	if s == nil || s.Subject == "" {
+		return fmt.Errorf("protocolSettings.subject is required")
+	}
+	for _, token := range strings.Split(s.Subject, ".") {
+		if token == "" || strings.ContainsAny(token, " \t\r\n") {
+			return fmt.Errorf("protocolSettings.subject is not a valid NATS subject")
+		}
+		if token == "*" || token == ">" {
+			return fmt.Errorf("protocolSettings.subject cannot contain wildcards")
+		}
 	}
 	return nil
 }
@@ -0,0 +1,228 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionRequestProtocolSettings(t *testing.T) {
+	t.Run("decodes the MQTT settings of an MQTT subscription", func(t *testing.T) {
+		var sr SubscriptionRequest
+		body := `{"protocol":"MQTT5","sink":"mqtts://broker.example.com","types":[],"config":{"subscriptionDetail":{}},
+			"protocolSettings":{"topicName":"efn/reports","qos":2,"userProperties":{"tenant":"acme"}}}`
+		require.NoError(t, json.Unmarshal([]byte(body), &sr))
+		assert.Nil(t, sr.ProtocolSettings)
+		require.NotNil(t, sr.MQTTSettings)
+		assert.Equal(t, "efn/reports", sr.MQTTSettings.TopicName)
+		assert.Equal(t, int32(2), *sr.MQTTSettings.Qos)
+		assert.NoError(t, sr.ValidateProtocol())
+		assert.NoError(t, sr.ValidateProtocolSettings())
+
+		encoded, err := json.Marshal(sr)
+		require.NoError(t, err)
+		var decoded SubscriptionRequest
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, sr, decoded)
+	})
+
+	t.Run("decodes the Kafka settings of a KAFKA subscription", func(t *testing.T) {
+		var sr SubscriptionRequest
+		body := `{"protocol":"KAFKA","sink":"kafkas://broker-1.example.com:9093,broker-2.example.com:9093","types":[],
+			"config":{"subscriptionDetail":{}},"protocolSettings":{"topicName":"efn.reports","ackMode":1,
+			"partitionKeyExtractor":"data.requestId","contentMode":"binary"}}`
+		require.NoError(t, json.Unmarshal([]byte(body), &sr))
+		assert.Nil(t, sr.ProtocolSettings)
+		assert.Nil(t, sr.MQTTSettings)
+		require.NotNil(t, sr.KafkaSettings)
+		assert.Equal(t, "efn.reports", sr.KafkaSettings.TopicName)
+		assert.Equal(t, 1, *sr.KafkaSettings.AckMode)
+		assert.Equal(t, Binary, *sr.KafkaSettings.ContentMode)
+		assert.NoError(t, sr.ValidateProtocol())
+		assert.NoError(t, sr.ValidateProtocolSettings())
+
+		encoded, err := json.Marshal(sr)
+		require.NoError(t, err)
+		var decoded SubscriptionRequest
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, sr, decoded)
+	})
+
+	t.Run("decodes the AMQP settings of an AMQP subscription", func(t *testing.T) {
+		var sr SubscriptionRequest
+		body := `{"protocol":"AMQP","sink":"amqps://broker.example.com/notifications","types":[],"config":{"subscriptionDetail":{}},
+			"protocolSettings":{"linkName":"efn","senderSettlementMode":"unsettled","linkProperties":{"tenant":"acme"}}}`
+		require.NoError(t, json.Unmarshal([]byte(body), &sr))
+		assert.Nil(t, sr.ProtocolSettings)
+		require.NotNil(t, sr.AMQPSettings)
+		assert.Equal(t, Unsettled, *sr.AMQPSettings.SenderSettlementMode)
+		assert.Equal(t, "notifications", sr.AMQPTargetAddress())
+		assert.NoError(t, sr.ValidateProtocol())
+		assert.NoError(t, sr.ValidateProtocolSettings())
+
+		address := "efn/reports"
+		sr.AMQPSettings.Address = &address
+		assert.Equal(t, "efn/reports", sr.AMQPTargetAddress())
+
+		encoded, err := json.Marshal(sr)
+		require.NoError(t, err)
+		var decoded SubscriptionRequest
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, sr, decoded)
+	})
+
+	t.Run("decodes the NATS settings of a NATS subscription", func(t *testing.T) {
+		var sr SubscriptionRequest
+		body := `{"protocol":"NATS","sink":"tls://nats-1.example.com:4222,nats-2.example.com:4222","types":[],
+			"config":{"subscriptionDetail":{}},"protocolSettings":{"subject":"efn.reports.acme"}}`
+		require.NoError(t, json.Unmarshal([]byte(body), &sr))
+		assert.Nil(t, sr.ProtocolSettings)
+		require.NotNil(t, sr.NATSSettings)
+		assert.Equal(t, "efn.reports.acme", sr.NATSSettings.Subject)
+		assert.NoError(t, sr.ValidateProtocol())
+		assert.NoError(t, sr.ValidateProtocolSettings())
+
+		encoded, err := json.Marshal(sr)
+		require.NoError(t, err)
+		var decoded SubscriptionRequest
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, sr, decoded)
+	})
+
+	t.Run("decodes the HTTP settings of an HTTP subscription", func(t *testing.T) {
+		var sr SubscriptionRequest
+		body := `{"protocol":"HTTP","sink":"https://endpoint.example.com/sink","types":[],"config":{"subscriptionDetail":{}},
+			"protocolSettings":{"headers":{"X-Tenant":"acme"}}}`
+		require.NoError(t, json.Unmarshal([]byte(body), &sr))
+		assert.Nil(t, sr.MQTTSettings)
+		require.NotNil(t, sr.ProtocolSettings)
+		assert.Equal(t, map[string]string{"X-Tenant": "acme"}, *sr.ProtocolSettings.Headers)
+		assert.NoError(t, sr.ValidateProtocolSettings())
+	})
+
+	t.Run("rejects invalid MQTT settings", func(t *testing.T) {
+		qos := int32(3)
+		for _, sr := range []SubscriptionRequest{
+			{Protocol: MQTT3, Sink: "mqtt://broker.example.com"},
+			{Protocol: MQTT3, Sink: "https://broker.example.com", MQTTSettings: &MQTTSettings{TopicName: "efn"}},
+			{Protocol: MQTT3, Sink: "mqtt://broker.example.com", MQTTSettings: &MQTTSettings{TopicName: "efn/#"}},
+			{Protocol: MQTT5, Sink: "mqtt://broker.example.com", MQTTSettings: &MQTTSettings{TopicName: "efn", Qos: &qos}},
+			{Protocol: HTTP, Sink: "mqtt://broker.example.com"},
+		} {
+			assert.Error(t, sr.ValidateProtocolSettings(), sr)
+		}
+	})
+
+	t.Run("rejects invalid Kafka settings", func(t *testing.T) {
+		ackMode, contentMode := 2, ApacheKafkaSettingsContentMode("batch")
+		attribute, emptyPath := "subject", "data..requestId"
+		for _, sr := range []SubscriptionRequest{
+			{Protocol: KAFKA, Sink: "kafka://broker.example.com:9092"},
+			{Protocol: KAFKA, Sink: "https://broker.example.com", KafkaSettings: &ApacheKafkaSettings{TopicName: "efn"}},
+			{Protocol: KAFKA, Sink: "kafka://broker.example.com,", KafkaSettings: &ApacheKafkaSettings{TopicName: "efn"}},
+			{Protocol: KAFKA, Sink: "kafka://broker.example.com", KafkaSettings: &ApacheKafkaSettings{TopicName: "efn/reports"}},
+			{Protocol: KAFKA, Sink: "kafka://broker.example.com", KafkaSettings: &ApacheKafkaSettings{TopicName: "efn", AckMode: &ackMode}},
+			{Protocol: KAFKA, Sink: "kafka://broker.example.com", KafkaSettings: &ApacheKafkaSettings{TopicName: "efn", ContentMode: &contentMode}},
+			{Protocol: KAFKA, Sink: "kafka://broker.example.com", KafkaSettings: &ApacheKafkaSettings{TopicName: "efn", PartitionKeyExtractor: &attribute}},
+			{Protocol: KAFKA, Sink: "kafka://broker.example.com", KafkaSettings: &ApacheKafkaSettings{TopicName: "efn", PartitionKeyExtractor: &emptyPath}},
+		} {
+			assert.Error(t, sr.ValidateProtocolSettings(), sr)
+		}
+	})
+
+	t.Run("rejects invalid AMQP settings", func(t *testing.T) {
+		mode, settled := AMQPSettingsSenderSettlementMode("mixed"), AMQPSettingsSenderSettlementMode("settled")
+		for _, sr := range []SubscriptionRequest{
+			{Protocol: AMQP, Sink: "amqp://broker.example.com"},
+			{Protocol: AMQP, Sink: "https://broker.example.com/notifications"},
+			{Protocol: AMQP, Sink: "amqp://broker.example.com/notifications", AMQPSettings: &AMQPSettings{SenderSettlementMode: &mode}},
+			{Protocol: AMQP, Sink: "amqp://broker.example.com/notifications", AMQPSettings: &AMQPSettings{SenderSettlementMode: &settled}},
+		} {
+			assert.Error(t, sr.ValidateProtocolSettings(), sr)
+		}
+	})
+
+	t.Run("rejects invalid NATS settings", func(t *testing.T) {
+		for _, sr := range []SubscriptionRequest{
+			{Protocol: NATS, Sink: "nats://nats.example.com"},
+			{Protocol: NATS, Sink: "https://nats.example.com", NATSSettings: &NATSSettings{Subject: "efn"}},
+			{Protocol: NATS, Sink: "nats://nats.example.com", NATSSettings: &NATSSettings{Subject: "efn.>"}},
+			{Protocol: NATS, Sink: "nats://nats.example.com", NATSSettings: &NATSSettings{Subject: "efn.*.reports"}},
+			{Protocol: NATS, Sink: "nats://nats.example.com", NATSSettings: &NATSSettings{Subject: "efn..reports"}},
+		} {
+			assert.Error(t, sr.ValidateProtocolSettings(), sr)
+		}
+	})
+
+	t.Run("rejects a plain credential for HTTP", func(t *testing.T) {
+		sr := SubscriptionRequest{Protocol: HTTP, SinkCredential: &SinkCredential{CredentialType: SinkCredentialCredentialTypePLAIN}}
+		assert.Error(t, sr.ValidateProtocol())
+		sr.Protocol = MQTT3
+		assert.NoError(t, sr.ValidateProtocol())
+		assert.Error(t, sr.SinkCredential.Validate())
+		sr.SinkCredential.Identifier, sr.SinkCredential.Secret = "efn", "secret"
+		assert.NoError(t, sr.SinkCredential.Validate())
+	})
+
+	t.Run("rejects a credential sent in cleartext outside of the cluster", func(t *testing.T) {
+		cred := &SinkCredential{CredentialType: SinkCredentialCredentialTypePLAIN, Identifier: "efn", Secret: "secret"}
+		mqtt := &MQTTSettings{TopicName: "efn"}
+		kafka := &ApacheKafkaSettings{TopicName: "efn"}
+		nats := &NATSSettings{Subject: "efn"}
+		for _, sr := range []SubscriptionRequest{
+			{Protocol: MQTT3, Sink: "mqtt://broker.example.com", MQTTSettings: mqtt, SinkCredential: cred},
+			{Protocol: KAFKA, Sink: "kafka://kafka.efn.svc:9092,broker.example.com:9092", KafkaSettings: kafka, SinkCredential: cred},
+			{Protocol: AMQP, Sink: "amqp://broker.example.com/notifications", SinkCredential: cred},
+			{Protocol: AMQP, Sink: "amqp://broker.svc.attacker.example/notifications", SinkCredential: cred},
+			{Protocol: NATS, Sink: "nats://nats.example.com:4222", NATSSettings: nats, SinkCredential: cred},
+		} {
+			assert.Error(t, sr.ValidateProtocolSettings(), sr)
+		}
+		for _, sr := range []SubscriptionRequest{
+			{Protocol: MQTT3, Sink: "mqtts://broker.example.com", MQTTSettings: mqtt, SinkCredential: cred},
+			{Protocol: MQTT3, Sink: "mqtt://broker.example.com", MQTTSettings: mqtt},
+			{Protocol: KAFKA, Sink: "kafka://kafka-0.kafka.efn.svc.cluster.local:9092,kafka-1.kafka.efn.svc:9092", KafkaSettings: kafka, SinkCredential: cred},
+			{Protocol: AMQP, Sink: "amqp://broker.efn.svc/notifications", SinkCredential: cred},
+			{Protocol: NATS, Sink: "tls://nats.example.com:4222", NATSSettings: nats, SinkCredential: cred},
+		} {
+			assert.NoError(t, sr.ValidateProtocolSettings(), sr)
+		}
+	})
+}
+
+func TestIsInternalClusterHost(t *testing.T) {
+	tests := []struct {
+		hostname string
+		expected bool
+	}{
+		{hostname: "broker.efn.svc", expected: true},
+		{hostname: "broker.efn.svc.cluster.local", expected: true},
+		{hostname: "Broker.EFN.SVC.cluster.local.", expected: true},
+		{hostname: "broker.svc.attacker.example", expected: false},
+		{hostname: "broker.efn.svc.cluster.local.attacker.example", expected: false},
+		{hostname: "broker-svc", expected: false},
+		{hostname: "broker.example.com", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsInternalClusterHost(tt.hostname))
+		})
+	}
+}
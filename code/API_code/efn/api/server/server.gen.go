@@ -43,6 +43,18 @@ type ServerInterface interface {
 	// Provides the overall Energy Consumption for the target Application instances in a certain period of time.
 	// (POST /calculate-energy-consumption)
 	CalculateEnergyConsumption(ctx echo.Context, params CalculateEnergyConsumptionParams) error
+	// Lists the reports created by the API Consumer.
+	// (GET /reports)
+	ListReports(ctx echo.Context, params ListReportsParams) error
+	// Cancels a report.
+	// (DELETE /reports/{requestId})
+	CancelReport(ctx echo.Context, requestId RequestId, params CancelReportParams) error
+	// Retrieves the status and the result of a report.
+	// (GET /reports/{requestId})
+	GetReport(ctx echo.Context, requestId RequestId, params GetReportParams) error
+	// Validates a report request without creating the report.
+	// (POST /reports:validate)
+	ValidateReport(ctx echo.Context, params ValidateReportParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -75,6 +87,36 @@ func (w *ServerInterfaceWrapper) CalculateCarbonFootprint(ctx echo.Context) erro
 
 		params.XCorrelator = &XCorrelator
 	}
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+	// ------------- Optional header parameter "Prefer" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Prefer")]; found {
+		var Prefer Prefer
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Prefer, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Prefer", valueList[0], &Prefer, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Prefer: %s", err))
+		}
+
+		params.Prefer = &Prefer
+	}
 
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.CalculateCarbonFootprint(ctx, params)
@@ -106,12 +148,241 @@ func (w *ServerInterfaceWrapper) CalculateEnergyConsumption(ctx echo.Context) er
 
 		params.XCorrelator = &XCorrelator
 	}
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+	// ------------- Optional header parameter "Prefer" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Prefer")]; found {
+		var Prefer Prefer
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Prefer, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Prefer", valueList[0], &Prefer, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Prefer: %s", err))
+		}
+
+		params.Prefer = &Prefer
+	}
 
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.CalculateEnergyConsumption(ctx, params)
 	return err
 }
 
+// ListReports converts echo context to params.
+func (w *ServerInterfaceWrapper) ListReports(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(OpenIdScopes, []string{"energy-footprint-notification:reports:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListReportsParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "kind" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "kind", ctx.QueryParams(), &params.Kind)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter kind: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdFrom" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdFrom", ctx.QueryParams(), &params.CreatedFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdFrom: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdTo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdTo", ctx.QueryParams(), &params.CreatedTo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdTo: %s", err))
+	}
+
+	// ------------- Optional query parameter "appInstanceId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "appInstanceId", ctx.QueryParams(), &params.AppInstanceId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter appInstanceId: %s", err))
+	}
+
+	// ------------- Optional query parameter "parentRequestId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "parentRequestId", ctx.QueryParams(), &params.ParentRequestId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter parentRequestId: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "x-correlator" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("x-correlator")]; found {
+		var XCorrelator XCorrelator
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for x-correlator, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "x-correlator", valueList[0], &XCorrelator, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x-correlator: %s", err))
+		}
+
+		params.XCorrelator = &XCorrelator
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ListReports(ctx, params)
+	return err
+}
+
+// CancelReport converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelReport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "requestId" -------------
+	var requestId RequestId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "requestId", ctx.Param("requestId"), &requestId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter requestId: %s", err))
+	}
+
+	ctx.Set(OpenIdScopes, []string{"energy-footprint-notification:reports:delete"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CancelReportParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "x-correlator" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("x-correlator")]; found {
+		var XCorrelator XCorrelator
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for x-correlator, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "x-correlator", valueList[0], &XCorrelator, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x-correlator: %s", err))
+		}
+
+		params.XCorrelator = &XCorrelator
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CancelReport(ctx, requestId, params)
+	return err
+}
+
+// GetReport converts echo context to params.
+func (w *ServerInterfaceWrapper) GetReport(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "requestId" -------------
+	var requestId RequestId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "requestId", ctx.Param("requestId"), &requestId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter requestId: %s", err))
+	}
+
+	ctx.Set(OpenIdScopes, []string{"energy-footprint-notification:reports:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetReportParams
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "x-correlator" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("x-correlator")]; found {
+		var XCorrelator XCorrelator
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for x-correlator, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "x-correlator", valueList[0], &XCorrelator, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x-correlator: %s", err))
+		}
+
+		params.XCorrelator = &XCorrelator
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetReport(ctx, requestId, params)
+	return err
+}
+
+// ValidateReport converts echo context to params.
+func (w *ServerInterfaceWrapper) ValidateReport(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(OpenIdScopes, []string{"energy-footprint-notification:calculate-energy-consumption", "energy-footprint-notification:calculate-carbon-footprint"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ValidateReportParams
+	// ------------- Required query parameter "kind" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "kind", ctx.QueryParams(), &params.Kind)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter kind: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "x-correlator" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("x-correlator")]; found {
+		var XCorrelator XCorrelator
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for x-correlator, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "x-correlator", valueList[0], &XCorrelator, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter x-correlator: %s", err))
+		}
+
+		params.XCorrelator = &XCorrelator
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ValidateReport(ctx, params)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -142,6 +413,10 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 
 	router.POST(baseURL+"/calculate-carbon-footprint", wrapper.CalculateCarbonFootprint)
 	router.POST(baseURL+"/calculate-energy-consumption", wrapper.CalculateEnergyConsumption)
+	router.GET(baseURL+"/reports", wrapper.ListReports)
+	router.DELETE(baseURL+"/reports/:requestId", wrapper.CancelReport)
+	router.GET(baseURL+"/reports/:requestId", wrapper.GetReport)
+	router.POST(baseURL+"/reports:validate", wrapper.ValidateReport)
 
 }
 
@@ -205,6 +480,21 @@ type Generic404JSONResponse struct {
 	Headers Generic404ResponseHeaders
 }
 
+type Generic409ResponseHeaders struct {
+	XCorrelator XCorrelator
+}
+type Generic409JSONResponse struct {
+	Body struct {
+		Code interface{} `json:"code"`
+
+		// Message A human-readable description of what the event represents
+		Message string      `json:"message"`
+		Status  interface{} `json:"status"`
+	}
+
+	Headers Generic409ResponseHeaders
+}
+
 type Generic410ResponseHeaders struct {
 	XCorrelator XCorrelator
 }
@@ -221,6 +511,7 @@ type Generic410JSONResponse struct {
 }
 
 type Generic429ResponseHeaders struct {
+	RetryAfter  int
 	XCorrelator XCorrelator
 }
 type Generic429JSONResponse struct {
@@ -244,6 +535,25 @@ type CalculateCarbonFootprintResponseObject interface {
 	VisitCalculateCarbonFootprintResponse(w http.ResponseWriter) error
 }
 
+type CalculateCarbonFootprint200ResponseHeaders struct {
+	PreferenceApplied string
+	XCorrelator       XCorrelator
+}
+
+type CalculateCarbonFootprint200JSONResponse struct {
+	Body    Report
+	Headers CalculateCarbonFootprint200ResponseHeaders
+}
+
+func (response CalculateCarbonFootprint200JSONResponse) VisitCalculateCarbonFootprintResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Preference-Applied", fmt.Sprint(response.Headers.PreferenceApplied))
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type CalculateCarbonFootprint201ResponseHeaders struct {
 	Location    string
 	XCorrelator XCorrelator
@@ -303,6 +613,27 @@ func (response CalculateCarbonFootprint404JSONResponse) VisitCalculateCarbonFoot
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type CalculateCarbonFootprint409JSONResponse struct{ Generic409JSONResponse }
+
+func (response CalculateCarbonFootprint409JSONResponse) VisitCalculateCarbonFootprintResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CalculateCarbonFootprint429JSONResponse struct{ Generic429JSONResponse }
+
+func (response CalculateCarbonFootprint429JSONResponse) VisitCalculateCarbonFootprintResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type CalculateEnergyConsumptionRequestObject struct {
 	Params CalculateEnergyConsumptionParams
 	Body   *CalculateEnergyConsumptionJSONRequestBody
@@ -312,6 +643,25 @@ type CalculateEnergyConsumptionResponseObject interface {
 	VisitCalculateEnergyConsumptionResponse(w http.ResponseWriter) error
 }
 
+type CalculateEnergyConsumption200ResponseHeaders struct {
+	PreferenceApplied string
+	XCorrelator       XCorrelator
+}
+
+type CalculateEnergyConsumption200JSONResponse struct {
+	Body    Report
+	Headers CalculateEnergyConsumption200ResponseHeaders
+}
+
+func (response CalculateEnergyConsumption200JSONResponse) VisitCalculateEnergyConsumptionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Preference-Applied", fmt.Sprint(response.Headers.PreferenceApplied))
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type CalculateEnergyConsumption201ResponseHeaders struct {
 	Location    string
 	XCorrelator XCorrelator
@@ -371,6 +721,280 @@ func (response CalculateEnergyConsumption404JSONResponse) VisitCalculateEnergyCo
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type CalculateEnergyConsumption409JSONResponse struct{ Generic409JSONResponse }
+
+func (response CalculateEnergyConsumption409JSONResponse) VisitCalculateEnergyConsumptionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CalculateEnergyConsumption429JSONResponse struct{ Generic429JSONResponse }
+
+func (response CalculateEnergyConsumption429JSONResponse) VisitCalculateEnergyConsumptionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", fmt.Sprint(response.Headers.RetryAfter))
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListReportsRequestObject struct {
+	Params ListReportsParams
+}
+
+type ListReportsResponseObject interface {
+	VisitListReportsResponse(w http.ResponseWriter) error
+}
+
+type ListReports200ResponseHeaders struct {
+	XCorrelator XCorrelator
+}
+
+type ListReports200JSONResponse struct {
+	Body    ReportList
+	Headers ListReports200ResponseHeaders
+}
+
+func (response ListReports200JSONResponse) VisitListReportsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListReports400JSONResponse struct{ Generic400JSONResponse }
+
+func (response ListReports400JSONResponse) VisitListReportsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListReports401JSONResponse struct{ Generic401JSONResponse }
+
+func (response ListReports401JSONResponse) VisitListReportsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListReports403JSONResponse struct{ Generic403JSONResponse }
+
+func (response ListReports403JSONResponse) VisitListReportsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelReportRequestObject struct {
+	RequestId RequestId `json:"requestId"`
+	Params    CancelReportParams
+}
+
+type CancelReportResponseObject interface {
+	VisitCancelReportResponse(w http.ResponseWriter) error
+}
+
+type CancelReport202ResponseHeaders struct {
+	XCorrelator XCorrelator
+}
+
+type CancelReport202JSONResponse struct {
+	Body    Report
+	Headers CancelReport202ResponseHeaders
+}
+
+func (response CancelReport202JSONResponse) VisitCancelReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(202)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelReport400JSONResponse struct{ Generic400JSONResponse }
+
+func (response CancelReport400JSONResponse) VisitCancelReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelReport401JSONResponse struct{ Generic401JSONResponse }
+
+func (response CancelReport401JSONResponse) VisitCancelReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelReport403JSONResponse struct{ Generic403JSONResponse }
+
+func (response CancelReport403JSONResponse) VisitCancelReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelReport404JSONResponse struct{ Generic404JSONResponse }
+
+func (response CancelReport404JSONResponse) VisitCancelReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CancelReport409JSONResponse struct{ Generic409JSONResponse }
+
+func (response CancelReport409JSONResponse) VisitCancelReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetReportRequestObject struct {
+	RequestId RequestId `json:"requestId"`
+	Params    GetReportParams
+}
+
+type GetReportResponseObject interface {
+	VisitGetReportResponse(w http.ResponseWriter) error
+}
+
+type GetReport200ResponseHeaders struct {
+	XCorrelator XCorrelator
+}
+
+type GetReport200JSONResponse struct {
+	Body    Report
+	Headers GetReport200ResponseHeaders
+}
+
+func (response GetReport200JSONResponse) VisitGetReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetReport400JSONResponse struct{ Generic400JSONResponse }
+
+func (response GetReport400JSONResponse) VisitGetReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetReport401JSONResponse struct{ Generic401JSONResponse }
+
+func (response GetReport401JSONResponse) VisitGetReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetReport403JSONResponse struct{ Generic403JSONResponse }
+
+func (response GetReport403JSONResponse) VisitGetReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetReport404JSONResponse struct{ Generic404JSONResponse }
+
+func (response GetReport404JSONResponse) VisitGetReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ValidateReportRequestObject struct {
+	Params ValidateReportParams
+	Body   *ValidateReportJSONRequestBody
+}
+
+type ValidateReportResponseObject interface {
+	VisitValidateReportResponse(w http.ResponseWriter) error
+}
+
+type ValidateReport200ResponseHeaders struct {
+	XCorrelator XCorrelator
+}
+
+type ValidateReport200JSONResponse struct {
+	Body    ReportValidation
+	Headers ValidateReport200ResponseHeaders
+}
+
+func (response ValidateReport200JSONResponse) VisitValidateReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ValidateReport400JSONResponse struct{ Generic400JSONResponse }
+
+func (response ValidateReport400JSONResponse) VisitValidateReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ValidateReport401JSONResponse struct{ Generic401JSONResponse }
+
+func (response ValidateReport401JSONResponse) VisitValidateReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ValidateReport403JSONResponse struct{ Generic403JSONResponse }
+
+func (response ValidateReport403JSONResponse) VisitValidateReportResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("x-correlator", fmt.Sprint(response.Headers.XCorrelator))
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 	// Retrieves the overall carbon footprint for the target Application instances in a certain period of time.
@@ -379,6 +1003,18 @@ type StrictServerInterface interface {
 	// Provides the overall Energy Consumption for the target Application instances in a certain period of time.
 	// (POST /calculate-energy-consumption)
 	CalculateEnergyConsumption(ctx context.Context, request CalculateEnergyConsumptionRequestObject) (CalculateEnergyConsumptionResponseObject, error)
+	// Lists the reports created by the API Consumer.
+	// (GET /reports)
+	ListReports(ctx context.Context, request ListReportsRequestObject) (ListReportsResponseObject, error)
+	// Cancels a report.
+	// (DELETE /reports/{requestId})
+	CancelReport(ctx context.Context, request CancelReportRequestObject) (CancelReportResponseObject, error)
+	// Retrieves the status and the result of a report.
+	// (GET /reports/{requestId})
+	GetReport(ctx context.Context, request GetReportRequestObject) (GetReportResponseObject, error)
+	// Validates a report request without creating the report.
+	// (POST /reports:validate)
+	ValidateReport(ctx context.Context, request ValidateReportRequestObject) (ValidateReportResponseObject, error)
 }
 
 type StrictHandlerFunc = strictecho.StrictEchoHandlerFunc
@@ -455,125 +1091,312 @@ func (sh *strictHandler) CalculateEnergyConsumption(ctx echo.Context, params Cal
 	return nil
 }
 
+// ListReports operation middleware
+func (sh *strictHandler) ListReports(ctx echo.Context, params ListReportsParams) error {
+	var request ListReportsRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ListReports(ctx.Request().Context(), request.(ListReportsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ListReports")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ListReportsResponseObject); ok {
+		return validResponse.VisitListReportsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// CancelReport operation middleware
+func (sh *strictHandler) CancelReport(ctx echo.Context, requestId RequestId, params CancelReportParams) error {
+	var request CancelReportRequestObject
+
+	request.RequestId = requestId
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.CancelReport(ctx.Request().Context(), request.(CancelReportRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CancelReport")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(CancelReportResponseObject); ok {
+		return validResponse.VisitCancelReportResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetReport operation middleware
+func (sh *strictHandler) GetReport(ctx echo.Context, requestId RequestId, params GetReportParams) error {
+	var request GetReportRequestObject
+
+	request.RequestId = requestId
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetReport(ctx.Request().Context(), request.(GetReportRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetReport")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetReportResponseObject); ok {
+		return validResponse.VisitGetReportResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// ValidateReport operation middleware
+func (sh *strictHandler) ValidateReport(ctx echo.Context, params ValidateReportParams) error {
+	var request ValidateReportRequestObject
+
+	request.Params = params
+
+	var body ValidateReportJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ValidateReport(ctx.Request().Context(), request.(ValidateReportRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ValidateReport")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ValidateReportResponseObject); ok {
+		return validResponse.VisitValidateReportResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x8+3IbN9bnq6DaqdrYQzZJUVIsbm3tciQ6w4otKRI1l7i9Eth9SGLUBDoAWjLHpap9",
-	"jX29fZItHAB9YTcl2bGzs9+XP1Kx2LgcHJzrDwf4FMRinQkOXKtg9CmIaZrOaXyLfwh+TOVc8DdC6Ewy",
-	"ro9pGucp1Uxw8/3TdxJ+zUHpcC6STajyuYoly8znC/dBMX77YNpmQmnz/wSKNsEoEDwGoldA1EZpWJMV",
-	"VSR2k0CCX2IkgSw8DUQsbA+QdyyGDtErpgjjCyHXSBlhimRS3LEEEmLWQrTAHuPzKTkWXOVrkEEnEBlI",
-	"7DBNglEQ11d6KjRbsNgutRNkVNI1aJAqGL3/FHwnYRGMghe9knm9sknvYzcWUkJKtZDBw4dO4Nj0Z5Fs",
-	"kMmCa+DIDpplqZumF6ciT+DOjPanfyrLYvhI11kKyDmqqd2iGqXBaP8gPDh8XcyCyxku6OuDxeF+9+CH",
-	"wQ/d/YPDve58uIi7e/HR4XBxeEgX9DB46OCgjhy9ySAY1ShCKjoBMyMqLRlfBp1AiVzGpuVK60yNej1e",
-	"4dUl8OQS5B3IwV7oiA9jsTb9MojvQCq784OwH3QCzdZmpL3+4HW3v9/tH8wGP4yGg1G//4v5aikSchnG",
-	"dE0lzaT4J8Q6BA5yuekWMtGtkhDeDULLo7KBWaqKV7BGDrZtn/2qesdmEyZmE4KHB8OgmrxmdJMKmhDD",
-	"Mso440sno4XIWtJMA5WvbbcHszUqE1wBqtVef6+pCf8QuUSZBkmY4doauLbyrFYiTxMiQeeSW3H/y2x2",
-	"TpSmOlckFgkQZpXCbCe5p4pIiIHdQUJUHseg1CJP003QCVZAE5TiT0FNSndwxTXfkmjDl73+/uOLeCbV",
-	"XJBU8KVZNdcgQRkmMk4WudQrkCTPEqpBfU3S9/v9XZ2Kfer9aDaSxaYtdhl8RpeB7TL8jC5D7DL4DMIG",
-	"lrC9o+d32TsKzPoVxLlkeoOmrKo56s9AJchxrlfB6P0HY7lUvl5TuQlGwbk1qcqZ1BUQCSpPC3tMOU03",
-	"iikieKvdtrwXfIIKclzqx/8Lp9LU0m/kVmB7tf8ujqW0hjRNzxY7Z2+zix86bX6psdRgNNgLX//wh2Oq",
-	"OCbbwOjC1/EtlrlMQhKMtMzhD1/zh6/5z+dr2oIuZP8j+1iXpmP3DQ19QhZCWhlniwVI4Nr7BSMczwsk",
-	"/368JQpVU/81yWGmg11o0Ak4RcNVG/6LSa6ZkoqU7nY1W3mL7Tg5nVxMj6/3+/3r6elfx2+nJ9fjix+v",
-	"3k1OZ82lT/kdTVlCxnKZG7sUEjcxudxwTT+SyccYvPG7o2kOlpzELLsxfCdYg1J0aT4epwxZl0HMFgwS",
-	"QjlhbjbqZusQ56yIiT6IkOTXHOSG4OaFhpNoUoLRfr9vOFRd29nV7PrszfXF+PTHSXNdZzmK7wXlSwjJ",
-	"pSWiuSiSK0jI/Qo4oWTJ7oCTBYM0wSjG/Ec5ybnKs0xIY76QA2ELK2rUPJcNEqnbXmYteXpeuDCRUsgp",
-	"X4jgofMpyKSJijSz8mAJNAFDvg5G79s2rUb8h4eSnqLXfr//wRDmXfHc+N/g4UOLY/0zTYgLHb+maa+Y",
-	"4C/Vh8H11en4avaXyelsejyeTU6aYuMIJzHlXGgyB0JzvQKuzQy4eQmhhMN99XcXrhahQVM6tuetCoif",
-	"0sxXnyzJwUS9a6YU48uOF5uO0RP4mJm5SCwhMT1oqkIyfoKyuqgNvrmobS97h2gNnitaV9ysTUj2L+Ty",
-	"15et4RfL1vD6fHLxbnp5OT07vT6ZnE7bpOscJG6m4CQBziAJyRhjOKLFLXCSCFAoByt6B84R250jKhYZ",
-	"mI1HW2U+5QokWVCWKlIkQTQlRQjQlMImhS2Gqk6DyhcLFuOHrCDekGv+NMmaDf9ojBF3TbyG31y8muvZ",
-	"IWDD5wrYGyHnLEmAfxPp2v9i6dq/np4YNXoznVxcn57Nrt+cXZ22CNi4HJFM0S4smIm0+S0X97zVMP10",
-	"eva30+vx+flbo6SGl+VUNfkwMqepXIImFcKNeXHD17d/v+6v9x8j+wJsMmkGM6K3EDlvM6PlEFXCZiuo",
-	"uFfZNlaDtG8smVVCn2DxDpHdf67Inlb49dVFdvDFweegf/3j2WlLYHalwCSBteSELFJxb4wKTc0/qhCP",
-	"+ZXxBD0i0SuqCdOIL+G5id1kn2bSO8pSOk+hRXSQmKrUFIaXVAS7LjyNcWtiNPj2oRoS3S4gg2fHYz8K",
-	"Dt9CNmx6+iWysXd0/fPV2Wx8Pfn78WRy8lgchsGLWVsZDsHHGCBhfEkomeeKcbONv+ZCU5KyNdMtm781",
-	"W1UMXJ5QbDwOVNvnvaOaJds7up6dnV2/G5/+4/pi8vPV5HJ22WKIa9JlBNokE3MwvhvWmZBUsnRD5qmI",
-	"b8ulSSPkQhKVsVsgVErDAlyU6WuWLIHGq9YQs0lULcg0I+NIfojGGr+xLDf2oElwu6TvHT1X0mdCkHeU",
-	"b3z68RWhpYI5ONI4y6ZcacpjmCYtm0+WqZjTNN2QnLNfcyCs9MVUKREzjO/vmV4RSmTOOePLiDM3phFI",
-	"yqteNoz4JFkCQViYnKdUY+y1NKpINSgHn5ez+CjRD6qZ89Y2dqwM9ovgaO5KNDEygYIF4oNRkOcsKTFY",
-	"h/4+dIIKQt1ggPHIVQNf2OugEyTMtFwz7rdhTbPMjDn69FWOHp8E1LcO2YPO15g2BKMKT0+OGvNlUzos",
-	"++kpbLvfMMfnLeah4zV/c2rRMJSV8mdnEPzBRV1UcCCSgMZExoPyts3c4sXGXpHj8bvxxRgtqcnBJSAy",
-	"F0NC5hsMCXDShploOdnYpmANCaNd883GF35uZbFOpM/TBTwWCebi61whPBA13F4UIIBYEmzMkLeCDR/5",
-	"oUW1WItNqeg2orJMWdI6lmhPjrc3Vs2dT8Plf9TG4q8Zfwt8qVfBaNAysz/TedzsX9pWD1tnOdsk/9V+",
-	"KI76HAZnLYIW5H7F4lVlKYZOY3YU+d4vZxD2CVsQVvmmBakcsJkW4R5xNLyscHoQ9luZa4+aHl/gCdUw",
-	"M+0Kv/OEHzS0zDYZ1I4bLaTrj4veB2hHHYfrrHOzOOKMGzwWfMGWLXBt7fyo63mK7GHL3KIApMS+CQdI",
-	"rJLgHlSOd8macmrCWyOtNDZ0mgjD8jWM+JR7Gb4HCwZkEhJYMA4JoVpLNs+N60lNrHJTHXmC2JRh4E2n",
-	"/uUd/Yi8UuYD40wzmuIPNxEvMForDKiQlWm8SHgKGK8PfYIm5Cbip0LDyEgNLcBlpjxcARbdV3AHkqaV",
-	"qTom9/D8MYbHfrpnaUpyBUTRNZAby+abCoND9JZ1S1ddWHMLL0EbIb7RMocbszHGpsU+3WGL8t/31Ei4",
-	"FgRzbu5IooooIbj5f2NLmSKxhAKpjHNpjy+Yzq1kSFikEGuvcf7sP+ITG6iPysTLM+9C0HUhGCGZVghU",
-	"oBWprhYDXpkDzp7AHbOpFONEulEKUjrlipgiWrLlEiQkEb/KzCiGKc5tkQRippzRuAXICNOW7U6550Kk",
-	"QLHypykRT1YgIb8um/22RiuFuj3eqe0DorPO0LE1kO8ZJwnV0MW/bGz10nO4VM+qJIRk6sz6QmBW/P7i",
-	"zTEZDodHH773J+7Gt2lJ41uQIQO9CIVc9hIR91Z6nfbkIjbNXyhAdK57EB6+xI3BUS3EaMj5l+AQkuex",
-	"PajUIgR7/b1htz/oDn6YDYajwevR3jA8fL33SzV8LFbdFkO2moYWo+d9n5X4Nf3I1vma8Hw9t+7QC3Mm",
-	"pFWYORSxcUK+j/J+fwj/bfAEx0mXnNlaFpP928Ex/8R8qdPUNuCJ+hLGHaAfNmuoemHGNSxBNtxGi0h/",
-	"aIlzdspxq7TagMu75pItlpPVKcO2oKpwkM3B2RqUpuvMjF1g1SK2pig2niTLgEMSkndGDGmyAonJrxfv",
-	"MOJ/G1+cjsjM7IPIHK5tj+UYJ2X8qbZiispJB2E84pUQbKvwwpqPqiS3Fp48T4rLLHi0Kwnezg9X+Zry",
-	"rgSa0HkKtmJCiyL0tJGnSxUa8xVZ/ZPjVj7b7aC6EtRKyCQoVLk23SwS8fokWOnhT6qrJR/B05Jsh+wE",
-	"rrlfSJs4t4dUO9KHwnu4k1znWGxQbxfrJBBhDx8ifnkS9tuTq6+SdX7V3LUtWD6XQotYpI/YZDTAlCSQ",
-	"sjs8unddQnLG040tDGLKIrsu+uLivrILpkXQCd79PJsN3f8Pgk4wfvez+fl0jEjWT+M3P42DaiWc79eg",
-	"+QLdAJrDsoixuYAC8dsqAHOlHiTnCciy9MX4TVcf5pDnSq2iLxjZBq7nHgbxYE9ZW6O2i2swsuKCRJgE",
-	"nINkIomCWhlkrRanHtNKsbafY83uaLXCcsGkiYAr0BJSWsGWGvFrpYDwkW33i/YxpLXXRWRsrbHOJa97",
-	"2yIAmYMtvtDCVYI5N2mslxEdW2HXZpnsDjWJS5lCMLd2CuYXXlKuHKlUWkPreVNuSdkfuZ/k8ePSYchm",
-	"GtbqqWCzDhyWqCaVkm62I6KK6D6ajbd0cXmulaKnBpiVLRsW27G6nbA2u33J+O1xUZPQ5qMU47eVsgUv",
-	"3mq7bsFkpe6032OXpcZxiI3zkBs8EeDO2zkT5CNCDAWpO9sJn8Aex8fHk8vL2dlPk9Odu4cI6UzcAq8s",
-	"sROcvx1Pd3Y6TymrN7+YvLmYXP7l0akuYCFBrbbnamJtJSNnDnWr1ApvfRzVFtkA6LZbt8WN6FGdmpTt",
-	"Q591z1o/ky55d3U5MxqvbPJbocN7BBuR+ZN95GinRu8W45ruakt2t5bTKqwF3PVowuHwMxN8WsyqyFx9",
-	"MEu6WCpEuOBdWGd6E/Gbq4tpt4ApbxAmGUW8S64uph75Pzm99DKuN6OIE/KK+NxuyfQqn4exWFcLym2b",
-	"NWWpFqOYx4vu/bJry3JTUOp/GBOoQvMhZAJn40YnFE3TTdeBg1cXp56Aq6vpiZs3l3yU5ywZHcLrebw/",
-	"7HeP4iHtDgbJUffo8PCo23/d7+/1+/ERPTw0I1esbIlDlTClG7ZKfM8062V5mvYGe0P7fdA9ODjoDvaG",
-	"3f2Dwx+2wvLPrCwvzywkK1n/NOpZtaBF3NmUikpMiZhrjFXQc6iiRnolRb50qGYtjSKXVczJDlOM4ECt",
-	"sKYEf4SnT4Wnl+3esmm2PJZlbA0CZPb4uJElVHfsKX9h4tNdjuLdz+dttHVs0Lqjl/nW3stGvztjChqv",
-	"4Ce6uKXtvW1kvaO3+bi728Hnd8OAfUcv821HuLLl1nwW0eKlCmT8UVTPtrK9ixTmsR7nlRlNiNIuSDRJ",
-	"pDvYt67ABRpqRdPUajIGIZCQXJVBY2orGIrcqNXKAU8ygQJfWrUeklI3bXiPSWuQhqj/aXtHUS+KeuGf",
-	"vmtN5Rsx2aPxZL21C1JbkIBj1GsyKTB0RSBlS2ZiMZv8lMzABGDbJPqo4TFQz46bgaxDb4jL4wwxpkZU",
-	"1xC4FO7AnmM/KyxvdwAPnWBNP07tAAP0IuUf9cB9O2y2m1aIXsdLredlWzAyqwXsdaEHnpxQ3Qb5cJe+",
-	"2MsabA0dAhyrY1wYVg7rMkwTx8RpnjhI0wJ0GbbAIhUu/AgmKfudYejnQ8eaSv18nmDzXVz5N1xgEzlz",
-	"q/3w0AmqF0eql9aD+f5wOIz3D7v7R3G/u7843Ou+7ic/dBd9WBwN+4tBvH9Ytx7vafdf4+4v/e5R93r0",
-	"X0NjRvJ+fxhbyPzTw4dP/c7eweHDd60k+lrjS6NHVlJ3Xjr6FMzxrzd+9fWbpy96dSsV1sP3B3+rBpeJ",
-	"A5UUmW0yMiEy4Ba0sP86FpxDrK9kWrWyFeMa3kOadrF4tWe6sKRbO0itQN/VAW0dHHw0TKTpiYhbjOM5",
-	"wgaaJCLOy/t+VLsz1aAT5DWyquF+NV7q2XKO9pcSHjoBc7hzffYXL8jZncnd4T7ixm/ZUUgxDKmO402n",
-	"TcO3gayIY7JQzb7pXOR6181ePHhseUWCRtyjJ/AxE8p6BYopoflcBV0KSCaM+IsXZMq1ZScT3K5HxcCp",
-	"ZMJEdKAAFA5kRy+vDW/ImvJNbWh7jGPZEZVVQI+zgHwP4TLEny/dJO7OnHzp2GOBpM9mUcRLHn2/lAB8",
-	"JXIFZEmVcae+4P7l1n1ph0oVyFrEEfiDHVwkK1E99JpBGgtyhpcGhCyTd+OgzbKZMjtkQxbryBGoY9ws",
-	"4p85j50L1qsORtROpToRN6O7U5dKidn4fGo2MuKvXrljZjxSj6myB8WLVNyPXr0yLd6/eoUdPZsd8+zR",
-	"3qtXpUn+En3pzVMx78lBOOzV1LI3Pp9e13+ZvDm9vlIgL7WQG/OvY6rgehCuk5eGzBcvkFEn1T74qz1z",
-	"U5+rdJ222+2diP82pcPCjoiXtfFeemwdtappCiUxSE0Zr4RgTqgKOYq4WBAl1nU5C8nMUV4oUDvddLmU",
-	"sKQakog/dw2mgdEzTNjStAS5a2SZrhz0vZC3hPE7kd6BvV7IuDEMHp1AN+xCnQLMqaoVUxEv4XYMhPCe",
-	"jVtj7d5DoVzITSqBrAVnWmARgwPHmWzdLAc8Cw6uCKdQTa+U7ryB6ojLnOPFW5F5I+Ab/Z//9b+R1ZIq",
-	"LfNY5xIsneVySAJZKja2HiTifrY7RuvTFSWlxtIVn9+36zJqYRRF/AlNTJaAnV6+DFGojbvnOt10anNH",
-	"vJicKULvDXeM7O3YbOIuY24j8oZhTEccz5qUKAfaoTGuRO6EakqOgWOxlDGTbtyI7zClVjy2Zrds9yv6",
-	"L4qcemFUEU8AsnTj5TIpivOq7spYeawrVAKrGdsIVhGXkMIdtXU2GFiaVRsvbwl42uAga7xOqQpvIr6t",
-	"iq2vQflDH69sWtKF0ayy2mK+sW6gwowCH5PKraiVfduu1Tk1rG8uvXuZVjeFF++K+HJnyxw/hCWqqsA7",
-	"5Ijxyr1vFPyqiITWsm+TSgSfCyoT1Tg/6jgNVDVqFFrRsuJbVY4xanzxE8W50mJtGGg8p7Ustr7KdDWc",
-	"svcNtnlqWreHDni1uXXvU8GXRj6IbpdpsxCxW3sanK6Gc88UU6ZsyIF+qcFsjEki/pSG19fdLmOdiBtj",
-	"R/kziCr8MqryrYsTn+5XxoflEaQjrmZldts4dzupWMnYOzGxiDg6tZhyRKK9q38uo7PG0w8tjPQqXVER",
-	"9HkFEehYQ/I5NqgxccQbxgZzXOuI8aDZ3pPY3tfnxlp4iI9PkXBN5tSI1/h8inHp8wZwm2K33YIFhpwp",
-	"jqjwbMf+ezAif8PQgtm2AoH/tI21xp6uN3XOYhBSY+1/L8fe2zF2q6V+1sh2+YxneWne6Vygz8WVEeYX",
-	"l+X6uljiZVveVpFwYya2JGR7lL0vHMWSLHL9JM1n2KYkuj0Q+Om+twobzfdGZGdGhvwUnIMqyPHS6mFc",
-	"LDCoS0WveF2o645BKoTU9rjScvs8xCZSM8RMGSdgy9qK4oVn6J8El229etVaLINfp60hfN0Ske+psnl9",
-	"YxNfFjFEfffM//1zQGHEpwUaacW5LB+JfPFBFLhA3SowIq5bs0Wc1as7tkL1qjzJOkBQyVjH59OI+5IU",
-	"67Wb5SAlEoCxQbXU5B0G12YzzFAvfcaL+3HhCuaQtcV7Oy1J31Y95mMJYN1tezWo71DnWQkUZppPb5ZZ",
-	"ztbtqc9bV/tq2kyXdThftBayvRTnIutreUHGteISjM5qBShWqyN/zHHpsE5safQUAXo6Z6n58VyKBUuN",
-	"rBaOzd9pEguyEvcmR61XG7tNtkJdeQUiJOcpUAX2jpPhpS1HsFNHHDMpriviFvGnYRE/xpgnboCyf+9l",
-	"kbAaKwboG+/qt3cyu8DC2hU5db1GZ5GKe1UFjfxBDTWmNCF5JjhJcllkWS5wNn9nUhgudFxVhflpDvoe",
-	"3BFFjXneuKDOZj6YI5remm6Ma2F4KnJuRSyBOKUSEpLlMhMK3J0XM52Pscbn007E71csVdqGKPbGrcrx",
-	"hMbLdCbhjrLUfElhSVOyMAYL4+KExbpIgVIR09Q0YSr1Jcd4pyZGP3KPVc8ZSIXFzfi4mwVkDE3NijkE",
-	"IUyUF3H4CDJmRRYg2XKFMYktPFhDvKKcqTXe6FwRiiXUXYYS3hMS/xK5dmBdkR9IgG4KyyUkNWHEOsM1",
-	"5YnJATaucA64yqUDPuwwhkwJxh8pCxOus5Rh2R0Gv5lkdzTeEAlL9/Sk6pA8W4k0KQTBqH7MstSV4UnK",
-	"VUYl8HhTMKAbA9eSxX687nzTTUCxJbcKnSTMFYs7i46VA0WxskPlfJWg/RgLi1fZ40kM87ZryrnQBD6u",
-	"aK6MpbHxpoSFcChOs8+abrCThxQtZMYF7xactLNH3JZEm2RMFc8c2LtNlQuPJ7hG8mPOErhBOdq2EIYO",
-	"1+M6Fuu14OGGrtMbr73H+BtNmWagyIXV8YhX7iLjII4DXvVRSexmgt5mXMFVF4A7L2ky2NbZfIpSOGMP",
-	"hET8xkx6ATSxd/mPVxDfmsluSg4+TinyZKyqUJ/MU+g4Um8O+gPSJadns+vpu/O3k3eT09nk5MZTJHhq",
-	"0sxMKMXmKUS8vkB3AdGC4imLmU43BWElnGNziaATpCwGrvBc0r0OZys0yB4+dVk/9rm/vw8pfsYzRddX",
-	"9d5Ojyenl5PuXtgPV3ptj+GZxlO+RyO8oBOUj2v2Q/u85seudQbduLozwagfHrpjM5qxYBQMw344tAeE",
-	"KzzTeiQMrT4I+1nPmLcdxBcD9B7t3bzyf2Efw3T6Y9IByok9U98u7PaZQVErXQtPMM9oJMON0MTVzXl6",
-	"w8YLtJ5caF4z/10en20+OPtY4UN7rXzL0wonWKDWgLccDlA9N6visRjDVTD3ZtX0U8+pDn7/pVbrxYpH",
-	"O+z9WI+rFPdKy2r4SrV7WH924q3YdXnlLePFs8b+2qa/llB7RfKzujWP6f+jvqlqH9R6Zpf95gOpvl7g",
-	"ffBoUeLoESO49ZLqBWjJ4A6egc1U8ptxKwaIR1f+TG4LAjHbTJfGjgTH24NXkyxp6aFpgG+oPIo/PGLQ",
-	"J823pp+244++PP67W/IW4Of5try5/j+s+R/W/A9r/v+5NW+xgbtexq6acxd/H+8G336TQZ+0QdQ7TPrn",
-	"PvmN9yaswbJ5yCeasQsh9EPvUab17vrhAB8dk8ykeMpqL3a1Ir2geapdYjPq9RD+WAmlR0f9I9Nzq0Tz",
-	"fEqkELpTPOfhHyip13DJDkG89YZmrFoWfUNMWrf1Y8+k2SZ1uTGi/qHg6M635Spc3fmy+fZpU/nm97P3",
-	"6aHzGRTsjBWaBDzT8z98ePi/AQAA//8weNi5d2oAAA==",
+	"H4sIAAAAAAAC/+x97XIbubXgq6B6UrXjuU2K+rBnxFu3shpZnqjGljWSnNyboVcEu0ESURPoAdCSGUdV",
+	"+xr7evskWwdfje5Gk5THTrI38yMZi42Pg4NzDs4XDj4mGV+VnBGmZDL+mGS4KGY4u9N/cHaKxYyzV5yr",
+	"UlCmTnGRVQVWlDP4/vF3gvxSEamGM56vh7KayUzQEj5f2Q+SsrtHaFtyqeC/OfFtknHCWUaQWhIk11KR",
+	"FVpiiTI7Ccn1l0yDgOYOBsTnpgcR9zQjKVJLKhFlcy5WGjJEJSoFv6c5yRGsBSmue5xcnqNTzmS1IiJJ",
+	"E14SoTuc58k4yZorveCKzmlmlpomJRZ4RRQRMhn//DH5nSDzZJx8tVcjb69usvdhkHEhSIEVF8nj+zSx",
+	"aPqe52uNZM4UYRoduCwLO81eVvAqJ/cw2r/9RRoUkw94VRYE/pljhc0WNSBNxkfPh89ffOdn0cs5nOPv",
+	"ns9fHA2ef7v/7eDo+YuDwexwng0OsuMXh/MXL/Acv0geUz2oBUetS5KMGxBpKNKEwohSCcoWSZpIXokM",
+	"Wi6VKuV4b48FuLomLL8m4p6I/YOhBX6Y8RX0K0l2T4Q0O78/HCVpougKRjoY7X83GB0NRs9v9r8dH+6P",
+	"R6M/w1cDEReLYYZXWOBS8L+QTA0JI2KxHniaGIQgDO/3hwZHdQNYqsyWZKUxGNs+81XuncImnMEmJI+P",
+	"j2mLXku8LjjOEaAMU0bZwtKoJ1kDGjSQ1cp0e4StkSVnkmi2OhgddDnhv3glNE0TgShgbUWYMvQsl7wq",
+	"ciSIqgQz5P6Hm5tLJBVWlUQZzwmihilgO9EDlkiQjNB7kiNZZRmRcl4VxTpJkyXBuabij0mDSnuwYpu3",
+	"KBrwcjA62ryIHaFmHBWcLWDVTBFBJCCRMjSvhFoSgaoyx4rIzwn60WjU18nv094PhBFBM2iru+w/ocu+",
+	"6XL4hC6Husv+EwDbN4AdHO/e5eA4gfVLklWCqrUWZSHnyO8JFkScVGqZjH9+D5JLVqsVFutknFwakSqt",
+	"SF0SJIisCi+PMcPFWlKJOIvKbYN7zs40g5zW/PGPOFS6XPqFjhXSXu0/y8FSS0NcFG/nvbPH5OL7NHYu",
+	"dZaajPcPht99+9vBFBxMpgHwwuc5WwxyqSB5MlaiIr+dNb+dNf96Z01M6dLoD/axFGROBGEZGWhJQvIu",
+	"TV36NtJNNTW/TZEZCi0545WAI2DtzwsihkmoX3rhmDxgqv7jcFSLDCusHjWfKrEe4LkiogvJRbWaEQFA",
+	"SJJxlkukOILR0IzMuYB5We5EhJWvCC8wZT2gvBh5GChTZEGEBqJN3U0oTu03ffzlaM6F4Xw612hS7rSU",
+	"ya7q9X+ethgkPAA/JjQnq5IrwrL14I6suxD9SNZohe/cymU1W1EpDXy2q/JwhifzEF0BvqEjDrs9ULU0",
+	"Q+EVQXdkjTDL6x9AB7FyRepfuaALynCBHMnrEXilUCYIVmZ8Rh6QICUXKkUPS1oQJEglHdAwiZ4WB5jU",
+	"E1GJBIGDhOSuxdHoGBEhuBiiE6RJxo7YACbEg0RS0aJAMwITloKDTCZ53+An37+9ujl7aSZJUcUKImGp",
+	"WDXQhKURmqIqFclTRBnAkS1RhiWxVAjAAaOuiERUDdGfloQhPJOEqdQwk9cEpkhWhgthJPgGCEhBSmMG",
+	"/5WoAqCxdCgDuqZAAoYRkzRhWJ/R5wHN/EjWDVJc4Q+vCVuAjDl4/iJNVpS5v/djPGmERJfsTuSd9GRl",
+	"dhYYctY4nuWaZUsB8kEW61RzqyYHhVZcKt11Qe8JQ6zN3CnS6EVTKy+m6OurV6fo24Oj0bMhulkSVEsv",
+	"QI0XQpwVaw2XXOGiQJyRgVxy5SSC/Hf0AHsQQJ1hxrhqQQ5bQFckbUiTOS4K2dB0cbBCNC/4g+YVjA5G",
+	"+zU7UGn5heS9W2Zk6ifJTK9JtrfoPCcMjheD2HrFGrcOIkdsISHOKSnyZh/Ly5z5ZfmllFgt64XU8LQV",
+	"sXBt3XV8PrEbxW9j+E8WzQ1FMtBR+g2NltfKdDy7OLs6P709Go1uzy/+ePL6/OXtydUP796cXdxEdpHd",
+	"44Lm6EQsqhVhaojsxOh6zRT+gM4+ZMSpvve4qIgBJ9eSoD18mqyIlHgBH08LqlFXkgyoJEeYIWpnw3a2",
+	"1BO/FsdcoF8qItZIH1L6YNUKZTI+Go0AQ+Ha3r67uX376vbq5OKHs+663lZaebnCbEGG6NoA0V2UEXqa",
+	"Z7EVFoY8wYaF/2GGKgaikwtgXI2BYQQVDWh2RYPQ0LWX+Zg+2Vg8E4KLczbnyWMKmhcviVCUyBpAMBer",
+	"VTL+ObZpDeDfP9bw+F5Ho9F7AMwZYjM415LH9xGz6nucI+s4+JyKfaCAfyo/7N++uzh5d/OHs4ub89OT",
+	"m7OXXbKxgAdSG1dqSZiCGUhu5S+oG8HvVgvw8qhLHe15QwJxU8J8zcnyiiDFkVYJ2CJ1ZJMCn5APJcyF",
+	"MkG0HMaFBIVlM2RNUtv/4qTWXnYPae3vSlrvGKyNC/pXjeXPT1uHn0xbh7eXZ1dvzq+vz99e3L48uziP",
+	"UdclEU6/ywmjJB+iE23BI8XvCEM5J1LTwRLfE68b6H2WGS8JbLzXLypJBJpjWkjkXWCgmToDsEuFXQgj",
+	"gqoJg6zmc5rpD6UHHsCFP8FVZ4x/nGl/S4O8Dr84eXXX00Ngh7sS2CsuZjTPCfsi1HX0ydR1dHv+Etjo",
+	"1fnZ1e3F25vbV2/fXUQI7KQeEQX6WcXuGH9gUcH048XbP13cnlxevgYmBVzWUzXoA2hOYbEgCgWAa8PB",
+	"DN/c/qPmeX20CewrYlyJiBrSm/OKxcRoPUQIGKjr9fEqYmN1QPvClBkCugXFPSR7tCvJXgT4+vwke/zJ",
+	"JHt8aw3dmLrNskoIwtagojmD2XuAtB+AYVUJsqfFXoQS3NgNAeaGzbRnal7QTDU3/rhJk8e3J6+vzk5e",
+	"/tft2X+eX99cdyG9MQ4MxY2BQhBmiHygUtuZjtJi4DXHbVOr62msfowyI3qVAAIOJisEwfnazCi3LOX0",
+	"7cWr1+enERW/MaOOsSCsPbV2fi/xcQEmJmyBP1Aia/MT9a7qiXN0FvaFmbOmnc42+bX1cOXxrlx5aunv",
+	"SzDl/idbhPuj2x/eXkSspXeSwJY1/MXG4aC43bLQtwe/UpbTzO8vVRK5VBYjeZ3nH99jWuBZEWMTDUxI",
+	"Rl4bQsFp05TonXEb5LP/5e0nDXScPvZ3NpJ+4Ix8Cdo4+GSBfXB8+9O7tzcnt2f/eXp29nKTcRQ6Nq2N",
+	"Qj5khOTGGTsDzyts4y8VVxgVdEVVZPNbs4VkYI13v/F6oMY+HzTl38Hx7c3bt7dvTi7+6/bq7Kd3Z1Fp",
+	"3qQuIGiw8GeEMKTIquQCC1qs0azg2V29NAFEzgWSJb0jCAsBKNCLktblKwjOllG7rwtUw/KDkfVIbojO",
+	"Gr8wLXf2oAtwnNIPdpaEN5yjN5itnU+gFe3TQYLBiQvKbKL6MH4Tcek9hWE8WjUMJ2V5zqTCLCMxR+cJ",
+	"WhR8hotijSpGf6kIorVqjaXkGcWBj19UDGLJE0btmEDKmIVK83DCzvIFQTrGjy4LrLQptSCMALVJmwtR",
+	"z+KMPjeoolb51r+iYLA/c6YFZR0ansAhbrIqknFSVTTvenrT0HL4XhB8l4O90EHF9RILUrtudYgQKyXo",
+	"rFJGdWkuFDkcAGE3SRG3kb6Jpps79JiGYq0L5R+BCx2UMWgQLjgjwxAvOa/MGWUxY0IGMBUj6oGLuzMT",
+	"tdewU0VWchvMF41+NVJrtsFC4LX+mytcfAq2+xaYIjokQ30q8wdmvJaoLCoT3JF6XDjWjZ9JcQRxzjUi",
+	"90SskV0wIgbyndD0GLrjf25trltgc9u6mH3fESgNurzhJS/4IhKkdF/0iVHc18FiLrIlkUoA32u3/t+J",
+	"PHVsLxnvLqgTyuYC3+jVd1a3Lj0V6GZSiSpTlSBoyY0p0kcHwxir0/I1jaVxnX1QRDAt5/RxBMoVOr+U",
+	"mzgJJvDc0JmpTeefh5U8HXRm2EiFMeoKkqyiZlOoEHv9NkmTnELLFWXu8FnhsoRFjz9+SpJSJ3t2a05Y",
+	"K088SX9FbtTWyUwGYfLoeWR9YcJeGp+PbdZx+Wkt6rrX3kWitMfS5V6ZNjMTKASiQ6cnb06uTrR2hlmO",
+	"fBhW8zQIND1pZC87CWxtCFYkp3gA36xNauc2QlGn63m4CMt4rp3uq0rqOMCko0pPEi1SaoBBtXGaVbtx",
+	"QH4BJ0a0DdoKq1JpQEsN0A4cp4kYBcDqyXr5H7TE3hh4r1P3NvPdtWn12ErZ65y35oP339hgm+EaxW3e",
+	"Qr0U7Z3hYiXR1245+8MRonNEg2+KoyCPEloMD5CF4VmAacgfjCHXZBRuXuBLrMgNtPOiZIvIBlhAHjey",
+	"SttyR2tY3jcUos7OYoED1RocBXQRics20gQHDqcaPXRRGc8JqpN5ECMkD7KkgixetMIMg8msD8AM4IQz",
+	"w+B1OGHnzNHwAzFe/1KQnMwpI3mtb0hUgP0zDUc+00EoQOA0bX55gz9oXEn4QBmFAJX+YTphPhhriEEz",
+	"ZDCNIwkHAWXNoV9qETKdQGYvGesEFh9FptLFJYgJ40tyTwQugqlSUHwcfkDwmE8PkMFTSet9nBo0TwME",
+	"D7Ue3ZR04cIi6hvROStTJSoyhY0BmZY5Fwqd1/9+wEDhiiPtXGcWJCyR5Jy5lJzGllJpnYQmJGn8ngpJ",
+	"qiqXRzEvSKYcx7kU7wk7M8b/uHbm2G/oiuOVJ4whOg8AlERJFK4WgIV16dlzck+920/YUTwoab0iKpES",
+	"dLEgguQT9q6EUQAp9sRCOcmotELjjpASUWXQbpl7xnlBsFahuxSx9aKJxtd1t19rtJqo4zpBYx90GNYK",
+	"Oroi6GvKUI4VGei/jNr8zGG4Zs+QEobo3Ir1Odeetp8hFenw8PD4/dcusRrONiVwdkfEkBI1H3Kx2Mt5",
+	"trdUq2JPzDNo/pUkOgw3eD588UxvjB7VxBIBnL+C0YN2Q3sSpJxDUvPhYLQ/2P/2Zv9wvP/d+OBw+OK7",
+	"gz83LAO36pjKGRUNG1KKDMWv8Ae6qlZB+pYj5pILwzAz4q3mHH09qUajQ/If+1swjgborbmyQKUbnErn",
+	"g0m73EZYLj8Fcc/1OQxrCE/hMB00PDYiJB3VWfvoOEqtRuGqjUiHFoPJcMqIDQTkeIkFcP2VNj/tns1x",
+	"VahkPMeFJG1/z/lcC4bUpKtrnaVOphBECUrunXDmq7hVIREXbTNU1jEECHoHyWMmYc/ax7Rx9cQnm9bA",
+	"UFm7jo3s5CsiTUuqJIh+ODIWZDpEdu12bIlWeI1wITXZEQYjmIXAieKIMqA3m1UthlEJRllWVDlpeVt2",
+	"Qq1qmyYuhdYAgezQ+qeZG7/lSCiJ6PGNsFx/jLgBuoswE1K2uCSC8q2G8lWrecSHmCYvscKndhO2OEX0",
+	"tuJg72dY6izN1KNEN7EpNGgu+ApRBeRFpKIrIJIhegtJnf6qEWyo2/A2KZUtgtAcQkxGjm7gR9XzdlnK",
+	"f7/omsKtuG6b/B+WXBKkBIaMDJ0f3FiVWtYf73lRrczSNXNQYX0+VDra8JmZZik6abMG3hLxiqglz01e",
+	"XMPS36gjx5cY8wfYBQQ+Hrk5p6EWEAYbQNaRq2WZvp3SljlGTsiAfmhEyvh0bo8okx0Yhpt2wkPHKdSz",
+	"+k+lBLtuLvy2b1j2GsGqCzJXiFeqKQp2XtGbGMCxlWmNKBI/gp/RjKgHYnOYVgTLSthlqSWJcLJnZPKh",
+	"NCEnzog0exm/Vdjj4wP0wDRYwgjh1EZYSI5yHt5u0RNb1MKwbVpBii+IWhJhEu+a37rcGogGd5BpiAAg",
+	"C0we97VaPUjrEF6fGG3zwZpNiDNZL/Gl/RLqfVxU92nKdEWkwqsSUOdz1nhmMzTA0CxLwoA834CWivMl",
+	"ETre5rTf4YT96eTqYoxuQE3jpc1vM+m5lKHaPyVbLocg4xFRNmGBh6Z1/c5YF6GiG71+uJuSW/tzx31x",
+	"t3ZgaVmtMBsIgnOtjkAzQIHzTBlqhGFj8/lA4tZxg89mO7AKfF6ClIJIu/2dWerYX3MSfd/PX0IILv4l",
+	"2xVdM2Sa2OZuITEK86fJK8zeVmrTxS3wzRJQ6PQ9CuBeyoxHG1kfUqAiPWhZmeFKktQn/OMNR41yMQYf",
+	"M3bBBiOLtJ6hxQQkKBiDq9RaDtDjnCiwLYBunUvigbKcP8jUuD21KCA5kgRcDooU6yF6xQXCdhCa+esV",
+	"AKmTZWVZrIFi9BiiiijxFiungJSINlWt3GFg0GbyPfQPtqccRnY0TbRv7u1MEnGPZ7Sgau2n6DYmfQZf",
+	"a4PIh2yJ2YLkjVPC3b6IAxLGeDaAYEXxH7Vq1NuuRandsfsXHp0ibeLfY2IjqbcO2Ah/x08br9qEGnGt",
+	"537mGJdRDiOCweZ3ubPPQNU4AccTNkDTvxLBp2PEeEMn66yNymaosjcUpQclv1S4GMiyoGo67htPf0a6",
+	"abFukFqPNdq4q0TNfSBiXYLODw3LgT9rAKJu6WYMbLeLVf2mWD3uPWE5F7uNZ9r2jJ52dWI+D+5Cmc7G",
+	"GLaRZBbkKgx775H1BYc7GPG0FeWSqAu+J9zkvY1WibeOSBME0v90Kkk+DLby02Np6WcJ/8Xo5hWmRSXI",
+	"FcEytt4/LdcIOwoFB0mM4fVx1+17Cj87apibeQyLWuE10DrIdAzJXeaX+oIf4BS+xl09Ofq6JGKFmSEs",
+	"l6uMuEBVcJnjmWFe00cOyIclrqQi+XTs96xp3dS3bHU+kPXA6N5mKDh/eeVkgMVLTnOrd4O+p0h9DxOm",
+	"sENRVVvWQckF5zgzd74bnN/AUpImnWXYgA+v4ltLHElvjv2T+1rU2/Vr619vtrvJO+zR4RZkN9/MtdHN",
+	"IirbgsDhpykoxpdx43CHsys0SnFgkn7Os+pLCV2xgRsjYxh66jHT/9sLdYusGPH05Ur1e/+61nhDSejx",
+	"AaTAONoFyU2ii+INXWVLpkvXPa4n3JKBthHQTdPtkJzmVarY3YWK1JZ3a4UdBXJHd6KuQ4CBabFU2o9i",
+	"zJja39pOLmu6PmB5BOvb36HPo+tY/lIca9ek6WgXD9UOlOGXrrPctmDaFmkIh25jyKbhOcRC0/qzRm+P",
+	"m6jfNfT/kySJSI7GpqUB422XJf1Jgy23qrEy2aJPdAwj0f8gZa9DaLRxWvWZAT1fw93ajC26OcHtUnDF",
+	"M15s23aMclJQTXel7TLUJZ1S9Oanm5tD85/nKfrx5NWPJyk6efPTpab7i5Oba+Q4/oHkgVoE3ZM00f3t",
+	"f5/DpZs3P8HP0DFJEz1eEhY4c/06SDEKSuRMML4nky3mqhbhRmUJXYVO8Gqx1DKr9niY6A4iLC85ZUp2",
+	"d3kWHkab9SeYu5Hn26kcGovQduqtuoiiyfds2aC+XB5lSHGmXeE2fqV9eMgX4wtx0ZnDIsf7supAVyOA",
+	"7Sqd7nASOZ06P1FxbzAcGy4TzGvkNuaOMJqbujp6L4c7+1yzIE64JdGrjilCP0MVT4IV5LTttzuAkSJ9",
+	"MSKIRDJ2JoMfH/aWOxBBNFK2Ix3sHwwPj57vppE8OfXZWpzb+jQN4Mc0WWC1JILkm8OHtX94k3fXokbr",
+	"5x4Zuq4ectPEvY93lO0Y9f4RWmrJ/0FdVayX9Pyx+0Fpj25zZ4EqWh5hWzxK5/kwrhBh+VMotMSCMHX1",
+	"lEI/LQBMIo39N5XmDgzi8wjo0FtUTBqx1Bqnx8TaBTA/tmkdH6naTB8aLqmw0HoWR3MsdkB+nCwsf3an",
+	"K6jU7Ni4qe8UyXpBElUsJ8IX3vt8Uec6nrO7M8D1+gOViot17xFsnPeyXQ+KF7mub0WF3D3YbIY81SPG",
+	"1gHkvFvSyU3d0t21+XUio7s1XQIwpS3zbVxegBklQ+w1kbcrG7djv0GZrDra5s+8mKZodvzU1uGy4qAL",
+	"vCBBrnlQNdUSfAs33jSq7wYHBX5jZQNtUp9xkYYZXC7lplWRUuepMo4mAUFMkkbt4LB5K0PYWbo4U/Qe",
+	"h2WJNbGi8AqfhjS4w9fRFD9NUpnwts8zDsu5hblkPp1zRkzNKsVt+VRd+e9cu17hoy/zN1tHkbvCd6S/",
+	"lGOKOJx2D1TCuABMnV5ZDwgJD34ec7mMSlOiJo9VaU79L2ZpjmhoTwk5lBNB790GmaTKJ1UW/AwC2G4M",
+	"FiSugQWiQdNaXmWbeeEzivBuie6t0jTS5ZOFaNs3bFEdB6xf1vxoVadWwVHK8tCAS9EKq2zpcOssNc8E",
+	"RqohqkzEwgZbAh13OvY2oBYm0z2v5A4irU34oB2S2TRIp20zOtCdI0mTdp9oXMCgKX5Z7wSV2BwWgR7S",
+	"lEegQZ5WQkYrHurfAX8lljonaprpn6bwm85WqLVQmGmITnRdUe9RFETzBuNoxQWJaEOhCqe/7Xzdz6x7",
+	"6/U+N2w/hV335K5c1tETe/YGBGfoyO52M37kLQOcZaTUMrFSYSwGGjCuvBa5JsrQkzEiKFtMx7H0Mpc8",
+	"0h+Cpq7YgDdHJgyhk44qLhVeS5PbSaVbnSlgS5U0Oi7sm4XQUbuhZQMfGJ1NQ8jNGeQZ+zQ9DVRgNuoB",
+	"9fG9NsMF7SNmph7SjyPd1RKQopTdWfCca2G30ZzuYDqb8FhzHxsRmLp3iiQhaGqCnXqoqbVNvVRgGSmK",
+	"XrLw32OHb1MqWPpKnBlrKuoHG5GkicdiErhXEmMw63/4+TaIjz/iguY9QfK3lcp4rYzmYm0tt9p9FhhU",
+	"nYBcQ4Xe9Wjr3OaOHHCkm/S1U8axbf4rrANdYHFrLMWCbm+kiHU8LNBNFItEOloyzcyfNrHbRUi/yGuk",
+	"4rcW4Wtq++tyTPGIGW7vUzhzvmaQCdPRb4SVXbfpifg9EYFjwPgj/gLaKilwKYGzgJvA1zdhteyQ9pab",
+	"KagZZkWkqGKKFr23DSeMi74Lh8FVniE6CUNVhsxXiMoJc0g3bF6Ty9Ro2VY2SC2/b7w9ANxhEldlWOjZ",
+	"Xz705wKdd649Umlvb3DmEQVYFbXK3RQRS14J/a5Djqn+7wMhd8W6h9ebzueuha5Tnna/AmKSIgGNDm1p",
+	"9FYI0qFOc6aQD6Wwpc9ZXcmtYlS5O4w2ut+6caExOW3fh5kiGuZd2CFjV0E/v3DaUK2jG/euZ4qx5TVl",
+	"d6e+SGtMkYODLqjj6vAi24VcuUAuY8btV21LMwI6CBYmG5TZtF8b0nE350x6p62rNdxSx+Dk9PTs+vrm",
+	"7Y9nF70Y0zVmbvgdYcES0+Ty9cl5b6fLAtNm86uzV1dn13/YONUVmQsil+25ujUJakTe2OoEwdM5rY/j",
+	"xiI7hQzarWOOexUk6NTth+528k30MxqgN++ub6yE0bcKazg04zH+0JAFBqNpA94W4t5vcwq1lhMlVl8W",
+	"YOPFTFtnoH6TwCdpuax+NIDFYcQ4G5BVqdYTNn13dT7w5Rym+jq51rXfXZ272kkvL64djav1GDTcb5C7",
+	"A7ugalnN4Amh8H0l02aFaaH4OGPZfPCwGJg3Qgoi5f8sdOlE+DCkXM/GgCckZH8MbBGFd1cXDoB3785f",
+	"2nkrwcZQLWn8gnw3y44OR4Pj7BAP9vfz48HxixfHg9F3o9HBaJQd4xcvYORAftT39WsXhx02BH4Pmu2V",
+	"VVHs7R8cmu/7g+fPnw/2Dw7hraZvW/cTnvjQUl31SdAa9durQzR8rxGJ1fRUdk2oiEDe4gc1I+4etPgU",
+	"L3bfLQQc16dCl8lZf4ZekFBqMiC1bTEjYYkBF3s2Nlkw7hBdhwUKzDB+BFsBoakV/DPmpl7HHVKxMqT6",
+	"oxZxwfMtnaTcEEfbjilIJ+g7n978dBmDLTU5Bj294Fu8l0lW6JusBJ3zRzy/w/HeJhGi727dTzc3/d2e",
+	"P72bzq/o6QXfejyCrdPUJYNEDkdfuGRj0QXT6jGtR9rS4zKYETSjOCHhPBe2lqM5gax+I5dYv8TjdB+S",
+	"o/odIEkKc4PQwTKeMMzQVEvVKXp39VoT5hQIYGof0Fn9otQUcWH+ZVtZoTUT/M569Kd6c63TQO/YNJ0w",
+	"jKZ3QA9mAP1PO4IPa2R8tcIDd+snRzPOlVQCl3Z08wrOVFMeDKmBwqtfSjMm/KsJlI3LOLiABSwoDAP8",
+	"8KMqtoFhTlA7OZALeEBOGnzpjsypbGi4U3f3Duy6m9fXSG8sQV9bNKcOk6nHSOrX4YB7lk6YfxjJuIJM",
+	"YNm51v3NLussyopKqjqM0LzlZxLPFddveLVVMcpQVhAsdOGknuPWOZ3D43VPE2fzjNXvSyqALBkn/8tU",
+	"7/ibXu3v/2bW+vu/6ZX+/m+wGX9ThXw2nkz2JpO94b/9LnrOdayHjV7+Zmtru8hYxj8cAujMV8WRiBR0",
+	"QcFqULzJP7N15Nxy+u2mMh1m3JKINtk4Ds209YdVo6ZGQe6JqVm5W5g4eko/6njRuRnA3Jqt/9hoz9ld",
+	"9dIqdYLO4TKmKtw0vE1NOUlYDrdkI2qUM7iNWW6uIZg35iwV18PaKKev7GCKlLDQ52Jcz26EHCvydy4s",
+	"8xTdTajdcaKb92Hln3CBXTXTrhbKfYVvPjUe4ZodHR4eZkcvBkfH2WhwNH9xMPhulH87mI/I/PhwNN/P",
+	"jl40xcvPePDXk8GfR4Pjwe3434cgRqD8Tab/n3x8fP9xlB48f/H4uyiI7pmQay2dTbyo77XIj8lM//XK",
+	"rb75ZPBXe00pNWwamo/uQSy9TD1QDRFsE9AELwkzgXPzr1POGMnUO1GEYjiQvsMHUhQD/e7EHnSh+aBR",
+	"Gq2eojGgqZZNbKXLlzyLR4fyKlMo51nlzxAQU+YeeZImVQOs0DANles9Uzcx/sS9KfkZKZLw1Vfo7T2c",
+	"cuTBeB7NKMgPg8JxnOg0DqN2yGHC9Bkd+onwjFeq70lmrG9Pd5//xxPmDl7yoeTSnApYOy+aWTKyDlkN",
+	"J+yrr9A5UwadlDOzHpkRhgXloFsQSUh4rNfvPcOrk6zhWJdGIzPomNRZtZtRgL4mw8VQ/3xtJ3FleZ5Z",
+	"9Ni6qk9F0YTVOPp6IQhhS15JghZYEomIeyvnWStl0+YK+BjRhOnkE9KDRV3i1TdGN6TIOHqrn07gonYz",
+	"wQENy6ayrpNhDvLK+mMzzv5SscxrbtbBa1jKKpaGwMNy0lAzYThhE/bNN7ZwHAxo3qAEVMAbAeNvvoEW",
+	"P3/zje7o0GyRZzwC33zz/utfwy97s4LP9sT+8HCvwZZ7J5fnt81fzl5d3L6TRFwrLtbwr1Msye3+cJU/",
+	"AzC/+koj6mXYR/9qnMnyqUyXRhNeJuwTKKqdAz5h9bM2jnpcDauQUzDKiFCYhg9dWqLydDRhfN4puGXL",
+	"qDQYKA43XiwEWZgY0K5rgAbAZ9rGt4nMXbCgqwsqUHYPgTJzzcQ8IeL8aI16CixIzvYWgZwwjxTtAwfT",
+	"zyby9EXVNTaxIGjFGVVclyW0CVpURDfLpgPpCjK6rKZnTceUNkiO1YTpYCpDipdOCLhG//d//x/ZKuZs",
+	"4KyXg3JSFnxtQlQT5ma7p7g5nS8fD5LOf/45zsuaCyeTCdvCifmC6E7Png01UcNxzxS8shrOPWF+crDO",
+	"HtzdvJ7NRvYdxXaeFCCMKh0h09XV/EA9HGNDQZDTj04JU0TIsBL2hPWIUkMerdkN2t2K/odE7l4QEFRO",
+	"CBTYsHRZx7XC4wqkvK4ULLlOsYgBrAOOBbnHJr1BK5awajjlh2g3gaNR43hKBriZsDYrqr5rJeF9Lpd7",
+	"Eib4mWMgQIZ3YgppVxRFX/totYcaVo3TvfbEdIlXcVQ/bWCQ44YwQIUM3ENHlAX30DThhyRiA7ltUBFn",
+	"M45FLjtZfanlQNmARmopWr/uIIOAWwMvbqKskoqvdEq3ebiZClsxFboCpjLv5whxGtzJb6kOsr7M0dp7",
+	"KD0D9IFUnKZhIbyfezqYDtW5HcnUPSStz6UOsrVOMmHbOLy57jiNpRMGwg6zHYCqE1GBle+snri9X60f",
+	"Bpn5fN6RMv0yzr5h5Fdy4nMm5hOmDzWXt+uO+l0RXXbe7I8g0rF0wCL6zPNA6IN1iJ4igzoTT1jkflkR",
+	"3nC2b6K093VXXUsnkmufH1O2MBxkIU/YrgPYTTHbbpwFAM65HlHqKKT59/4Y/UmrFtS01VfGiqLvftZq",
+	"3cSsVkIaqP19PfZBz9hRSb3TyGb5lJVVLd7xjOszV9lqhnZxZaVu/RKvY3ZbQOG2BGhIIe1RDj5xFAMy",
+	"r9RWmN9Wqp5uf9yjCOjLb53mB2PUa5EFNycdOI5a/TVQhG0FFD/7xqThxh5vyAw2htSN9plS1rhAvZs8",
+	"ArCMtfXNN9ELG/rreVSFb0oi9DWWxq7vbOIzr0M0dw/+C3skTCHxc++NNORcX2GYuJTwSWIVdcPA2uPa",
+	"mm3CaDPnvqWqh/Qkmg6CwGI9uTyfMJcRbk7tbpJ+7QnQukF4AeCNVq5hM2CoZ87i1ftxZWvcadReej8D",
+	"j9yuaFRY3mQANo9txwbNHUp3MqC0pbl9s2A5rTdDnrau+GpiomvCPnktqL2UCYus5St00kiD0tpZI1XK",
+	"cPXEhTmura9TtwQ+1Q56W7wN9Ik5LYBW/cHmXinhc7SERBzMmvXD7SYbog4ecB6iy4JgScyrJYBLkzhj",
+	"pp4wwChhKiC3CdvuFnFjnLDcDlD333vmDVaQYkSfjffN9zhKs0Av7bxN3cwmA1eODJ1GLlCDQZTmqCo5",
+	"Q3klvJVlFWf426bApzb/B34Ki2g0kOeEi+bZ0ilzSOE76GZSUjNTGVXpAuZZgQXJUVmJkktiX7FwGfd2",
+	"pHTCIMldKlefG77JSkdoHE2XgkARX/hSkAWUEgWBpfXinGbKm0AFz3ABTagsXJVQEKra66XzPoUmUanr",
+	"kepMeeOQsRWpWre2tBMCtLwJIx+IyKi3AgRdLJX02SErAlkwVK70621LXai2VAOqKXyPC/0Xr5R11nn7",
+	"QBAyKMgCnAMhMeo7AyvMcgyOMHudiTBZCev4mDAHpiBwHknjJlyVBQV2tBWvBb3H2RoJsqgKZxdV5ZIX",
+	"uScEYP2MloW9HCUwk/pScbb2CBhkhClBMzfeYLYe5ETSBTMMnefU1ne1Et3UFHP1Ra1Xzt3dMh8zbvxV",
+	"Jjyp1bx2GVjGFbJVuOi91TcFmXPrxen2gaol0Mm5FI3LDLLYPCbN7BNm8oeIvjzjHkM1r5XUhxJ6qdeI",
+	"fqhori8RgMekKSF0QN/0uIUoPGfDNV4VU8e9p/o3XFBFiURXhscnLHh3UPEaA471NZPUdc6aiPNYtQq4",
+	"PSVnRc9szkTxh7FzhEzYFCa9Ijg3L36eLkl2B5NNawxuhlTj5ESGrj5RFSS1oE6fj/bRAMF72udvLl+f",
+	"vTm7uDl7OXUQccgjxqjkUkLsesKaC7RPChmneEEzqoq1B8yvwtoSSZoUNCPMVMhjJv/FJPWgg+GoE/Z5",
+	"eHgYYv1ZxxRtX7n3+vz07OL6bHAwHA0hyGiuIygd5duo4cH7oe6JpWQ03NdzfhiYw2CQhTuTjEfDFzZs",
+	"hkuajJPD4Wh4aAKESx3T2qCGwueSmxwtdzFX9+GspSOc2jHs7ZFYIN4PsLexd/dh0CuSEXrv+AfMAcyQ",
+	"iam3Lxc7y8Df122oJ9rO6BjDHdXEpqs4eCGc65+ghtBj4sAl3cfV6meAep9frZu0XhtNt7b312+z9eCO",
+	"rHfpUmr5kTy+9wUSvuf5eoe3f+vXZLcnTrYvg0ded32pUxQ7vjPrZAiDcqGzF/4OHfrdi7J1BF2JiuiQ",
+	"upVZAPrBaPSZlxpb2030hlfjQQjK6rXUmr/3Gk0v9TZNkXmHtv1QSVBDM0eMNx7QlraAWfBU7qXP4B2c",
+	"mLvX2569Lese2Pb4lQ/nplAi/e9PZmHuaHAJ09DIEIWfgzvlAYpbqHzN++rGvoZUM5dUZm/8Bg/91+t6",
+	"Urdu/sWv24Sj0aivk2eUPfcm+GiU6C77T+iyb7ocPqHLoely9IQuR6bL8RO6HOsuB0/ocnDcSHjRAtyl",
+	"m/jb2fEE6PGGM/Q9yF5ZrVZYrPVpZup47uDaC8zj+NsmlAUh3ZYHDYgJL+AYSk7bg4c2uq0riotEP9S9",
+	"0X21QR84i5Wv2qYGdDr9QxWBiN9wd1Wgu/7flIHflIHflIHflIHflIF/XWUgcoS21IHLUNo5bcBa/6f9",
+	"rv9fpQ+cxQJk/RpBUJVlQSL3ta40t4SP5tRvzloXZ+B1NyHZINC+4lIhQTJdN09XfkNX/lU97byZ00K5",
+	"GwbGmZYiKKeYmmn8m6pCXzbUXv5oSW/9RRAoU0OZl8ZaiSgx3O80lW1qCQs+uro4zrR7/EPJnSuLnl99",
+	"3rdKbrBibSHxWLVHhK/XAgBRaPxLRQTc27POKH9j8SkCtb79uAso7slx2Ic+MODbE4EwtS93A8HRGFb6",
+	"xrt+k0HDZHZcdbL+XdL/psT92DrsRK+EvitbL2e3JP+nLGXmXM5fbhU3/AutwcR4jQJOZW9p7Bho7TcB",
+	"dqOYVvmzzVCG9UQ1fJGSojHQ2kVPQ+C2oulN58JT8CKxAaxv3oKuqGqpDvbJ14NR+NLfaLTt7eAOWuLC",
+	"DsuGsDN5WRAKo7zStV2K1NdSi9X96iM7PdxGvL3/4oo6yOmYhhgtjhZofP8t9a1PVWssisaC4LytxgCC",
+	"N2oAjQJXgS5y5apHRXWOvY++Vuaj4YGCqOjDRCwjhayrUbnsd/NCq4ny+sDrEF1wNK+EWhLhw7K+dFkd",
+	"JdcjYVYXHQ/RgfyLxjaKABAUPqDYKVCGDIyFezmgADSug0pggdHIOCLzOclUaiuyNdcFTXx/V+4LcWFf",
+	"+IExYQjI8STCVC6zk8QcGPDJmrNf2mXhNzOJ8PzBP8w4d8jRFmF7I32B2WAzEZZrli0FZ7ySxfpfQWj8",
+	"fYy0XyuarHxoCae2dNgif9LtZo55nLZR0bFb3OSJL0qk/png+o155R7HlabUFtdFt9wL02lzKFdh18qv",
+	"4LG1Fc4Joua5N1eutjBZPJHCv4Jkvk6bdTv5Embe2dSojOjvgHflyw9E/TMIl7+z50/6l0bMc2v1jsGu",
+	"1k+X/SY1OlLjS2gnzZhLvTmd5092kg+BfjK+N0UzSRgZaYmNysqMe19f00/dzKvLICdG9kuIuizJbI2m",
+	"YFxP7e1+WzdSBvWDCAqfpNU/nF/KtHWVzFQlC2/WhanGuqhf1I5zLmlz2cmXuQy9001h1nybiv3qp5i1",
+	"ChdUnnSugH6XeFMs2WKn5HPJpnhpaq+01Y7mh6Dq04oqZRTOLR6UZlzjkzwq/+iQTyAcHSoUdzxBUh3S",
+	"C7ETqM9dTvhHxHqC8rhbQg1UmnX1cYnj/pof5phBvqZ2TvbEHn6zRHd1sKefLVTvhITsVDnWQg12zNcm",
+	"a77Esen0aC+6t5rGzwYcU2BJNzV5hR9xSa84V497Gxe6dz8a7kOqIBYUdA9b71R3bfiTdKLieG9PpzMv",
+	"uVTj49HxftImcZ3/y7lKXbk5Z9q3ajKIFOn7E1Nc0rAOkq1D1fxxT/IVgVTEKRDqe4+5rvJteSuIU9j7",
+	"idtvBdbydOfIx2P6BAh6kze6AOyYitGd/pIXRUx7MY6JUIdxLrtiHcQUHWFaMDp0+fj+8f8NANIgy+Rx",
+	"xAAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -26,6 +26,7 @@ import (
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/server"
 	handler "github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/api"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/backend"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
@@ -47,6 +48,7 @@ func main() {
 	// Skip server validation - we don't want to validate server URLs
 	swagger.Servers = nil
 
+	e.Use(middleware.Correlator())
 	e.Use(middleware.DebugBodyLogger())
 	e.Use(middleware.ZapLogger())
 	e.Use(middleware.JWT())
@@ -85,7 +87,12 @@ func main() {
 			Fatal("failed to connect to mongo Database")
 	}
 
-	h, err := handler.New(db, pdp)
+	clients, err := backend.NewFromEnv()
+	if err != nil {
+		log.With(zap.Error(err)).Fatal("failed to create backend clients")
+	}
+
+	h, err := handler.New(db, pdp, clients)
 	if err != nil {
 		log.With(zap.Error(err)).
 			Fatal("failed to create api handler")
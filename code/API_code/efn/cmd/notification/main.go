@@ -40,7 +40,7 @@ func main() {
 			Fatal("failed to connect to mongo Database")
 	}
 
-	handler := notification.NewHandler(db, conf.HTTP)
+	handler := notification.NewHandler(db, conf.HTTP, conf.Delivery)
 
 	log.With(zap.String("address", conf.API.Address)).Info("Starting notification server")
 	err = server.Start(handler)
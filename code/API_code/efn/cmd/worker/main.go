@@ -16,6 +16,8 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"expvar"
 	"net/http"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
@@ -26,7 +28,6 @@ import (
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/reciever/worker"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
-	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
 )
 
 func main() {
@@ -41,17 +42,14 @@ func main() {
 			Fatal("Failed to connect to mongo Database")
 	}
 
-	orch, err := orchestrator.NewDummyClient()
-	if err != nil {
-		log.With(zap.Error(err)).Fatal("Failed to create orchestrator client")
-	}
-
-	handler, err := worker.NewHandler(db, orch)
+	handler, err := worker.NewHandler(db)
 	if err != nil {
 		log.With(zap.Error(err)).
 			Fatal("Failed to create worker handler")
 	}
 
+	go handler.RunWatchdog(context.Background())
+
 	mux := http.NewServeMux()
 
 	// Health endpoint
@@ -60,6 +58,9 @@ func main() {
 		_, _ = w.Write([]byte("ok"))
 	})
 
+	// State of the backend guards (circuit breakers, rate and concurrency limits) and runtime metrics
+	mux.Handle("/debug/vars", expvar.Handler())
+
 	// Normal event ingress (broker deliveries)
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		// Ignore explicit healthz here (handled above)
@@ -16,8 +16,15 @@ limitations under the License.
 package api
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -26,11 +33,17 @@ import (
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/server"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/backend"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/inline"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/scheduler"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/middleware"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/policy"
 	servererr "github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/server/error"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/server/request"
@@ -38,42 +51,61 @@ import (
 
 var _ server.ServerInterface = &handler{}
 
-func New(db database.Interface, pdp policy.Interface) (*handler, error) {
+// defaultListLimit is the page size used by ListReports when the caller does not set one.
+const defaultListLimit = 20
+
+func New(db database.Interface, pdp policy.Interface, clients *backend.Clients) (*handler, error) {
 	sender, err := event.NewSender()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cloud event sender: %w", err)
 	}
 	cfg := config.GetConf()
 	return &handler{
-		events:   sender,
-		database: db,
-		pdp:      pdp,
-		config:   cfg.API,
+		events:       sender,
+		database:     db,
+		pdp:          pdp,
+		orchestrator: clients.Orchestrator,
+		inline:       inline.NewRunner(clients, cfg.Gathering.WindowSize),
+		config:       cfg.API,
+		quota:        cfg.Quota,
+		windowSize:   cfg.Gathering.WindowSize,
 	}, nil
 }
 
 type handler struct {
-	database database.Interface
-	events   event.Sender
-	pdp      policy.Interface
-	config   config.API
+	database     database.Interface
+	events       event.Sender
+	pdp          policy.Interface
+	orchestrator orchestrator.Interface
+	inline       *inline.Runner
+	config       config.API
+	quota        config.Quota
+	// windowSize is the length of the windows the worker fetches the time period of a report in.
+	windowSize time.Duration
 }
 
 func (h *handler) CalculateCarbonFootprint(c echo.Context, params models.CalculateCarbonFootprintParams) error {
-	return h.handleReportCalculation(c, database.RequestKindCarbonFootprint)
+	return h.handleReportCalculation(c, database.RequestKindCarbonFootprint, params.IdempotencyKey, params.Prefer)
 }
 
 func (h *handler) CalculateEnergyConsumption(c echo.Context, params models.CalculateEnergyConsumptionParams) error {
-	return h.handleReportCalculation(c, database.RequestKindEnergyConsumption)
+	return h.handleReportCalculation(c, database.RequestKindEnergyConsumption, params.IdempotencyKey, params.Prefer)
 }
 
 // handleReportCalculation centralizes the shared logic of the two calculate endpoints.
 // It binds the common request body, authorizes access to application IDs, creates a job in the database
 // and send the CloudEvent.
-func (h *handler) handleReportCalculation(c echo.Context, kind database.RequestKind) error {
-	log := logger.Get()
-	requestID := uuid.New().String()
+// When an idempotency key is given, or a requestId is supplied in the body, retries of the same submission
+// by the same subject return the original response instead of creating a new job. A supplied requestId is
+// scoped to the subject: the job is identified by a UUID derived from both. A retry of a submission
+// interrupted before answering resumes its job once the lease of the key has expired.
+// When the Prefer header asks to wait for a small one-shot report, the report is calculated inline and
+// returned with a 200 status; the asynchronous flow takes over if it cannot be calculated in time.
+// Requests exceeding the quotas of the subject are rejected with a 429 status.
+func (h *handler) handleReportCalculation(c echo.Context, kind database.RequestKind, idempotencyKey, prefer *string) error {
 	ctx := c.Request().Context()
+	log := logger.FromContext(ctx)
+	requestID := uuid.New().String()
 
 	req, err := request.Bind[models.ReportCreationRequest](c)
 	if err != nil {
@@ -81,7 +113,162 @@ func (h *handler) handleReportCalculation(c echo.Context, kind database.RequestK
 		log.With(zap.Error(err)).Error(msg)
 		return servererr.SendFromStatusCode(c, http.StatusBadRequest, msg)
 	}
+	// The fingerprint is taken before any default is applied to the request.
+	fingerprint, err := requestFingerprint(kind, *req)
+	if err != nil {
+		msg := "failed to fingerprint request"
+		log.With(zap.Error(err)).Error(msg)
+		return servererr.SendFromStatusCode(c, http.StatusInternalServerError, msg)
+	}
+
+	schedule, reqErr := h.validateRequest(log, kind, req)
+	if reqErr != nil {
+		return reqErr.send(c)
+	}
+
+	appIds := serviceIDs(req.Service)
+	subject := middleware.CtxSub(ctx)
+
+	if err = h.pdp.HasAccessToApplicationIDs(ctx, subject, appIds); err != nil {
+		msg := "failed to authorize application IDs"
+		log.With(zap.Error(err)).Error(msg)
+		return servererr.SendFromStatusCode(c, http.StatusUnauthorized, err.Error())
+	}
+	key := ""
+	switch {
+	case idempotencyKey != nil:
+		key = *idempotencyKey
+	case req.RequestId != nil:
+		key = *req.RequestId
+	}
+	if req.RequestId != nil {
+		requestID = scopedRequestID(subject, *req.RequestId)
+	}
+	resumed := false
+	if key != "" {
+		now := time.Now().UTC()
+		stored, reserved, err := h.database.ReserveIdempotencyKey(ctx, database.IdempotencyKey{
+			Subject:     subject,
+			Key:         key,
+			Fingerprint: fingerprint,
+			RequestID:   requestID,
+			CreatedAt:   now,
+			LeaseUntil:  now.Add(h.config.IdempotencyKeyLease),
+			ExpiresAt:   now.Add(h.config.IdempotencyKeyTTL),
+		})
+		if err != nil {
+			log.With(zap.Error(err)).Error("failed to reserve idempotency key")
+			return servererr.Send(c, err)
+		}
+		if !reserved {
+			return replaySubmission(c, stored, fingerprint)
+		}
+		if stored != nil {
+			// This retry takes over a submission interrupted before answering, which may have created the job.
+			requestID = stored.RequestID
+			resumed = true
+		}
+	}
+	// forgetKey drops the idempotency key of a submission that failed before creating its job, so that it
+	// can be retried with any request.
+	forgetKey := func() {
+		if key == "" {
+			return
+		}
+		if err := h.database.DeleteIdempotencyKey(ctx, subject, key); err != nil {
+			log.With(zap.Error(err)).Error("failed to release idempotency key")
+		}
+	}
+	// releaseKey ends the lease of a submission that failed after creating its job, so that a retry resumes it.
+	releaseKey := func() {
+		if key == "" {
+			return
+		}
+		if err := h.database.ReleaseIdempotencyKey(ctx, subject, key); err != nil {
+			log.With(zap.Error(err)).Error("failed to release idempotency key")
+		}
+	}
 
+	req.RequestId = &requestID
+	// The runs of a periodic report are started by the scheduler.
+	sendEvents := schedule == nil
+	var job *database.Job
+	if resumed {
+		if job, err = h.database.GetJob(ctx, requestID); err != nil && !servererr.IsNotFound(err) {
+			log.With(zap.Error(err)).Error("failed to read job of interrupted submission")
+			releaseKey()
+			return servererr.Send(c, err)
+		}
+	}
+	if job == nil {
+		// Retries of a submission are answered above without being counted against the quotas.
+		if reqErr = h.checkRequestQuota(ctx, log, subject, len(appIds), time.Now().UTC()); reqErr != nil {
+			forgetKey()
+			return reqErr.send(c)
+		}
+		if reqErr = h.checkConcurrentJobs(ctx, log, subject, requestID); reqErr != nil {
+			forgetKey()
+			return reqErr.send(c)
+		}
+
+		job = newJob(*req, kind, subject, correlator.FromContext(ctx))
+		job.Schedule = schedule
+		if err = h.database.CreateJob(ctx, job); err != nil {
+			log.With(zap.Error(err)).Error("failed to create job")
+			forgetKey()
+			return servererr.Send(c, err)
+		}
+	} else {
+		// The events of the interrupted submission are sent again, unless its job is over: the worker skips
+		// the ones it processed.
+		log.With(zap.String("requestID", requestID), zap.String("status", string(job.Status))).Info("resuming interrupted submission")
+		sendEvents = sendEvents && !job.Status.IsFinal()
+		prefer = nil
+	}
+
+	if wait := h.syncWait(prefer, schedule, len(appIds)); wait > 0 {
+		if report := h.calculateInline(ctx, job, wait); report != nil {
+			c.Response().Header().Set("Preference-Applied", fmt.Sprintf("wait=%d", int(wait.Seconds())))
+			return h.respond(c, log, subject, key, http.StatusOK, report)
+		}
+	}
+
+	if sendEvents {
+		for _, appInstanceID := range appIds {
+			eventId := event.EventIDForApp(requestID, appInstanceID)
+			if err = h.events.Send(ctx, eventId, event.EventTypeGatherInfoRequested, event.SourceEFNAPI, event.NewGatherInfoData(requestID, appInstanceID)); err != nil {
+				msg := "failed to send cloud event"
+				log.With(zap.Error(err), zap.String("Event ID", eventId)).Error(msg)
+				releaseKey()
+				return servererr.SendFromStatusCode(c, http.StatusInternalServerError, "failed to send event")
+			}
+		}
+	}
+
+	return h.respond(c, log, subject, key, http.StatusCreated, req)
+}
+
+// respond sends the response of a submission whose job has been created and, when the submission has an
+// idempotency key, stores it as sent, for the retries of the submission to get it back.
+func (h *handler) respond(c echo.Context, log *zap.Logger, subject, key string, status int, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		msg := "failed to encode response"
+		log.With(zap.Error(err)).Error(msg)
+		return servererr.SendFromStatusCode(c, http.StatusInternalServerError, msg)
+	}
+	if key != "" {
+		if err = h.database.SetIdempotencyKeyResponse(c.Request().Context(), subject, key, status, b); err != nil {
+			// The job has been created: only retries of this submission are affected.
+			log.With(zap.Error(err)).Error("failed to store idempotency key response")
+		}
+	}
+	return c.JSONBlob(status, b)
+}
+
+// validateRequest checks a report request beyond the OpenAPI validation and applies its defaults.
+// It returns the schedule of the runs of a periodic report, nil for a one-shot report.
+func (h *handler) validateRequest(log *zap.Logger, kind database.RequestKind, req *models.ReportCreationRequest) (*database.Schedule, *requestError) {
 	maxDuration := time.Duration(h.config.MaxTimePeriodDays) * 24 * time.Hour
 	now := time.Now()
 	oldestAllowedDate := now.Add(-maxDuration)
@@ -90,23 +277,35 @@ func (h *handler) handleReportCalculation(c echo.Context, kind database.RequestK
 	if len(req.Service) == 0 {
 		msg := "service array must contain at least one application instance"
 		log.Error(msg)
-		return servererr.SendFromStatusCode(c, http.StatusBadRequest, msg)
+		return nil, &requestError{status: http.StatusBadRequest, message: msg}
 	}
 
-	// Validate that timePeriod is provided; if not, set default
-	if req.TimePeriod == nil {
-		req.TimePeriod = &models.TimePeriod{
-			StartDate: oldestAllowedDate,
-			EndDate:   &now,
+	// A periodic report has no time period of its own: each run covers the period that elapsed before it.
+	var schedule *database.Schedule
+	if period := req.SubscriptionRequest.Config.SubscriptionDetail.ReportingPeriod; period != nil {
+		var err error
+		if schedule, err = newSchedule(*period, *req, now); err != nil {
+			log.With(zap.Error(err)).Error("invalid periodic report")
+			return nil, &requestError{status: http.StatusBadRequest, message: err.Error()}
 		}
 	}
 
-	// Validate that endDate is after startDate if endDate is present
-	if req.TimePeriod.EndDate != nil {
-		if !req.TimePeriod.EndDate.After(req.TimePeriod.StartDate) {
-			msg := "endDate must be after startDate in timePeriod"
-			log.Error(msg)
-			return servererr.SendFromStatusCode(c, http.StatusBadRequest, msg)
+	if schedule == nil {
+		// Validate that timePeriod is provided; if not, set default
+		if req.TimePeriod == nil {
+			req.TimePeriod = &models.TimePeriod{
+				StartDate: oldestAllowedDate,
+				EndDate:   &now,
+			}
+		}
+
+		// Validate that endDate is after startDate if endDate is present
+		if req.TimePeriod.EndDate != nil {
+			if !req.TimePeriod.EndDate.After(req.TimePeriod.StartDate) {
+				msg := "endDate must be after startDate in timePeriod"
+				log.Error(msg)
+				return nil, &requestError{status: http.StatusBadRequest, message: msg}
+			}
 		}
 	}
 
@@ -117,81 +316,479 @@ func (h *handler) handleReportCalculation(c echo.Context, kind database.RequestK
 		if actualType != expectedType {
 			msg := fmt.Sprintf("subscription event type '%s' does not match endpoint (expected '%s')", actualType, expectedType)
 			log.Error(msg)
-			return servererr.SendFromStatusCode(c, http.StatusBadRequest, msg)
+			return nil, &requestError{status: http.StatusBadRequest, message: msg}
 		}
 	}
 
-	// Validate that startDate and endDate are not in the future
-	if req.TimePeriod.StartDate.After(now) {
-		msg := "startDate cannot be in the future"
-		log.Error(msg)
-		return servererr.SendFromStatusCodeWithCode(c, http.StatusBadRequest, "OUT_OF_RANGE", msg)
-	}
-	if req.TimePeriod.EndDate != nil && req.TimePeriod.EndDate.After(now) {
-		msg := "endDate cannot be in the future"
-		log.Error(msg)
-		return servererr.SendFromStatusCodeWithCode(c, http.StatusBadRequest, "OUT_OF_RANGE", msg)
-	}
+	if schedule == nil {
+		// Validate that startDate and endDate are not in the future
+		if req.TimePeriod.StartDate.After(now) {
+			msg := "startDate cannot be in the future"
+			log.Error(msg)
+			return nil, &requestError{status: http.StatusBadRequest, code: "OUT_OF_RANGE", message: msg}
+		}
+		if req.TimePeriod.EndDate != nil && req.TimePeriod.EndDate.After(now) {
+			msg := "endDate cannot be in the future"
+			log.Error(msg)
+			return nil, &requestError{status: http.StatusBadRequest, code: "OUT_OF_RANGE", message: msg}
+		}
 
-	if req.TimePeriod.StartDate.Before(oldestAllowedDate) {
-		msg := fmt.Sprintf("startDate cannot be older than %d days", h.config.MaxTimePeriodDays)
-		log.Error(msg)
-		return servererr.SendFromStatusCodeWithCode(c, http.StatusBadRequest, "OUT_OF_RANGE", msg)
-	}
-	if req.TimePeriod.EndDate != nil && req.TimePeriod.EndDate.Before(oldestAllowedDate) {
-		msg := fmt.Sprintf("endDate cannot be older than %d days", h.config.MaxTimePeriodDays)
-		log.Error(msg)
-		return servererr.SendFromStatusCodeWithCode(c, http.StatusBadRequest, "OUT_OF_RANGE", msg)
+		if req.TimePeriod.StartDate.Before(oldestAllowedDate) {
+			msg := fmt.Sprintf("startDate cannot be older than %d days", h.config.MaxTimePeriodDays)
+			log.Error(msg)
+			return nil, &requestError{status: http.StatusBadRequest, code: "OUT_OF_RANGE", message: msg}
+		}
+		if req.TimePeriod.EndDate != nil && req.TimePeriod.EndDate.Before(oldestAllowedDate) {
+			msg := fmt.Sprintf("endDate cannot be older than %d days", h.config.MaxTimePeriodDays)
+			log.Error(msg)
+			return nil, &requestError{status: http.StatusBadRequest, code: "OUT_OF_RANGE", message: msg}
+		}
 	}
 
-	// Validate protocol limitation (only HTTP supported now)
-	if err = req.SubscriptionRequest.ValidateProtocol(); err != nil {
+	// Validate protocol limitation (every protocol of the spec, with the credential types each implements)
+	if err := req.SubscriptionRequest.ValidateProtocol(); err != nil {
 		log.With(zap.Error(err)).Warn("unsupported subscription protocol")
-		return servererr.SendFromStatusCode(c, http.StatusNotImplemented, err.Error())
+		return nil, &requestError{status: http.StatusNotImplemented, message: err.Error()}
+	}
+	if err := req.SubscriptionRequest.ValidateProtocolSettings(); err != nil {
+		log.With(zap.Error(err)).Warn("invalid subscription protocol settings")
+		return nil, &requestError{status: http.StatusBadRequest, code: "INVALID_ARGUMENT", message: err.Error()}
 	}
 
-	// Validate sink credential limitation (only ACCESSTOKEN supported now)
+	// Validate sink credential limitation (only ACCESSTOKEN and PLAIN supported now)
 	if cred := req.SubscriptionRequest.SinkCredential; cred != nil {
-		if err = cred.Validate(); err != nil {
+		if err := cred.Validate(); err != nil {
 			log.With(zap.Error(err)).Warn("sink credential validation failed")
-			return servererr.SendFromStatusCode(c, http.StatusNotImplemented, err.Error())
+			return nil, &requestError{status: http.StatusNotImplemented, message: err.Error()}
 		}
 	}
 
-	// Warn if initialEvent is set to true (has no effect for this API)
-	if req.SubscriptionRequest.Config.InitialEvent != nil && *req.SubscriptionRequest.Config.InitialEvent {
+	// Warn if initialEvent is set to true (only meaningful for periodic reports)
+	if schedule == nil && req.SubscriptionRequest.Config.InitialEvent != nil && *req.SubscriptionRequest.Config.InitialEvent {
 		log.Warn("initialEvent is set to true but has no effect for this API")
 	}
+	return schedule, nil
+}
+
+// ValidateReport runs the checks of the calculate endpoint of the given kind on a report request and
+// resolves the topology of its application instances, without creating a job nor sending any event.
+func (h *handler) ValidateReport(c echo.Context, params models.ValidateReportParams) error {
+	ctx := c.Request().Context()
+	log := logger.FromContext(ctx)
+	kind := requestKind(params.Kind)
 
-	appIds := make([]string, len(req.Service))
-	for i, id := range req.Service {
-		appIds[i] = id.String()
+	req, err := request.Bind[models.ReportCreationRequest](c)
+	if err != nil {
+		msg := "failed to validate request body"
+		log.With(zap.Error(err)).Error(msg)
+		return servererr.SendFromStatusCode(c, http.StatusBadRequest, msg)
+	}
+	schedule, reqErr := h.validateRequest(log, kind, req)
+	if reqErr != nil {
+		return reqErr.send(c)
 	}
 
-	if err = h.pdp.HasAccessToApplicationIDs(ctx, middleware.CtxSub(ctx), appIds); err != nil {
+	if err = h.pdp.HasAccessToApplicationIDs(ctx, middleware.CtxSub(ctx), serviceIDs(req.Service)); err != nil {
 		msg := "failed to authorize application IDs"
 		log.With(zap.Error(err)).Error(msg)
 		return servererr.SendFromStatusCode(c, http.StatusUnauthorized, err.Error())
 	}
 
-	req.RequestId = &requestID
-	if err = h.database.CreateJob(ctx, newJob(*req, kind)); err != nil {
-		log.With(zap.Error(err)).Error("failed to create job")
+	// Each run of a periodic report covers one reporting period.
+	now := time.Now().UTC()
+	job := database.Job{JobSpec: database.JobSpec{TimePeriod: req.TimePeriod}, CreatedAt: now}
+	if schedule != nil {
+		job.TimePeriod = &models.TimePeriod{StartDate: now.Add(-schedule.Period), EndDate: &now}
+	}
+
+	validation := models.ReportValidation{
+		Valid:        true,
+		TimePeriod:   req.TimePeriod,
+		Applications: make([]models.ApplicationTopology, 0, len(req.Service)),
+	}
+	resolved := make([]orchestrator.Information, 0, len(req.Service))
+	for _, appInstanceID := range req.Service {
+		info, err := h.orchestrator.GatherInformation(ctx, appInstanceID.String())
+		if err != nil {
+			log.With(zap.Error(err), zap.String("appInstanceID", appInstanceID.String())).Warn("failed to gather application instance information")
+			validation.Valid = false
+			errorInfo := &models.ErrorInfo{
+				Status:  http.StatusUnprocessableEntity,
+				Code:    "UNRESOLVED_TOPOLOGY",
+				Message: err.Error(),
+			}
+			// The topology may resolve later on, once the orchestrator is available again.
+			if datasource.IsRetryable(err) {
+				errorInfo.Status = http.StatusServiceUnavailable
+				errorInfo.Code = "UNAVAILABLE"
+			}
+			validation.Applications = append(validation.Applications, models.ApplicationTopology{
+				AppInstanceId: appInstanceID,
+				Error:         errorInfo,
+			})
+			continue
+		}
+		resolved = append(resolved, info)
+		validation.Applications = append(validation.Applications, newApplicationTopology(appInstanceID, info))
+	}
+	validation.EstimatedFanOut = estimateFanOut(resolved, len(job.TimeWindows(h.windowSize)))
+	return c.JSON(http.StatusOK, validation)
+}
+
+func newApplicationTopology(appInstanceID models.AppInstanceId, info orchestrator.Information) models.ApplicationTopology {
+	nes := make([]models.NetworkElementTopology, 0, len(info.NE))
+	for _, ne := range info.NE {
+		nes = append(nes, models.NetworkElementTopology{
+			InstanceId: ne.InstanceID,
+			NetworkId:  &ne.NetworkID,
+			VendorId:   &ne.VendorID,
+			InfraType:  &ne.InfraType,
+		})
+	}
+	return models.ApplicationTopology{
+		AppInstanceId:   appInstanceID,
+		IpList:          &info.App.IPList,
+		InfraType:       &info.App.InfraType,
+		NetworkElements: &nes,
+	}
+}
+
+// estimateFanOut counts the backend calls and the events the worker makes for a report over the
+// given application instances: for each of them, an orchestrator call and, in every one of the windows
+// of the time period, a Cloud Observability call for the application and one per network element,
+// and a single Traffic Volume call, each behind its own event; the report then adds a calculation and
+// a notification event.
+func estimateFanOut(resolved []orchestrator.Information, windows int) models.EstimatedFanOut {
+	var f models.EstimatedFanOut
+	for _, info := range resolved {
+		f.OrchestratorCalls++
+		f.CloudObservabilityCalls += windows * (1 + len(info.NE))
+		f.TrafficVolumeCalls += windows
+		// gatherinfo, then per window app consumption, one energy event per network element and the traffic event.
+		f.Events += 1 + windows*(2+len(info.NE))
+	}
+	if len(resolved) > 0 {
+		f.Events += 2
+	}
+	f.BackendCalls = f.OrchestratorCalls + f.CloudObservabilityCalls + f.TrafficVolumeCalls
+	return f
+}
+
+// requestError is a request validation failure, sent back to the API consumer as an ErrorInfo.
+type requestError struct {
+	status  int
+	code    string
+	message string
+	// retryAfter is sent in the Retry-After header when set.
+	retryAfter time.Duration
+}
+
+func (e *requestError) send(c echo.Context) error {
+	if e.retryAfter > 0 {
+		c.Response().Header().Set("Retry-After", retryAfterSeconds(e.retryAfter))
+	}
+	if e.code != "" {
+		return servererr.SendFromStatusCodeWithCode(c, e.status, e.code, e.message)
+	}
+	return servererr.SendFromStatusCode(c, e.status, e.message)
+}
+
+// syncWait returns how long to wait for the report to be calculated inline, or zero when the report
+// is to be processed asynchronously: the Prefer header does not ask to wait, the report is periodic or
+// it covers too many application instances. The wait is capped by the configuration.
+func (h *handler) syncWait(prefer *string, schedule *database.Schedule, applications int) time.Duration {
+	if prefer == nil || schedule != nil || applications > h.config.SyncMaxApplications || h.inline == nil {
+		return 0
+	}
+	wait := preferredWait(*prefer)
+	if wait > h.config.SyncMaxWait {
+		wait = h.config.SyncMaxWait
+	}
+	return wait
+}
+
+// preferredWait extracts the wait preference, in seconds, of a Prefer header value (RFC 7240).
+// It returns zero if the preference is absent or invalid.
+func preferredWait(prefer string) time.Duration {
+	for _, preference := range strings.Split(prefer, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(preference), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "wait") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(value), `"`))
+		if err != nil || seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
+}
+
+// calculateInline calculates the report of job within wait and stores its result. It returns nil when
+// the report could not be calculated in time or failed, in which case the job is left created for the
+// asynchronous flow, which also retries the backend errors.
+func (h *handler) calculateInline(ctx context.Context, job *database.Job, wait time.Duration) *models.Report {
+	log := logger.FromContext(ctx).With(zap.String("requestID", *job.RequestId), zap.Duration("wait", wait))
+
+	runCtx, cancel := context.WithTimeout(ctx, wait)
+	result, err := h.inline.Run(runCtx, job)
+	// The inline work still running past the wait is stopped before the asynchronous flow takes the job over.
+	cancel()
+	if err != nil {
+		log.With(zap.Error(err)).Warn("inline calculation did not complete, falling back to asynchronous processing")
+		return nil
+	}
+
+	// The job stays created until its result is ready, then goes through its whole lifecycle at once, so
+	// that the asynchronous flow taking over a job left created finds it in the state it starts from. The
+	// response is the notification of a report calculated inline, so the job is completed with its result.
+	now := time.Now().UTC()
+	completed, err := h.database.CompleteInlineJob(ctx, *job.RequestId, result.Value, result.Breakdown, now)
+	if err != nil || !completed {
+		log.With(zap.Error(err), zap.Bool("completed", completed)).Error("failed to store inline calculation result")
+		return nil
+	}
+	log.Info("report calculated inline")
+
+	job.Status = database.StatusCompleted
+	for _, status := range []database.Status{database.StatusGathering, database.StatusCalculating, database.StatusNotifying, database.StatusCompleted} {
+		job.Transitions = append(job.Transitions, database.Transition{Status: status, At: now})
+	}
+	job.Result = &result.Value
+	job.Breakdown = result.Breakdown
+	job.UpdatedAt = &now
+	job.CompletedAt = &now
+	report := newReport(job, nil)
+	// Every application instance has been gathered, although nothing is stored per application.
+	report.GatheredApplications = report.TotalApplications
+	return &report
+}
+
+// newSchedule validates the subscription of a periodic report and returns the schedule of its runs.
+// The first run is due immediately when initialEvent is set, one period later otherwise.
+func newSchedule(period models.ReportingPeriod, req models.ReportCreationRequest, now time.Time) (*database.Schedule, error) {
+	d, ok := scheduler.Period(period)
+	if !ok {
+		return nil, fmt.Errorf("unsupported reportingPeriod '%s'", period)
+	}
+	cfg := req.SubscriptionRequest.Config
+	if req.TimePeriod != nil {
+		return nil, fmt.Errorf("timePeriod cannot be set for a periodic report")
+	}
+	if cfg.SubscriptionExpireTime == nil && cfg.SubscriptionMaxEvents == nil {
+		return nil, fmt.Errorf("a periodic report requires subscriptionExpireTime or subscriptionMaxEvents")
+	}
+	if cfg.SubscriptionExpireTime != nil && !cfg.SubscriptionExpireTime.After(now) {
+		return nil, fmt.Errorf("subscriptionExpireTime must be in the future")
+	}
+	next := now.UTC()
+	if cfg.InitialEvent == nil || !*cfg.InitialEvent {
+		next = next.Add(d)
+	}
+	return &database.Schedule{Period: d, NextRunAt: next}, nil
+}
+
+// replaySubmission answers a submission whose idempotency key is already known. The original response is
+// returned for a retry of the same request, while a different request reusing the key is rejected.
+func replaySubmission(c echo.Context, stored *database.IdempotencyKey, fingerprint string) error {
+	log := logger.FromContext(c.Request().Context()).With(zap.String("requestID", stored.RequestID))
+	switch {
+	case stored.Fingerprint != fingerprint:
+		msg := "idempotency key already used for a different request"
+		log.Warn(msg)
+		return servererr.SendFromStatusCodeWithCode(c, http.StatusConflict, "CONFLICT", msg)
+	case stored.ResponseStatus == 0:
+		msg := "a request with the same idempotency key is still being processed"
+		log.Warn(msg)
+		return servererr.SendFromStatusCodeWithCode(c, http.StatusConflict, "ABORTED", msg)
+	default:
+		log.Info("replaying response of idempotent submission")
+		return c.JSONBlob(stored.ResponseStatus, stored.ResponseBody)
+	}
+}
+
+// requestIDNamespace is the UUID namespace of the identifiers derived from the requestId supplied by a subject.
+var requestIDNamespace = uuid.MustParse("6f1c5e0a-3d2b-5c8e-9a47-0e1b2c3d4f5a")
+
+// scopedRequestID derives the identifier of the job of a subject from the requestId it supplied, so that
+// the same value supplied by different subjects identifies different jobs.
+func scopedRequestID(subject, requestID string) string {
+	return uuid.NewSHA1(requestIDNamespace, []byte(subject+"\x00"+requestID)).String()
+}
+
+// requestFingerprint identifies a submission by its endpoint and body.
+func requestFingerprint(kind database.RequestKind, req models.ReportCreationRequest) (string, error) {
+	b, err := json.Marshal(struct {
+		Kind    database.RequestKind         `json:"kind"`
+		Request models.ReportCreationRequest `json:"request"`
+	}{kind, req})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// getOwnedJob reads a job created by the caller. The job of another subject is not found, as it is not
+// listed by ListReports either. A job created before its subject was recorded is checked on its
+// applications only.
+func (h *handler) getOwnedJob(ctx context.Context, requestID string) (*database.Job, error) {
+	job, err := h.database.GetJob(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Subject != "" && job.Subject != middleware.CtxSub(ctx) {
+		return nil, servererr.NewNotFound("job with id '" + requestID + "'")
+	}
+	return job, nil
+}
+
+// GetReport returns the current status of a job and, once available, its result or failure reason.
+// Access is granted only to the subject that created the job, if it is authorized on all the application
+// IDs of the job.
+func (h *handler) GetReport(c echo.Context, requestId models.RequestId, params models.GetReportParams) error {
+	ctx := c.Request().Context()
+	log := logger.FromContext(ctx).With(zap.String("requestID", requestId))
+
+	job, err := h.getOwnedJob(ctx, requestId)
+	if err != nil {
+		log.With(zap.Error(err)).Error("failed to read job")
 		return servererr.Send(c, err)
 	}
 
-	for _, appInstanceID := range appIds {
-		eventId := event.EventIDForApp(requestID, appInstanceID)
-		if err = h.events.Send(ctx, eventId, event.EventTypeGatherInfoRequested, event.SourceEFNAPI, event.NewGatherInfoData(requestID, appInstanceID)); err != nil {
+	if err = h.pdp.HasAccessToApplicationIDs(ctx, middleware.CtxSub(ctx), serviceIDs(job.Service)); err != nil {
+		msg := "failed to authorize application IDs"
+		log.With(zap.Error(err)).Error(msg)
+		return servererr.SendFromStatusCode(c, http.StatusUnauthorized, err.Error())
+	}
+
+	results, err := h.database.GetAllJobAppResults(ctx, requestId)
+	if err != nil {
+		log.With(zap.Error(err)).Error("failed to read job app results")
+		return servererr.Send(c, err)
+	}
+	return c.JSON(http.StatusOK, newReport(job, results))
+}
+
+// CancelReport cancels a job whose result has not been calculated yet and requests the final
+// cancellation callback. Cancelling an already cancelled job returns it unchanged. Like GetReport, it
+// is restricted to the subject that created the job.
+func (h *handler) CancelReport(c echo.Context, requestId models.RequestId, params models.CancelReportParams) error {
+	ctx := c.Request().Context()
+	log := logger.FromContext(ctx).With(zap.String("requestID", requestId))
+
+	job, err := h.getOwnedJob(ctx, requestId)
+	if err != nil {
+		log.With(zap.Error(err)).Error("failed to read job")
+		return servererr.Send(c, err)
+	}
+
+	if err = h.pdp.HasAccessToApplicationIDs(ctx, middleware.CtxSub(ctx), serviceIDs(job.Service)); err != nil {
+		msg := "failed to authorize application IDs"
+		log.With(zap.Error(err)).Error(msg)
+		return servererr.SendFromStatusCode(c, http.StatusUnauthorized, err.Error())
+	}
+
+	cancelled, err := h.database.CancelJob(ctx, requestId)
+	if err != nil {
+		log.With(zap.Error(err)).Error("failed to cancel job")
+		return servererr.Send(c, err)
+	}
+
+	// Re-read the job to return the status it actually has after the cancellation attempt.
+	job, err = h.database.GetJob(ctx, requestId)
+	if err != nil {
+		log.With(zap.Error(err)).Error("failed to read job")
+		return servererr.Send(c, err)
+	}
+	if !cancelled && job.Status != database.StatusCancelled {
+		msg := fmt.Sprintf("report is %s and can no longer be cancelled", job.Status)
+		log.Warn(msg)
+		return servererr.SendFromStatusCodeWithCode(c, http.StatusConflict, "CONFLICT", msg)
+	}
+
+	// The callback is requested again while it has not been delivered, so that retrying the
+	// cancellation recovers from a failure to send the event; duplicates are discarded downstream.
+	if cancelled || !job.NotificationSent {
+		data := event.NewNotificationCancelledRequestedData(requestId)
+		if err = h.events.Send(ctx, requestId, event.EventTypeNotificationCancelledRequested, event.SourceEFNAPI, data); err != nil {
 			msg := "failed to send cloud event"
-			log.With(zap.Error(err), zap.String("Event ID", eventId)).Error(msg)
+			log.With(zap.Error(err)).Error(msg)
 			return servererr.SendFromStatusCode(c, http.StatusInternalServerError, "failed to send event")
 		}
+		log.With(zap.Bool("transitioned", cancelled)).Info("report cancelled")
 	}
-	return c.JSON(http.StatusCreated, req)
+
+	results, err := h.database.GetAllJobAppResults(ctx, requestId)
+	if err != nil {
+		log.With(zap.Error(err)).Error("failed to read job app results")
+		return servererr.Send(c, err)
+	}
+	return c.JSON(http.StatusAccepted, newReport(job, results))
+}
+
+// ListReports returns a page of the jobs created by the caller, most recent first.
+// One job more than requested is read to know whether a next page exists.
+func (h *handler) ListReports(c echo.Context, params models.ListReportsParams) error {
+	ctx := c.Request().Context()
+	log := logger.FromContext(ctx)
+
+	limit := defaultListLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	filter := database.JobFilter{
+		Subject:       middleware.CtxSub(ctx),
+		CreatedFrom:   params.CreatedFrom,
+		CreatedTo:     params.CreatedTo,
+		AppInstanceID: params.AppInstanceId,
+		Limit:         limit + 1,
+	}
+	if params.ParentRequestId != nil {
+		filter.ParentID = *params.ParentRequestId
+	}
+	if params.Status != nil {
+		status := database.Status(*params.Status)
+		filter.Status = &status
+	}
+	if params.Kind != nil {
+		kind := requestKind(*params.Kind)
+		filter.RequestKind = &kind
+	}
+	if params.Cursor != nil {
+		after, err := decodeCursor(*params.Cursor)
+		if err != nil {
+			msg := "invalid cursor"
+			log.With(zap.Error(err)).Error(msg)
+			return servererr.SendFromStatusCode(c, http.StatusBadRequest, msg)
+		}
+		filter.After = after
+	}
+
+	jobs, err := h.database.ListJobs(ctx, filter)
+	if err != nil {
+		log.With(zap.Error(err)).Error("failed to list jobs")
+		return servererr.Send(c, err)
+	}
+
+	list := models.ReportList{Reports: []models.Report{}}
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+		last := jobs[limit-1]
+		next := encodeCursor(database.JobCursor{CreatedAt: last.CreatedAt, ID: *last.RequestId})
+		list.NextCursor = &next
+	}
+	for i := range jobs {
+		// Application results are not read for listings, so the gathering progress is left out.
+		report := newReport(&jobs[i], nil)
+		report.GatheredApplications = nil
+		list.Reports = append(list.Reports, report)
+	}
+	return c.JSON(http.StatusOK, list)
 }
 
-func newJob(req models.ReportCreationRequest, kind database.RequestKind) *database.Job {
+func newJob(req models.ReportCreationRequest, kind database.RequestKind, subject, xCorrelator string) *database.Job {
+	now := time.Now().UTC()
 	return &database.Job{
 		JobSpec: database.JobSpec{
 			RequestId:           req.RequestId,
@@ -200,7 +797,128 @@ func newJob(req models.ReportCreationRequest, kind database.RequestKind) *databa
 			SubscriptionRequest: req.SubscriptionRequest,
 			TimePeriod:          req.TimePeriod,
 		},
+		Subject:     subject,
+		XCorrelator: xCorrelator,
+		Status:      database.StatusCreated,
+		Transitions: []database.Transition{{Status: database.StatusCreated, At: now}},
+		CreatedAt:   now,
+	}
+}
+
+// newReport builds the API representation of a job.
+func newReport(job *database.Job, results []database.JobAppResult) models.Report {
+	// An application instance is gathered once its results over every window of the time period are complete.
+	completeWindows := make(map[string]int)
+	gathered := 0
+	for _, r := range results {
+		if !r.IsComplete() {
+			continue
+		}
+		completeWindows[r.AppID]++
+		if completeWindows[r.AppID] == r.Windows() {
+			gathered++
+		}
+	}
+	total := len(job.Service)
+
+	status := job.Status
+	if status == "" {
+		status = database.StatusCreated
+	}
+
+	kind := reportKind(job.RequestKind)
+	report := models.Report{
+		RequestId:            *job.RequestId,
+		Kind:                 &kind,
+		Service:              &job.Service,
+		Status:               models.ReportStatus(status),
+		CreatedAt:            job.CreatedAt,
+		UpdatedAt:            job.UpdatedAt,
+		CompletedAt:          job.CompletedAt,
+		TotalApplications:    &total,
+		GatheredApplications: &gathered,
+		Error:                job.Error,
+		TimePeriod:           job.TimePeriod,
+	}
+	if len(job.Transitions) > 0 {
+		history := make([]models.StatusChange, 0, len(job.Transitions))
+		for _, t := range job.Transitions {
+			history = append(history, models.StatusChange{Status: models.ReportStatus(t.Status), At: t.At})
+		}
+		report.StatusHistory = &history
+	}
+	if job.Failure != nil {
+		report.Failure = &models.FailureReason{
+			Stage: models.ReportStatus(job.Failure.Stage),
+			Cause: models.FailureReasonCause(job.Failure.Cause),
+		}
+		if job.Failure.EventType != "" {
+			report.Failure.EventType = &job.Failure.EventType
+		}
+	}
+	if job.ParentID != "" {
+		report.ParentRequestId = &job.ParentID
+	}
+	if job.Schedule != nil {
+		report.Runs = &job.Schedule.Runs
+		if !status.IsFinal() {
+			report.NextRunAt = &job.Schedule.NextRunAt
+		}
+	}
+	if job.Result != nil {
+		switch job.RequestKind {
+		case database.RequestKindCarbonFootprint:
+			report.CarbonFootprint = job.Result
+		default:
+			report.EnergyConsumption = job.Result
+		}
+		report.Breakdown = job.Breakdown
+		report.Coverage = job.Coverage
+	}
+	return report
+}
+
+func reportKind(kind database.RequestKind) models.ReportKind {
+	if kind == database.RequestKindCarbonFootprint {
+		return models.CarbonFootprint
+	}
+	return models.EnergyConsumption
+}
+
+func requestKind(kind models.ReportKind) database.RequestKind {
+	if kind == models.CarbonFootprint {
+		return database.RequestKindCarbonFootprint
+	}
+	return database.RequestKindEnergyConsumption
+}
+
+// encodeCursor turns the position of a job into the opaque cursor exposed by ListReports.
+func encodeCursor(c database.JobCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*database.JobCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c database.JobCursor
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == "" {
+		return nil, fmt.Errorf("cursor without job id")
+	}
+	return &c, nil
+}
+
+func serviceIDs(service []models.AppInstanceId) []string {
+	ids := make([]string, len(service))
+	for i, id := range service {
+		ids[i] = id.String()
 	}
+	return ids
 }
 
 func getExpectedEventType(kind database.RequestKind) string {
@@ -0,0 +1,544 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/backend"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/inline"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/calculator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/cloudobservability"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/middleware"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/policy"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
+)
+
+func TestNewReport(t *testing.T) {
+	requestID := "req1"
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	completeResult := database.JobAppResult{
+		JobAppResultMetadata: database.JobAppResultMetadata{NumberOfTotalNEs: 1},
+		Result: &database.TaskResult{
+			AppInstanceEnergyConsumption: floatPtr(1.0),
+			NetworkElements: map[string]database.NetworkElementResult{
+				"ne1": {
+					EnergyConsumption:  floatPtr(1.0),
+					AppInstanceTraffic: floatPtr(1.0),
+					TotalTraffic:       floatPtr(1.0),
+				},
+			},
+		},
+	}
+	partialResult := database.JobAppResult{
+		JobAppResultMetadata: database.JobAppResultMetadata{NumberOfTotalNEs: 2},
+		Result: &database.TaskResult{
+			AppInstanceEnergyConsumption: floatPtr(1.0),
+		},
+	}
+
+	tests := []struct {
+		name           string
+		job            database.Job
+		results        []database.JobAppResult
+		expectStatus   models.ReportStatus
+		expectGathered int
+		expectEnergy   *float64
+		expectCarbon   *float64
+	}{
+		{
+			name: "job without status is created",
+			job: database.Job{
+				JobSpec: database.JobSpec{RequestKind: database.RequestKindEnergyConsumption},
+			},
+			expectStatus: models.Created,
+		},
+		{
+			name: "gathering job counts gathered applications",
+			job: database.Job{
+				JobSpec: database.JobSpec{RequestKind: database.RequestKindEnergyConsumption},
+				Status:  database.StatusGathering,
+			},
+			results:        []database.JobAppResult{completeResult, partialResult},
+			expectStatus:   models.Gathering,
+			expectGathered: 1,
+		},
+		{
+			name: "notifying job exposes its result",
+			job: database.Job{
+				JobSpec: database.JobSpec{RequestKind: database.RequestKindEnergyConsumption},
+				Status:  database.StatusNotifying,
+				Result:  floatPtr(0.0044),
+			},
+			results:        []database.JobAppResult{completeResult},
+			expectStatus:   models.Notifying,
+			expectGathered: 1,
+			expectEnergy:   floatPtr(0.0044),
+		},
+		{
+			name: "completed energy job exposes energy consumption",
+			job: database.Job{
+				JobSpec: database.JobSpec{RequestKind: database.RequestKindEnergyConsumption},
+				Status:  database.StatusCompleted,
+				Result:  floatPtr(0.0044),
+			},
+			results:        []database.JobAppResult{completeResult},
+			expectStatus:   models.Completed,
+			expectGathered: 1,
+			expectEnergy:   floatPtr(0.0044),
+		},
+		{
+			name: "completed carbon job exposes carbon footprint",
+			job: database.Job{
+				JobSpec: database.JobSpec{RequestKind: database.RequestKindCarbonFootprint},
+				Status:  database.StatusCompleted,
+				Result:  floatPtr(0.5),
+			},
+			results:        []database.JobAppResult{completeResult},
+			expectStatus:   models.Completed,
+			expectGathered: 1,
+			expectCarbon:   floatPtr(0.5),
+		},
+		{
+			name: "failed job exposes error info",
+			job: database.Job{
+				JobSpec: database.JobSpec{RequestKind: database.RequestKindEnergyConsumption},
+				Status:  database.StatusFailed,
+				Error:   &models.ErrorInfo{Status: 500, Code: "Internal Server Error", Message: "boom"},
+				Failure: &database.Failure{Stage: database.StatusGathering, Cause: database.FailureCauseBackendError},
+			},
+			results:      []database.JobAppResult{partialResult},
+			expectStatus: models.Failed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.job.RequestId = &requestID
+			tt.job.Service = []models.AppInstanceId{uuid.New(), uuid.New()}
+			tt.job.CreatedAt = createdAt
+
+			report := newReport(&tt.job, tt.results)
+			assert.Equal(t, requestID, report.RequestId)
+			assert.Equal(t, tt.expectStatus, report.Status)
+			assert.Equal(t, createdAt, report.CreatedAt)
+			assert.Equal(t, 2, *report.TotalApplications)
+			assert.Equal(t, tt.expectGathered, *report.GatheredApplications)
+			assert.Equal(t, tt.expectEnergy, report.EnergyConsumption)
+			assert.Equal(t, tt.expectCarbon, report.CarbonFootprint)
+			assert.Equal(t, tt.job.Error, report.Error)
+			if tt.job.Failure != nil {
+				assert.Equal(t, models.Gathering, report.Failure.Stage)
+				assert.Equal(t, models.BackendError, report.Failure.Cause)
+			} else {
+				assert.Nil(t, report.Failure)
+			}
+		})
+	}
+
+	t.Run("status history", func(t *testing.T) {
+		job := database.Job{
+			JobSpec:   database.JobSpec{RequestId: &requestID},
+			Status:    database.StatusGathering,
+			CreatedAt: createdAt,
+			Transitions: []database.Transition{
+				{Status: database.StatusCreated, At: createdAt},
+				{Status: database.StatusGathering, At: createdAt.Add(time.Second)},
+			},
+		}
+		report := newReport(&job, nil)
+		assert.Equal(t, []models.StatusChange{
+			{Status: models.Created, At: createdAt},
+			{Status: models.Gathering, At: createdAt.Add(time.Second)},
+		}, *report.StatusHistory)
+	})
+}
+
+func TestCursor(t *testing.T) {
+	cursor := database.JobCursor{CreatedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), ID: "req1"}
+
+	decoded, err := decodeCursor(encodeCursor(cursor))
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+
+	for _, invalid := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+		_, err = decodeCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestScopedRequestID(t *testing.T) {
+	id := scopedRequestID("alice", "req1")
+	_, err := uuid.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, scopedRequestID("alice", "req1"))
+	assert.NotEqual(t, id, scopedRequestID("bob", "req1"))
+	assert.NotEqual(t, id, scopedRequestID("alice", "req2"))
+	// The subject and the value cannot be shifted into one another.
+	assert.NotEqual(t, scopedRequestID("ab", "c"), scopedRequestID("a", "bc"))
+}
+
+func TestReplaySubmission(t *testing.T) {
+	requestID := "req1"
+	req := models.ReportCreationRequest{
+		RequestId: &requestID,
+		Service:   []models.AppInstanceId{uuid.New()},
+	}
+	fingerprint, err := requestFingerprint(database.RequestKindEnergyConsumption, req)
+	assert.NoError(t, err)
+	otherKind, err := requestFingerprint(database.RequestKindCarbonFootprint, req)
+	assert.NoError(t, err)
+	assert.NotEqual(t, fingerprint, otherKind)
+	created, err := json.Marshal(req)
+	assert.NoError(t, err)
+	report, err := json.Marshal(models.Report{RequestId: requestID, Status: models.Completed})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		stored       database.IdempotencyKey
+		expectStatus int
+		expectCode   string
+	}{
+		{
+			name:         "same request returns the original response",
+			stored:       database.IdempotencyKey{Fingerprint: fingerprint, RequestID: requestID, ResponseStatus: http.StatusCreated, ResponseBody: created},
+			expectStatus: http.StatusCreated,
+		},
+		{
+			name:         "same request calculated inline returns the original report",
+			stored:       database.IdempotencyKey{Fingerprint: fingerprint, RequestID: requestID, ResponseStatus: http.StatusOK, ResponseBody: report},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "different request is a conflict",
+			stored:       database.IdempotencyKey{Fingerprint: otherKind, RequestID: requestID, ResponseStatus: http.StatusCreated, ResponseBody: created},
+			expectStatus: http.StatusConflict,
+			expectCode:   "CONFLICT",
+		},
+		{
+			name:         "same request still in progress is aborted",
+			stored:       database.IdempotencyKey{Fingerprint: fingerprint, RequestID: requestID},
+			expectStatus: http.StatusConflict,
+			expectCode:   "ABORTED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+
+			assert.NoError(t, replaySubmission(c, &tt.stored, fingerprint))
+			assert.Equal(t, tt.expectStatus, rec.Code)
+			if tt.expectCode != "" {
+				var errorInfo models.ErrorInfo
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &errorInfo))
+				assert.Equal(t, tt.expectCode, errorInfo.Code)
+			} else {
+				assert.Equal(t, tt.stored.ResponseBody, rec.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestNewSchedule(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(48 * time.Hour)
+	past := now.Add(-time.Hour)
+	maxEvents := 3
+	initialEvent := true
+
+	tests := []struct {
+		name          string
+		config        models.Config
+		timePeriod    *models.TimePeriod
+		expectErr     bool
+		expectNextRun time.Time
+	}{
+		{
+			name:          "first run one period later by default",
+			config:        models.Config{SubscriptionMaxEvents: &maxEvents},
+			expectNextRun: now.Add(24 * time.Hour),
+		},
+		{
+			name:          "first run immediately with initialEvent",
+			config:        models.Config{SubscriptionExpireTime: &future, InitialEvent: &initialEvent},
+			expectNextRun: now,
+		},
+		{
+			name:       "time period is rejected",
+			config:     models.Config{SubscriptionMaxEvents: &maxEvents},
+			timePeriod: &models.TimePeriod{StartDate: past},
+			expectErr:  true,
+		},
+		{
+			name:      "unbounded subscription is rejected",
+			config:    models.Config{},
+			expectErr: true,
+		},
+		{
+			name:      "expired subscription is rejected",
+			config:    models.Config{SubscriptionExpireTime: &past},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := models.ReportCreationRequest{TimePeriod: tt.timePeriod}
+			req.SubscriptionRequest.Config = tt.config
+
+			schedule, err := newSchedule(models.Daily, req, now)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 24*time.Hour, schedule.Period)
+			assert.Equal(t, tt.expectNextRun, schedule.NextRunAt)
+			assert.Zero(t, schedule.Runs)
+		})
+	}
+}
+
+func TestSyncWait(t *testing.T) {
+	h := &handler{
+		inline: &inline.Runner{},
+		config: config.API{SyncMaxApplications: 2, SyncMaxWait: 30 * time.Second},
+	}
+	prefer := func(s string) *string { return &s }
+
+	tests := []struct {
+		name         string
+		prefer       *string
+		schedule     *database.Schedule
+		applications int
+		expect       time.Duration
+	}{
+		{name: "no preference", applications: 1},
+		{name: "wait preference", prefer: prefer("wait=10"), applications: 1, expect: 10 * time.Second},
+		{name: "wait among other preferences", prefer: prefer("respond-async, Wait=\"5\""), applications: 1, expect: 5 * time.Second},
+		{name: "wait capped by configuration", prefer: prefer("wait=120"), applications: 1, expect: 30 * time.Second},
+		{name: "invalid wait", prefer: prefer("wait=soon"), applications: 1},
+		{name: "too many applications", prefer: prefer("wait=10"), applications: 3},
+		{name: "periodic report", prefer: prefer("wait=10"), schedule: &database.Schedule{}, applications: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, h.syncWait(tt.prefer, tt.schedule, tt.applications))
+		})
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestEstimateFanOut(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolved []orchestrator.Information
+		windows  int
+		expect   models.EstimatedFanOut
+	}{
+		{name: "no application"},
+		{
+			name:     "single application without network elements",
+			resolved: []orchestrator.Information{{}},
+			expect: models.EstimatedFanOut{
+				OrchestratorCalls:       1,
+				CloudObservabilityCalls: 1,
+				TrafficVolumeCalls:      1,
+				BackendCalls:            3,
+				Events:                  5,
+			},
+		},
+		{
+			name: "applications with network elements",
+			resolved: []orchestrator.Information{
+				{NE: []orchestrator.NEInfo{{InstanceID: "ne-1"}, {InstanceID: "ne-2"}}},
+				{NE: []orchestrator.NEInfo{{InstanceID: "ne-3"}}},
+			},
+			expect: models.EstimatedFanOut{
+				OrchestratorCalls:       2,
+				CloudObservabilityCalls: 5,
+				TrafficVolumeCalls:      2,
+				BackendCalls:            9,
+				Events:                  11,
+			},
+		},
+		{
+			name:     "time period split into windows",
+			resolved: []orchestrator.Information{{NE: []orchestrator.NEInfo{{InstanceID: "ne-1"}}}},
+			windows:  3,
+			expect: models.EstimatedFanOut{
+				OrchestratorCalls:       1,
+				CloudObservabilityCalls: 6,
+				TrafficVolumeCalls:      3,
+				BackendCalls:            10,
+				Events:                  12,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, estimateFanOut(tt.resolved, max(tt.windows, 1)))
+		})
+	}
+}
+
+type mockReportDatabase struct {
+	database.Interface
+	job       *database.Job
+	cancelled bool
+}
+
+func (m *mockReportDatabase) GetJob(context.Context, string) (*database.Job, error) {
+	return m.job, nil
+}
+
+func (m *mockReportDatabase) GetAllJobAppResults(context.Context, string) ([]database.JobAppResult, error) {
+	return nil, nil
+}
+
+func (m *mockReportDatabase) CancelJob(context.Context, string) (bool, error) {
+	m.cancelled = true
+	return false, nil
+}
+
+func TestReportOwnership(t *testing.T) {
+	// Both subjects are authorized on the application of the job.
+	appID := uuid.New()
+	requestID := "req1"
+
+	tests := []struct {
+		name         string
+		jobSubject   string
+		subject      string
+		expectStatus int
+	}{
+		{name: "creator reads its report", jobSubject: "alice", subject: "alice", expectStatus: http.StatusOK},
+		{name: "another subject does not find it", jobSubject: "alice", subject: "bob", expectStatus: http.StatusNotFound},
+		{name: "report created before subjects were recorded", subject: "bob", expectStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mockReportDatabase{job: &database.Job{
+				JobSpec: database.JobSpec{RequestId: &requestID, Service: []models.AppInstanceId{appID}},
+				Subject: tt.jobSubject,
+				Status:  database.StatusGathering,
+			}}
+			h := &handler{database: db, pdp: policy.NewAllowAll()}
+			request := func() echo.Context {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req = req.WithContext(context.WithValue(req.Context(), middleware.Sub, tt.subject))
+				return echo.New().NewContext(req, httptest.NewRecorder())
+			}
+
+			c := request()
+			assert.NoError(t, h.GetReport(c, requestID, models.GetReportParams{}))
+			assert.Equal(t, tt.expectStatus, c.Response().Status)
+
+			if tt.expectStatus == http.StatusNotFound {
+				c = request()
+				assert.NoError(t, h.CancelReport(c, requestID, models.CancelReportParams{}))
+				assert.Equal(t, http.StatusNotFound, c.Response().Status)
+				assert.False(t, db.cancelled)
+			}
+		})
+	}
+}
+
+type mockInlineDatabase struct {
+	database.Interface
+	completed   []time.Time
+	transitions int
+}
+
+func (m *mockInlineDatabase) CompleteInlineJob(_ context.Context, _ string, _ float64, _ *models.ResultBreakdown, at time.Time) (bool, error) {
+	m.completed = append(m.completed, at)
+	return true, nil
+}
+
+func (m *mockInlineDatabase) SetJobStatus(context.Context, string, database.Status) (bool, error) {
+	m.transitions++
+	return true, nil
+}
+
+func (m *mockInlineDatabase) SetJobResult(context.Context, string, float64, *models.ResultBreakdown, *models.DataCoverage) error {
+	m.transitions++
+	return nil
+}
+
+func TestCalculateInline(t *testing.T) {
+	orch, err := orchestrator.NewDummyClient()
+	assert.NoError(t, err)
+	cloudObs, err := cloudobservability.NewDummyClient()
+	assert.NoError(t, err)
+	tv, err := trafficvolume.NewDummyClient()
+	assert.NoError(t, err)
+	runner := inline.NewRunner(&backend.Clients{
+		Orchestrator:       orch,
+		CloudObservability: cloudObs,
+		TrafficVolume:      tv,
+		Calculator:         calculator.NewSimpleClient(0.00035),
+	}, 0)
+
+	tests := []struct {
+		name         string
+		wait         time.Duration
+		expectReport bool
+	}{
+		{name: "job completed in one step with its result", wait: 10 * time.Second, expectReport: true},
+		{name: "job left created when the run times out", wait: time.Nanosecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestID := "req1"
+			job := &database.Job{JobSpec: database.JobSpec{RequestId: &requestID, Service: []models.AppInstanceId{uuid.New()}}}
+			db := &mockInlineDatabase{}
+			h := &handler{database: db, inline: runner}
+
+			report := h.calculateInline(context.Background(), job, tt.wait)
+			assert.Zero(t, db.transitions)
+			if !tt.expectReport {
+				assert.Nil(t, report)
+				assert.Empty(t, db.completed)
+				return
+			}
+			assert.NotNil(t, report)
+			assert.Len(t, db.completed, 1)
+			assert.Equal(t, models.Completed, report.Status)
+		})
+	}
+}
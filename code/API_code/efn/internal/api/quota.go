@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// rateLimitWindow is the length of the fixed windows in which the requests of a subject are counted.
+const rateLimitWindow = time.Minute
+
+// checkRequestQuota rejects a calculate request covering too many application instances, then counts it
+// against the requests per minute limit of the subject. Counters are kept in the database so that the
+// limit holds across the replicas of the API.
+func (h *handler) checkRequestQuota(ctx context.Context, log *zap.Logger, subject string, applications int, now time.Time) *requestError {
+	if limit := h.quota.MaxApplicationsPerRequest; limit > 0 && applications > limit {
+		msg := fmt.Sprintf("a request cannot cover more than %d application instances", limit)
+		log.Warn(msg, zap.Int("applications", applications))
+		return &requestError{status: http.StatusTooManyRequests, code: "QUOTA_EXCEEDED", message: msg}
+	}
+
+	limit := h.quota.RequestsPerMinute
+	if limit <= 0 {
+		return nil
+	}
+	window := now.Truncate(rateLimitWindow)
+	end := window.Add(rateLimitWindow)
+	count, err := h.database.IncrementRequestCount(ctx, subject, window, end.Add(rateLimitWindow))
+	if err != nil {
+		msg := "failed to count request"
+		log.With(zap.Error(err)).Error(msg)
+		return &requestError{status: http.StatusInternalServerError, message: msg}
+	}
+	if count > limit {
+		msg := fmt.Sprintf("rate limit of %d requests per minute reached", limit)
+		log.Warn(msg, zap.Int("count", count))
+		return &requestError{status: http.StatusTooManyRequests, code: "TOO_MANY_REQUESTS", message: msg, retryAfter: end.Sub(now)}
+	}
+	return nil
+}
+
+// checkConcurrentJobs reserves a slot for the job about to be created with jobID, and rejects the calculate
+// request when the subject already has as many reports being processed as allowed. The slot is reserved
+// atomically, so that concurrent requests cannot exceed the limit together.
+func (h *handler) checkConcurrentJobs(ctx context.Context, log *zap.Logger, subject, jobID string) *requestError {
+	limit := h.quota.MaxConcurrentJobs
+	if limit <= 0 {
+		return nil
+	}
+	reserved, err := h.database.ReserveJobSlot(ctx, subject, jobID, limit, time.Now().UTC())
+	if err != nil {
+		msg := "failed to reserve a slot for the report"
+		log.With(zap.Error(err)).Error(msg)
+		return &requestError{status: http.StatusInternalServerError, message: msg}
+	}
+	if !reserved {
+		msg := fmt.Sprintf("limit of %d reports in progress reached", limit)
+		log.Warn(msg)
+		return &requestError{status: http.StatusTooManyRequests, code: "QUOTA_EXCEEDED", message: msg, retryAfter: h.quota.ConcurrencyRetryAfter}
+	}
+	return nil
+}
+
+// retryAfterSeconds formats a delay for the Retry-After header, rounded up to the next second.
+func retryAfterSeconds(d time.Duration) string {
+	return fmt.Sprintf("%d", int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+)
+
+type mockQuotaDatabase struct {
+	database.Interface
+	counts map[time.Time]int
+	slots  []string
+	err    error
+}
+
+func (m *mockQuotaDatabase) IncrementRequestCount(_ context.Context, _ string, window, _ time.Time) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.counts[window]++
+	return m.counts[window], nil
+}
+
+func (m *mockQuotaDatabase) ReserveJobSlot(_ context.Context, _, jobID string, limit int, _ time.Time) (bool, error) {
+	if m.err != nil || len(m.slots) >= limit {
+		return false, m.err
+	}
+	m.slots = append(m.slots, jobID)
+	return true, nil
+}
+
+func TestCheckRequestQuota(t *testing.T) {
+	now := time.Date(2025, 1, 1, 10, 0, 45, 500_000_000, time.UTC)
+
+	t.Run("too many application instances", func(t *testing.T) {
+		db := &mockQuotaDatabase{counts: map[time.Time]int{}}
+		h := &handler{database: db, quota: config.Quota{RequestsPerMinute: 2, MaxApplicationsPerRequest: 3}}
+
+		reqErr := h.checkRequestQuota(context.Background(), zap.NewNop(), "sub", 4, now)
+		assert.NotNil(t, reqErr)
+		assert.Equal(t, http.StatusTooManyRequests, reqErr.status)
+		assert.Equal(t, "QUOTA_EXCEEDED", reqErr.code)
+		assert.Zero(t, reqErr.retryAfter)
+		assert.Empty(t, db.counts)
+	})
+	t.Run("rate limit reached until the end of the window", func(t *testing.T) {
+		db := &mockQuotaDatabase{counts: map[time.Time]int{}}
+		h := &handler{database: db, quota: config.Quota{RequestsPerMinute: 2}}
+
+		assert.Nil(t, h.checkRequestQuota(context.Background(), zap.NewNop(), "sub", 1, now))
+		assert.Nil(t, h.checkRequestQuota(context.Background(), zap.NewNop(), "sub", 1, now))
+		reqErr := h.checkRequestQuota(context.Background(), zap.NewNop(), "sub", 1, now)
+		assert.NotNil(t, reqErr)
+		assert.Equal(t, "TOO_MANY_REQUESTS", reqErr.code)
+		assert.Equal(t, 14500*time.Millisecond, reqErr.retryAfter)
+
+		assert.Nil(t, h.checkRequestQuota(context.Background(), zap.NewNop(), "sub", 1, now.Add(15*time.Second)))
+	})
+	t.Run("disabled limits", func(t *testing.T) {
+		h := &handler{database: &mockQuotaDatabase{err: errors.New("unexpected call")}}
+		assert.Nil(t, h.checkRequestQuota(context.Background(), zap.NewNop(), "sub", 100, now))
+	})
+	t.Run("counter failure", func(t *testing.T) {
+		h := &handler{database: &mockQuotaDatabase{err: errors.New("boom")}, quota: config.Quota{RequestsPerMinute: 2}}
+		reqErr := h.checkRequestQuota(context.Background(), zap.NewNop(), "sub", 1, now)
+		assert.NotNil(t, reqErr)
+		assert.Equal(t, http.StatusInternalServerError, reqErr.status)
+	})
+}
+
+func TestCheckConcurrentJobs(t *testing.T) {
+	quota := config.Quota{MaxConcurrentJobs: 2, ConcurrencyRetryAfter: 30 * time.Second}
+
+	db := &mockQuotaDatabase{slots: []string{"req1"}}
+	h := &handler{database: db, quota: quota}
+	assert.Nil(t, h.checkConcurrentJobs(context.Background(), zap.NewNop(), "sub", "req2"))
+	assert.Equal(t, []string{"req1", "req2"}, db.slots)
+
+	reqErr := h.checkConcurrentJobs(context.Background(), zap.NewNop(), "sub", "req3")
+	assert.NotNil(t, reqErr)
+	assert.Equal(t, "QUOTA_EXCEEDED", reqErr.code)
+	assert.Len(t, db.slots, 2)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	assert.NoError(t, reqErr.send(c))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+
+	h = &handler{database: &mockQuotaDatabase{err: errors.New("boom")}, quota: quota}
+	reqErr = h.checkConcurrentJobs(context.Background(), zap.NewNop(), "sub", "req1")
+	assert.NotNil(t, reqErr)
+	assert.Equal(t, http.StatusInternalServerError, reqErr.status)
+
+	h = &handler{database: &mockQuotaDatabase{err: errors.New("unexpected call")}}
+	assert.Nil(t, h.checkConcurrentJobs(context.Background(), zap.NewNop(), "sub", "req1"))
+}
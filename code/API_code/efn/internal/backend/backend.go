@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backend
+
+import (
+	"os"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/calculator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/cloudobservability"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
+)
+
+// Clients groups the backends used to gather the data of a report and calculate its result.
+type Clients struct {
+	Orchestrator       orchestrator.Interface
+	CloudObservability cloudobservability.Interface
+	TrafficVolume      trafficvolume.Interface
+	Calculator         calculator.Interface
+}
+
+// New creates the backends named by the configuration from the registries of their packages, each data
+// backend guarded with the limits of the configuration.
+func New(cfg config.Backend) (*Clients, error) {
+	orch, err := orchestrator.Backends.New(cfg.Orchestrator)
+	if err != nil {
+		return nil, err
+	}
+	cloudObsName := cfg.CloudObservability
+	if cloudObsName == "" {
+		cloudObsName = legacyCloudObservability()
+	}
+	cloudObs, err := cloudobservability.Backends.New(cloudObsName)
+	if err != nil {
+		return nil, err
+	}
+	trafficName := cfg.TrafficVolume
+	if trafficName == "" {
+		trafficName = legacyTrafficVolume()
+	}
+	tv, err := trafficvolume.Backends.New(trafficName)
+	if err != nil {
+		return nil, err
+	}
+	calc, err := calculator.Backends.New(cfg.Calculator)
+	if err != nil {
+		return nil, err
+	}
+
+	clients := &Clients{
+		Orchestrator:       orch,
+		CloudObservability: cloudObs,
+		TrafficVolume:      tv,
+		Calculator:         calc,
+	}
+	return Guard(clients, cfg), nil
+}
+
+// NewFromEnv creates the backends named by the backend configuration of the environment.
+func NewFromEnv() (*Clients, error) {
+	return New(config.GetConf().Backend)
+}
+
+// legacyCloudObservability names the cloud observability backend selected by the variables used before
+// BACKEND_CLOUD_OBSERVABILITY.
+func legacyCloudObservability() string {
+	switch {
+	case os.Getenv("CLIENT_TYPE") == "configurable":
+		return "configurable"
+	case os.Getenv("CLOUDOBS_FAIL_NE") == "true" || os.Getenv("CLOUDOBS_FAIL_THROTTLE") == "true":
+		return "error-dummy"
+	default:
+		return "dummy"
+	}
+}
+
+// legacyTrafficVolume names the traffic volume backend selected by the variable used before
+// BACKEND_TRAFFIC_VOLUME.
+func legacyTrafficVolume() string {
+	if os.Getenv("TRAFFIC_CLIENT_TYPE") == "configurable" {
+		return "configurable"
+	}
+	return "dummy"
+}
@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("creates the backends by name", func(t *testing.T) {
+		t.Setenv("CARBON_FACTOR_TCO2E_PER_KWH", "0.5")
+		clients, err := New(config.Backend{Orchestrator: "dummy", CloudObservability: "configurable", TrafficVolume: "dummy", Calculator: "simple"})
+		assert.NoError(t, err)
+		assert.NotNil(t, clients.Orchestrator)
+		assert.NotNil(t, clients.CloudObservability)
+		assert.NotNil(t, clients.TrafficVolume)
+		assert.NotNil(t, clients.Calculator)
+	})
+	t.Run("rejects an unknown backend", func(t *testing.T) {
+		_, err := New(config.Backend{Orchestrator: "kubernetes", Calculator: "simple"})
+		assert.ErrorContains(t, err, `unknown orchestrator backend "kubernetes", available: dummy`)
+	})
+	t.Run("rejects an invalid backend configuration", func(t *testing.T) {
+		t.Setenv("CARBON_FACTOR_TCO2E_PER_KWH", "a lot")
+		_, err := New(config.Backend{Orchestrator: "dummy", Calculator: "simple"})
+		assert.ErrorContains(t, err, `failed to create calculator backend "simple"`)
+	})
+}
+
+func TestLegacyBackendNames(t *testing.T) {
+	assert.Equal(t, "dummy", legacyCloudObservability())
+	assert.Equal(t, "dummy", legacyTrafficVolume())
+
+	t.Setenv("CLOUDOBS_FAIL_NE", "true")
+	assert.Equal(t, "error-dummy", legacyCloudObservability())
+
+	t.Setenv("CLIENT_TYPE", "configurable")
+	t.Setenv("TRAFFIC_CLIENT_TYPE", "configurable")
+	assert.Equal(t, "configurable", legacyCloudObservability())
+	assert.Equal(t, "configurable", legacyTrafficVolume())
+}
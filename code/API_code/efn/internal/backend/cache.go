@@ -0,0 +1,306 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"expvar"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/cloudobservability"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
+)
+
+// MeasurementStore shares the cached measurements between replicas. database.Interface implements it.
+type MeasurementStore interface {
+	GetMeasurements(ctx context.Context, keys []string, now time.Time) (map[string]database.Measurement, error)
+	SetMeasurements(ctx context.Context, measurements []database.Measurement) error
+}
+
+// cacheStats counts the measurements found in the cache, and the ones retrieved from the backends.
+var cacheStats = expvar.NewMap("measurementCache")
+
+// Cache puts a cache of the network element energy consumptions and traffic volumes in front of the
+// backends of clients, so that jobs asking for the same network element over the same time window reuse
+// the value retrieved by the first one. Concurrent identical lookups make a single call to the backend.
+// The cache is kept in memory, and in store when it is not nil. A zero TTL returns clients as is.
+func Cache(clients *Clients, store MeasurementStore, cfg config.Cache) *Clients {
+	if cfg.TTL <= 0 {
+		return clients
+	}
+	cache := &measurementCache{
+		ttl:         cfg.TTL,
+		maxEntries:  cfg.MaxEntries,
+		callTimeout: cfg.CallTimeout,
+		store:       store,
+		now:         time.Now,
+		entries:     make(map[string]database.Measurement),
+	}
+	cached := *clients
+	cached.CloudObservability = &cachedCloudObservability{Interface: clients.CloudObservability, cache: cache}
+	cached.TrafficVolume = &cachedTrafficVolume{next: clients.TrafficVolume, cache: cache}
+	return &cached
+}
+
+type measurementCache struct {
+	ttl        time.Duration
+	maxEntries int
+	// callTimeout bounds a call shared by concurrent lookups, zero for no bound.
+	callTimeout time.Duration
+	store       MeasurementStore
+	now         func() time.Time
+	group       singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]database.Measurement
+}
+
+// cacheable tells whether the values over timePeriod are final, and can be reused.
+func (c *measurementCache) cacheable(timePeriod *models.TimePeriod) bool {
+	return timePeriod != nil && timePeriod.EndDate != nil && !timePeriod.EndDate.After(c.now())
+}
+
+// lookup returns the unexpired measurements stored under keys, from memory first, then from the store.
+// A store that cannot be read is only logged: the values are retrieved from the backend instead.
+func (c *measurementCache) lookup(ctx context.Context, keys []string) map[string]database.Measurement {
+	now := c.now()
+	found := make(map[string]database.Measurement, len(keys))
+	missing := make([]string, 0, len(keys))
+	c.mu.Lock()
+	for _, key := range keys {
+		if m, ok := c.entries[key]; ok && m.ExpiresAt.After(now) {
+			found[key] = m
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(missing) > 0 && c.store != nil {
+		stored, err := c.store.GetMeasurements(ctx, missing, now)
+		if err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn("Failed to read cached measurements")
+		}
+		c.mu.Lock()
+		for key, m := range stored {
+			found[key] = m
+			c.put(m)
+		}
+		c.mu.Unlock()
+	}
+	cacheStats.Add("hits", int64(len(found)))
+	cacheStats.Add("misses", int64(len(keys)-len(found)))
+	return found
+}
+
+// share makes the call of fn for the concurrent lookups of key once. The call does not stop when the
+// caller that started it gives up, so that the others waiting for the same key still get the value: it
+// runs detached from the context of that caller, bounded by callTimeout, and each caller stops waiting
+// for it when its own context is done.
+func (c *measurementCache) share(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	ch := c.group.DoChan(key, func() (any, error) {
+		callCtx := context.WithoutCancel(ctx)
+		if c.callTimeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(callCtx, c.callTimeout)
+			defer cancel()
+		}
+		return fn(callCtx)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		return res.Val, res.Err
+	}
+}
+
+// save caches measurements until the TTL elapses.
+func (c *measurementCache) save(ctx context.Context, measurements []database.Measurement) {
+	expiresAt := c.now().Add(c.ttl)
+	c.mu.Lock()
+	for i := range measurements {
+		measurements[i].ExpiresAt = expiresAt
+		c.put(measurements[i])
+	}
+	c.mu.Unlock()
+
+	if c.store != nil {
+		if err := c.store.SetMeasurements(ctx, measurements); err != nil {
+			logger.FromContext(ctx).With(zap.Error(err)).Warn("Failed to store cached measurements")
+		}
+	}
+}
+
+// put keeps a measurement in memory, making room for it when the cache is full: expired measurements
+// go first, then arbitrary ones. The caller holds the lock.
+func (c *measurementCache) put(m database.Measurement) {
+	if _, ok := c.entries[m.Key]; !ok && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		now := c.now()
+		for key, entry := range c.entries {
+			if !entry.ExpiresAt.After(now) {
+				delete(c.entries, key)
+			}
+		}
+		for key := range c.entries {
+			if len(c.entries) < c.maxEntries {
+				break
+			}
+			delete(c.entries, key)
+		}
+	}
+	c.entries[m.Key] = m
+}
+
+// measurementKey identifies the values of a network element over a time period.
+func measurementKey(kind string, timePeriod *models.TimePeriod, parts ...string) string {
+	return strings.Join(append(append([]string{kind}, parts...),
+		timePeriod.StartDate.UTC().Format(time.RFC3339), timePeriod.EndDate.UTC().Format(time.RFC3339)), "|")
+}
+
+// energyKey identifies the energy consumption of a network element, which does not depend on the
+// application instance it serves.
+func energyKey(neInstanceID, neInfraType string, timePeriod *models.TimePeriod) string {
+	return measurementKey("energy", timePeriod, neInfraType, neInstanceID)
+}
+
+// trafficKey identifies the traffic volumes of a network element for the IPs of an application instance.
+func trafficKey(ne trafficvolume.NetworkElement, ipList []string, timePeriod *models.TimePeriod) string {
+	ips := slices.Clone(ipList)
+	slices.Sort(ips)
+	sum := sha256.Sum256([]byte(strings.Join(ips, ",")))
+	return measurementKey("traffic", timePeriod, ne.VendorIdentifier, ne.NEIdentifier, hex.EncodeToString(sum[:8]))
+}
+
+// cachedCloudObservability caches the energy consumption of the network elements. The one of the
+// application instances is retrieved for each job.
+type cachedCloudObservability struct {
+	cloudobservability.Interface
+	cache *measurementCache
+}
+
+func (c *cachedCloudObservability) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID, neInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
+	if !c.cache.cacheable(timePeriod) {
+		return c.Interface.RetrieveNetworkElementEnergyConsumption(ctx, appInstanceID, neInstanceID, timePeriod, neInfraType)
+	}
+	key := energyKey(neInstanceID, neInfraType, timePeriod)
+	if m, ok := c.cache.lookup(ctx, []string{key})[key]; ok && m.EnergyConsumption != nil {
+		consumption := *m.EnergyConsumption
+		return &consumption, nil
+	}
+
+	value, err := c.cache.share(ctx, key, func(ctx context.Context) (any, error) {
+		consumption, err := c.Interface.RetrieveNetworkElementEnergyConsumption(ctx, appInstanceID, neInstanceID, timePeriod, neInfraType)
+		if err != nil {
+			return nil, err
+		}
+		c.cache.save(ctx, []database.Measurement{{Key: key, EnergyConsumption: consumption}})
+		return *consumption, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	consumption := value.(float64)
+	return &consumption, nil
+}
+
+// cachedTrafficVolume caches the traffic volumes of each network element, so that only the network
+// elements missing from the cache are asked to the backend.
+type cachedTrafficVolume struct {
+	next  trafficvolume.Interface
+	cache *measurementCache
+}
+
+func (t *cachedTrafficVolume) RetrieveTrafficVolumes(ctx context.Context, appInstanceIPList []string, networkElements []trafficvolume.NetworkElement, timePeriod *models.TimePeriod) (*trafficvolume.TrafficVolumeMeasureList, error) {
+	if !t.cache.cacheable(timePeriod) {
+		return t.next.RetrieveTrafficVolumes(ctx, appInstanceIPList, networkElements, timePeriod)
+	}
+	keys := make([]string, len(networkElements))
+	for i, ne := range networkElements {
+		keys[i] = trafficKey(ne, appInstanceIPList, timePeriod)
+	}
+	found := t.cache.lookup(ctx, keys)
+
+	var missing []trafficvolume.NetworkElement
+	var missingKeys []string
+	for i, ne := range networkElements {
+		if m, ok := found[keys[i]]; !ok || m.AppInstanceTraffic == nil || m.TotalTraffic == nil {
+			missing = append(missing, ne)
+			missingKeys = append(missingKeys, keys[i])
+		}
+	}
+	if len(missing) > 0 {
+		value, err := t.cache.share(ctx, strings.Join(missingKeys, ";"), func(ctx context.Context) (any, error) {
+			volumes, err := t.next.RetrieveTrafficVolumes(ctx, appInstanceIPList, missing, timePeriod)
+			if err != nil {
+				return nil, err
+			}
+			matched, _ := trafficvolume.Match(missing, volumes)
+			retrieved := make(map[string]database.Measurement, len(matched))
+			for i, ne := range missing {
+				measure, ok := matched[ne]
+				if !ok {
+					continue
+				}
+				key := missingKeys[i]
+				retrieved[key] = database.Measurement{
+					Key:                key,
+					AppInstanceTraffic: &measure.TrafficVolumeIP,
+					TotalTraffic:       &measure.TrafficVolumeAll,
+				}
+			}
+			measurements := make([]database.Measurement, 0, len(retrieved))
+			for _, m := range retrieved {
+				measurements = append(measurements, m)
+			}
+			t.cache.save(ctx, measurements)
+			return retrieved, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for key, m := range value.(map[string]database.Measurement) {
+			found[key] = m
+		}
+	}
+
+	// The network elements the backend has no measure for are left out, as in its own response.
+	volumes := &trafficvolume.TrafficVolumeMeasureList{TrafficVolumeMeasureList: make([]trafficvolume.TrafficVolumeMeasure, 0, len(networkElements))}
+	for i, ne := range networkElements {
+		m, ok := found[keys[i]]
+		if !ok || m.AppInstanceTraffic == nil || m.TotalTraffic == nil {
+			continue
+		}
+		volumes.TrafficVolumeMeasureList = append(volumes.TrafficVolumeMeasureList, trafficvolume.TrafficVolumeMeasure{
+			NetworkElement:   ne,
+			TrafficVolumeIP:  *m.AppInstanceTraffic,
+			TrafficVolumeAll: *m.TotalTraffic,
+		})
+	}
+	return volumes, nil
+}
@@ -0,0 +1,254 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backend
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
+)
+
+// countingBackend counts the calls made to it, and the network elements asked for traffic volumes.
+type countingBackend struct {
+	failingCloudObservability
+	mu         sync.Mutex
+	calls      int
+	asked      [][]string
+	err        error
+	release    chan struct{}
+	unmeasured string
+}
+
+func (b *countingBackend) RetrieveNetworkElementEnergyConsumption(ctx context.Context, _, _ string, _ *models.TimePeriod, _ string) (*float64, error) {
+	if b.release != nil {
+		select {
+		case <-b.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls++
+	if b.err != nil {
+		return nil, b.err
+	}
+	v := float64(b.calls)
+	return &v, nil
+}
+
+func (b *countingBackend) RetrieveTrafficVolumes(_ context.Context, _ []string, networkElements []trafficvolume.NetworkElement, _ *models.TimePeriod) (*trafficvolume.TrafficVolumeMeasureList, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls++
+	ids := make([]string, 0, len(networkElements))
+	volumes := &trafficvolume.TrafficVolumeMeasureList{}
+	for _, ne := range networkElements {
+		ids = append(ids, ne.NEIdentifier)
+		if ne.NEIdentifier == b.unmeasured {
+			continue
+		}
+		volumes.TrafficVolumeMeasureList = append(volumes.TrafficVolumeMeasureList, trafficvolume.TrafficVolumeMeasure{
+			NetworkElement:   ne,
+			TrafficVolumeIP:  float64(b.calls),
+			TrafficVolumeAll: 100,
+		})
+	}
+	b.asked = append(b.asked, ids)
+	return volumes, nil
+}
+
+// memoryStore is a MeasurementStore shared by the caches of several replicas.
+type memoryStore struct {
+	measurements map[string]database.Measurement
+}
+
+func (s *memoryStore) GetMeasurements(_ context.Context, keys []string, now time.Time) (map[string]database.Measurement, error) {
+	found := make(map[string]database.Measurement)
+	for _, key := range keys {
+		if m, ok := s.measurements[key]; ok && m.ExpiresAt.After(now) {
+			found[key] = m
+		}
+	}
+	return found, nil
+}
+
+func (s *memoryStore) SetMeasurements(ctx context.Context, measurements []database.Measurement) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, m := range measurements {
+		s.measurements[m.Key] = m
+	}
+	return nil
+}
+
+func period(start time.Time, length time.Duration) *models.TimePeriod {
+	end := start.Add(length)
+	return &models.TimePeriod{StartDate: start, EndDate: &end}
+}
+
+func TestCachedNetworkElementEnergy(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := config.Cache{TTL: time.Hour, MaxEntries: 10}
+
+	t.Run("reuses the value of a network element over the same window", func(t *testing.T) {
+		b := &countingBackend{}
+		clients := Cache(&Clients{CloudObservability: b}, nil, cfg)
+
+		first, err := clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, 24*time.Hour), "router")
+		assert.NoError(t, err)
+		second, err := clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app2", "ne1", period(day, 24*time.Hour), "router")
+		assert.NoError(t, err)
+		assert.Equal(t, *first, *second)
+		assert.Equal(t, 1, b.calls)
+
+		_, _ = clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, 12*time.Hour), "router")
+		_, _ = clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne2", period(day, 24*time.Hour), "router")
+		assert.Equal(t, 3, b.calls)
+	})
+
+	t.Run("retrieves again once expired", func(t *testing.T) {
+		b := &countingBackend{}
+		clients := Cache(&Clients{CloudObservability: b}, nil, cfg)
+		cache := clients.CloudObservability.(*cachedCloudObservability).cache
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		_, _ = clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, time.Hour), "router")
+		now = now.Add(time.Hour)
+		_, _ = clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, time.Hour), "router")
+		assert.Equal(t, 2, b.calls)
+	})
+
+	t.Run("does not cache errors nor windows still open", func(t *testing.T) {
+		b := &countingBackend{err: errors.New("unavailable")}
+		clients := Cache(&Clients{CloudObservability: b}, nil, cfg)
+
+		for range 2 {
+			_, err := clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, time.Hour), "router")
+			assert.Error(t, err)
+		}
+		b.err = nil
+		for range 2 {
+			_, err := clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(time.Now(), time.Hour), "router")
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 4, b.calls)
+	})
+
+	t.Run("coalesces concurrent lookups", func(t *testing.T) {
+		b := &countingBackend{release: make(chan struct{})}
+		clients := Cache(&Clients{CloudObservability: b}, nil, cfg)
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				consumption, err := clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, time.Hour), "router")
+				assert.NoError(t, err)
+				assert.Equal(t, 1.0, *consumption)
+			}()
+		}
+		time.Sleep(50 * time.Millisecond)
+		close(b.release)
+		wg.Wait()
+		assert.Equal(t, 1, b.calls)
+	})
+
+	t.Run("shared lookup goes on when the caller that started it gives up", func(t *testing.T) {
+		b := &countingBackend{release: make(chan struct{})}
+		store := &memoryStore{measurements: make(map[string]database.Measurement)}
+		clients := Cache(&Clients{CloudObservability: b}, store, cfg)
+
+		firstCtx, cancel := context.WithCancel(ctx)
+		first := make(chan error, 1)
+		go func() {
+			_, err := clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(firstCtx, "app1", "ne1", period(day, time.Hour), "router")
+			first <- err
+		}()
+		time.Sleep(20 * time.Millisecond)
+		second := make(chan *float64, 1)
+		go func() {
+			consumption, err := clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app2", "ne1", period(day, time.Hour), "router")
+			assert.NoError(t, err)
+			second <- consumption
+		}()
+		time.Sleep(20 * time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-first, context.Canceled)
+		close(b.release)
+		if consumption := <-second; assert.NotNil(t, consumption) {
+			assert.Equal(t, 1.0, *consumption)
+		}
+		assert.Equal(t, 1, b.calls)
+		assert.Len(t, store.measurements, 1)
+	})
+
+	t.Run("shares the values between replicas through the store", func(t *testing.T) {
+		b := &countingBackend{}
+		store := &memoryStore{measurements: make(map[string]database.Measurement)}
+		replica1 := Cache(&Clients{CloudObservability: b}, store, cfg)
+		replica2 := Cache(&Clients{CloudObservability: b}, store, cfg)
+
+		_, _ = replica1.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, time.Hour), "router")
+		_, _ = replica2.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app1", "ne1", period(day, time.Hour), "router")
+		assert.Equal(t, 1, b.calls)
+		assert.Len(t, store.measurements, 1)
+	})
+}
+
+func TestCachedTrafficVolumes(t *testing.T) {
+	ctx := context.Background()
+	window := period(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), 24*time.Hour)
+	ne := func(id string) trafficvolume.NetworkElement {
+		return trafficvolume.NetworkElement{VendorIdentifier: "vendor", NEIdentifier: id}
+	}
+	b := &countingBackend{unmeasured: "ne4"}
+	clients := Cache(&Clients{TrafficVolume: b}, nil, config.Cache{TTL: time.Hour})
+
+	volumes, err := clients.TrafficVolume.RetrieveTrafficVolumes(ctx, []string{"10.0.0.1", "10.0.0.2"}, []trafficvolume.NetworkElement{ne("ne1"), ne("ne2")}, window)
+	assert.NoError(t, err)
+	assert.Len(t, volumes.TrafficVolumeMeasureList, 2)
+
+	// Only the network elements missing from the cache are asked, whatever the order of the IPs.
+	volumes, err = clients.TrafficVolume.RetrieveTrafficVolumes(ctx, []string{"10.0.0.2", "10.0.0.1"}, []trafficvolume.NetworkElement{ne("ne3"), ne("ne1"), ne("ne4")}, window)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"ne1", "ne2"}, {"ne3", "ne4"}}, b.asked)
+	if assert.Len(t, volumes.TrafficVolumeMeasureList, 2) {
+		assert.Equal(t, "ne3", volumes.TrafficVolumeMeasureList[0].NetworkElement.NEIdentifier)
+		assert.Equal(t, 2.0, volumes.TrafficVolumeMeasureList[0].TrafficVolumeIP)
+		assert.Equal(t, "ne1", volumes.TrafficVolumeMeasureList[1].NetworkElement.NEIdentifier)
+		assert.Equal(t, 1.0, volumes.TrafficVolumeMeasureList[1].TrafficVolumeIP)
+	}
+
+	// The traffic of the application instance depends on its IPs.
+	_, err = clients.TrafficVolume.RetrieveTrafficVolumes(ctx, []string{"10.0.0.3"}, []trafficvolume.NetworkElement{ne("ne1")}, window)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, b.calls)
+}
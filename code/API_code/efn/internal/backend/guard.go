@@ -0,0 +1,337 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backend
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+)
+
+// Reasons a guard refuses a call.
+const (
+	reasonCircuitOpen      = "circuit breaker open"
+	reasonRateLimited      = "rate limit reached"
+	reasonConcurrencyLimit = "concurrency limit reached"
+)
+
+type breakerState string
+
+const (
+	breakerClosed   breakerState = "closed"
+	breakerOpen     breakerState = "open"
+	breakerHalfOpen breakerState = "half-open"
+)
+
+// outcome classifies the answer of a backend for the breaker and the concurrency limit.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeThrottled
+	outcomeFailure
+	// outcomeIgnored says nothing about the health of the backend, e.g. the caller gave up.
+	outcomeIgnored
+)
+
+// guardStates publishes the state of every guard, served on /debug/vars by the replicas exposing it.
+var guardStates = expvar.NewMap("backends")
+
+// guard protects a backend with a circuit breaker, a token bucket and a concurrency limit following
+// additive increase, multiplicative decrease: the limit grows by one every limit successful calls and
+// is halved on every throttled call.
+type guard struct {
+	name string
+	cfg  config.Backend
+	now  func() time.Time
+
+	mu sync.Mutex
+	// circuit breaker
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	// token bucket
+	tokens     float64
+	refilledAt time.Time
+	// concurrency limit
+	limit    float64
+	inFlight int
+	released chan struct{}
+	// counters
+	calls     uint64
+	rejected  uint64
+	throttled uint64
+	failed    uint64
+}
+
+// guardSnapshot is the state of a guard published in the metrics.
+type guardSnapshot struct {
+	Breaker             string  `json:"breaker"`
+	ConsecutiveFailures int     `json:"consecutiveFailures"`
+	ConcurrencyLimit    float64 `json:"concurrencyLimit"`
+	InFlight            int     `json:"inFlight"`
+	Tokens              float64 `json:"tokens"`
+	Calls               uint64  `json:"calls"`
+	Rejected            uint64  `json:"rejected"`
+	Throttled           uint64  `json:"throttled"`
+	Failed              uint64  `json:"failed"`
+}
+
+// newGuard creates the guard of the backend name.
+func newGuard(name string, cfg config.Backend) *guard {
+	if cfg.RateBurst < 1 {
+		cfg.RateBurst = 1
+	}
+	g := &guard{
+		name:       name,
+		cfg:        cfg,
+		now:        time.Now,
+		state:      breakerClosed,
+		tokens:     float64(cfg.RateBurst),
+		refilledAt: time.Now(),
+		limit:      float64(cfg.MaxConcurrency),
+		released:   make(chan struct{}),
+	}
+	guardStates.Set(name, expvar.Func(func() any { return g.snapshot() }))
+	return g
+}
+
+func (g *guard) snapshot() guardSnapshot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return guardSnapshot{
+		Breaker:             string(g.state),
+		ConsecutiveFailures: g.failures,
+		ConcurrencyLimit:    g.limit,
+		InFlight:            g.inFlight,
+		Tokens:              g.tokens,
+		Calls:               g.calls,
+		Rejected:            g.rejected,
+		Throttled:           g.throttled,
+		Failed:              g.failed,
+	}
+}
+
+// acquire lets a call through, or returns a retryable error when the backend must not be called now.
+// Every call let through must be followed by a call to done with its error.
+func (g *guard) acquire(ctx context.Context) error {
+	if err := g.admit(ctx); err != nil {
+		return err
+	}
+	if err := g.waitToken(ctx); err != nil {
+		g.abandon()
+		return err
+	}
+	if err := g.waitSlot(ctx); err != nil {
+		g.abandon()
+		return err
+	}
+	return nil
+}
+
+// admit applies the circuit breaker. Once the cooldown of an open breaker has elapsed, a single probe
+// call is let through to decide whether it closes again.
+func (g *guard) admit(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.state == breakerOpen {
+		elapsed := g.now().Sub(g.openedAt)
+		if elapsed < g.cfg.BreakerCooldown {
+			return g.reject(reasonCircuitOpen, g.cfg.BreakerCooldown-elapsed)
+		}
+		g.setState(ctx, breakerHalfOpen)
+	}
+	if g.state == breakerHalfOpen {
+		if g.probing {
+			return g.reject(reasonCircuitOpen, g.cfg.BreakerCooldown)
+		}
+		g.probing = true
+	}
+	return nil
+}
+
+// abandon releases the probe of a half-open breaker when its call is not made.
+func (g *guard) abandon() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.probing = false
+}
+
+// waitToken takes a token from the bucket, waiting for it to be refilled when this takes less than MaxWait.
+// The token of a call whose context is done during the wait is put back, as the call is not made.
+func (g *guard) waitToken(ctx context.Context) error {
+	if g.cfg.RateLimit <= 0 {
+		return nil
+	}
+	g.mu.Lock()
+	now := g.now()
+	g.tokens = math.Min(float64(g.cfg.RateBurst), g.tokens+now.Sub(g.refilledAt).Seconds()*g.cfg.RateLimit)
+	g.refilledAt = now
+	var wait time.Duration
+	if g.tokens < 1 {
+		wait = time.Duration((1 - g.tokens) / g.cfg.RateLimit * float64(time.Second))
+		if wait > g.cfg.MaxWait {
+			err := g.reject(reasonRateLimited, wait)
+			g.mu.Unlock()
+			return err
+		}
+	}
+	// The token is taken now, a waiting call leaves the bucket in debt.
+	g.tokens--
+	g.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		g.mu.Lock()
+		g.tokens = math.Min(float64(g.cfg.RateBurst), g.tokens+1)
+		g.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitSlot takes one of the calls in flight allowed by the concurrency limit, waiting up to MaxWait for
+// one to be released.
+func (g *guard) waitSlot(ctx context.Context) error {
+	timer := time.NewTimer(g.cfg.MaxWait)
+	defer timer.Stop()
+	for {
+		g.mu.Lock()
+		if g.cfg.MaxConcurrency <= 0 || g.inFlight < int(g.limit) {
+			g.inFlight++
+			g.calls++
+			g.mu.Unlock()
+			return nil
+		}
+		released := g.released
+		g.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-released:
+		case <-timer.C:
+			g.mu.Lock()
+			err := g.reject(reasonConcurrencyLimit, g.cfg.MaxWait)
+			g.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// done records the error returned by a call let through by acquire.
+func (g *guard) done(ctx context.Context, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.inFlight--
+	close(g.released)
+	g.released = make(chan struct{})
+	g.probing = false
+
+	switch g.classify(ctx, err) {
+	case outcomeSuccess:
+		g.failures = 0
+		if g.state == breakerHalfOpen {
+			g.setState(ctx, breakerClosed)
+		}
+		if g.cfg.MaxConcurrency > 0 {
+			g.limit = math.Min(float64(g.cfg.MaxConcurrency), g.limit+1/g.limit)
+		}
+	case outcomeThrottled:
+		g.throttled++
+		if g.cfg.MaxConcurrency > 0 {
+			g.limit = math.Max(1, g.limit/2)
+			logger.FromContext(ctx).With(zap.String("backend", g.name), zap.Float64("concurrencyLimit", g.limit)).
+				Warn("Backend throttled, concurrency limit decreased")
+		}
+		g.recordFailure(ctx)
+	case outcomeFailure:
+		g.failed++
+		g.recordFailure(ctx)
+	}
+}
+
+// classify tells what the error returned by a call says about the health of the backend. A permanent,
+// not found or unauthorized error is an answer of a healthy backend about the call it was asked.
+func (g *guard) classify(ctx context.Context, err error) outcome {
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case datasource.IsCancelled(err) && ctx.Err() != nil:
+		return outcomeIgnored
+	case datasource.IsThrottled(err):
+		return outcomeThrottled
+	case datasource.IsRetryable(err):
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
+
+func (g *guard) recordFailure(ctx context.Context) {
+	g.failures++
+	switch g.state {
+	case breakerHalfOpen:
+		g.setState(ctx, breakerOpen)
+	case breakerClosed:
+		if g.cfg.BreakerThreshold > 0 && g.failures >= g.cfg.BreakerThreshold {
+			g.setState(ctx, breakerOpen)
+		}
+	}
+}
+
+func (g *guard) setState(ctx context.Context, state breakerState) {
+	log := logger.FromContext(ctx).With(
+		zap.String("backend", g.name),
+		zap.String("from", string(g.state)),
+		zap.String("to", string(state)),
+		zap.Int("consecutiveFailures", g.failures),
+	)
+	if state == breakerOpen {
+		g.openedAt = g.now()
+		log.Warn("Backend circuit breaker opened")
+	} else {
+		log.Info("Backend circuit breaker state changed")
+	}
+	g.state = state
+}
+
+// reject counts a call refused by the guard. The caller holds the lock.
+func (g *guard) reject(reason string, retryAfter time.Duration) error {
+	g.rejected++
+	return &datasource.Error{
+		Class:      datasource.ClassRetryable,
+		Message:    fmt.Sprintf("%s backend unavailable: %s", g.name, reason),
+		RetryAfter: retryAfter,
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
+)
+
+// testGuard returns a guard reading the time from the returned clock.
+func testGuard(cfg config.Backend) (*guard, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := newGuard("test", cfg)
+	g.now = func() time.Time { return now }
+	g.refilledAt = now
+	return g, &now
+}
+
+// call makes a call returning err through the guard.
+func call(g *guard, err error) error {
+	ctx := context.Background()
+	if acquireErr := g.acquire(ctx); acquireErr != nil {
+		return acquireErr
+	}
+	g.done(ctx, err)
+	return err
+}
+
+func assertUnavailable(t *testing.T, err error, reason string) {
+	t.Helper()
+	assert.Equal(t, datasource.ClassRetryable, datasource.ClassOf(err))
+	assert.EqualError(t, err, "test backend unavailable: "+reason)
+}
+
+func TestGuardCircuitBreaker(t *testing.T) {
+	backendErr := errors.New("connection refused")
+	cfg := config.Backend{BreakerThreshold: 2, BreakerCooldown: 30 * time.Second}
+
+	tests := []struct {
+		name          string
+		errs          []error
+		advance       time.Duration
+		probeErr      error
+		expectedState breakerState
+	}{
+		{
+			name:          "permanent failures keep the breaker closed",
+			errs:          []error{datasource.NewPermanent("no data"), fmt.Errorf("app: %w", datasource.NewNotFound("unknown network element"))},
+			expectedState: breakerClosed,
+		},
+		{
+			name:          "success resets the consecutive failures",
+			errs:          []error{backendErr, nil, backendErr},
+			expectedState: breakerClosed,
+		},
+		{
+			name:          "successful probe closes the breaker",
+			errs:          []error{backendErr, datasource.NewThrottled("slow down", 0)},
+			advance:       30 * time.Second,
+			expectedState: breakerClosed,
+		},
+		{
+			name:          "failed probe opens the breaker again",
+			errs:          []error{backendErr, backendErr},
+			advance:       time.Minute,
+			probeErr:      backendErr,
+			expectedState: breakerOpen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, now := testGuard(cfg)
+			for _, err := range tt.errs {
+				assert.Equal(t, err, call(g, err))
+			}
+			if tt.advance == 0 {
+				assert.Equal(t, tt.expectedState, g.state)
+				return
+			}
+
+			assert.Equal(t, breakerOpen, g.state)
+			assertUnavailable(t, call(g, nil), reasonCircuitOpen)
+
+			*now = now.Add(tt.advance)
+			assert.Equal(t, tt.probeErr, call(g, tt.probeErr))
+			assert.Equal(t, tt.expectedState, g.state)
+		})
+	}
+}
+
+func TestGuardHalfOpenLetsASingleProbeThrough(t *testing.T) {
+	g, now := testGuard(config.Backend{BreakerThreshold: 1, BreakerCooldown: time.Second})
+	_ = call(g, errors.New("timeout"))
+	*now = now.Add(time.Second)
+
+	ctx := context.Background()
+	assert.NoError(t, g.acquire(ctx))
+	assertUnavailable(t, g.acquire(ctx), reasonCircuitOpen)
+	g.done(ctx, nil)
+	assert.NoError(t, call(g, nil))
+}
+
+func TestGuardRateLimit(t *testing.T) {
+	g, now := testGuard(config.Backend{RateLimit: 1, RateBurst: 2})
+
+	assert.NoError(t, call(g, nil))
+	assert.NoError(t, call(g, nil))
+	err := call(g, nil)
+	assertUnavailable(t, err, reasonRateLimited)
+	assert.Equal(t, time.Second, datasource.RetryAfter(fmt.Errorf("wrapped: %w", err)))
+
+	*now = now.Add(time.Second)
+	assert.NoError(t, call(g, nil))
+	assert.Equal(t, uint64(1), g.snapshot().Rejected)
+}
+
+func TestGuardRateLimitCancelledWait(t *testing.T) {
+	g, now := testGuard(config.Backend{RateLimit: 1, RateBurst: 1, MaxWait: time.Minute})
+	assert.NoError(t, call(g, nil))
+
+	// The call giving up while waiting for the bucket to be refilled does not use its token.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, g.acquire(ctx), context.Canceled)
+	assert.Equal(t, 0.0, g.snapshot().Tokens)
+
+	*now = now.Add(time.Second)
+	assert.NoError(t, g.waitToken(context.Background()))
+	assert.Equal(t, 0.0, g.snapshot().Tokens)
+}
+
+func TestGuardConcurrencyLimit(t *testing.T) {
+	g, _ := testGuard(config.Backend{MaxConcurrency: 4})
+	ctx := context.Background()
+
+	// Throttling halves the limit down to a single call in flight.
+	_ = call(g, datasource.NewThrottled("slow down", 0))
+	_ = call(g, datasource.NewThrottled("slow down", 0))
+	_ = call(g, datasource.NewThrottled("slow down", 0))
+	assert.Equal(t, 1.0, g.limit)
+
+	assert.NoError(t, g.acquire(ctx))
+	assertUnavailable(t, g.acquire(ctx), reasonConcurrencyLimit)
+
+	// A call waiting for a slot gets the one released.
+	acquired := make(chan error)
+	g.cfg.MaxWait = time.Minute
+	go func() { acquired <- g.acquire(ctx) }()
+	g.done(ctx, nil)
+	assert.NoError(t, <-acquired)
+	g.done(ctx, nil)
+
+	// Successes grow the limit back by one per limit calls, up to the maximum.
+	assert.Equal(t, 2.5, g.limit)
+	for range 20 {
+		_ = call(g, nil)
+	}
+	assert.Equal(t, 4.0, g.limit)
+	assert.Equal(t, 0, g.snapshot().InFlight)
+}
+
+func TestGuardedCloudObservability(t *testing.T) {
+	clients := Guard(&Clients{CloudObservability: failingCloudObservability{}}, config.Backend{BreakerThreshold: 1, BreakerCooldown: time.Minute})
+	ctx := context.Background()
+	period := &models.TimePeriod{StartDate: time.Now().Add(-time.Hour)}
+
+	_, err := clients.CloudObservability.RetrieveAppEnergyConsumption(ctx, "app", period, "os")
+	assert.True(t, datasource.IsThrottled(err))
+
+	_, err = clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, "app", "ne1", period, "gNB")
+	assert.False(t, datasource.IsThrottled(err))
+	assert.True(t, datasource.IsRetryable(err))
+}
+
+type failingCloudObservability struct{}
+
+func (failingCloudObservability) RetrieveAppEnergyConsumption(context.Context, string, *models.TimePeriod, string) (*float64, error) {
+	return nil, datasource.NewThrottled("slow down", 0)
+}
+
+func (failingCloudObservability) RetrieveNetworkElementEnergyConsumption(context.Context, string, string, *models.TimePeriod, string) (*float64, error) {
+	return nil, datasource.NewThrottled("slow down", 0)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backend
+
+import (
+	"context"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/cloudobservability"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
+)
+
+// Names of the guarded backends, used in the errors, the logs and the metrics.
+const (
+	nameOrchestrator       = "orchestrator"
+	nameCloudObservability = "cloud-observability"
+	nameTrafficVolume      = "traffic-volume"
+)
+
+// Guard wraps the data backends of clients so that they are not called while unavailable, nor beyond
+// the rate and concurrency they accept. The calculator runs locally and is left as is.
+func Guard(clients *Clients, cfg config.Backend) *Clients {
+	return &Clients{
+		Orchestrator: &guardedOrchestrator{
+			next:  clients.Orchestrator,
+			guard: newGuard(nameOrchestrator, cfg),
+		},
+		CloudObservability: &guardedCloudObservability{
+			next:  clients.CloudObservability,
+			guard: newGuard(nameCloudObservability, cfg),
+		},
+		TrafficVolume: &guardedTrafficVolume{
+			next:  clients.TrafficVolume,
+			guard: newGuard(nameTrafficVolume, cfg),
+		},
+		Calculator: clients.Calculator,
+	}
+}
+
+type guardedOrchestrator struct {
+	next  orchestrator.Interface
+	guard *guard
+}
+
+func (o *guardedOrchestrator) GatherInformation(ctx context.Context, appInstanceID string) (orchestrator.Information, error) {
+	if err := o.guard.acquire(ctx); err != nil {
+		return orchestrator.Information{}, err
+	}
+	info, err := o.next.GatherInformation(ctx, appInstanceID)
+	o.guard.done(ctx, err)
+	return info, err
+}
+
+type guardedCloudObservability struct {
+	next  cloudobservability.Interface
+	guard *guard
+}
+
+func (c *guardedCloudObservability) RetrieveAppEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, appInfraType string) (*float64, error) {
+	if err := c.guard.acquire(ctx); err != nil {
+		return nil, err
+	}
+	consumption, err := c.next.RetrieveAppEnergyConsumption(ctx, appInstanceID, timePeriod, appInfraType)
+	c.guard.done(ctx, err)
+	return consumption, err
+}
+
+func (c *guardedCloudObservability) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID, neInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
+	if err := c.guard.acquire(ctx); err != nil {
+		return nil, err
+	}
+	consumption, err := c.next.RetrieveNetworkElementEnergyConsumption(ctx, appInstanceID, neInstanceID, timePeriod, neInfraType)
+	c.guard.done(ctx, err)
+	return consumption, err
+}
+
+type guardedTrafficVolume struct {
+	next  trafficvolume.Interface
+	guard *guard
+}
+
+func (t *guardedTrafficVolume) RetrieveTrafficVolumes(ctx context.Context, ipList []string, networkElements []trafficvolume.NetworkElement, timePeriod *models.TimePeriod) (*trafficvolume.TrafficVolumeMeasureList, error) {
+	if err := t.guard.acquire(ctx); err != nil {
+		return nil, err
+	}
+	volumes, err := t.next.RetrieveTrafficVolumes(ctx, ipList, networkElements, timePeriod)
+	t.guard.done(ctx, err)
+	return volumes, err
+}
@@ -17,6 +17,8 @@ package database
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
 )
@@ -24,20 +26,186 @@ import (
 type Status string
 
 const (
-	StatusPending    Status = "pending"
-	StatusProcessing Status = "processing"
-	StatusCompleted  Status = "completed"
-	StatusFailed     Status = "failed"
+	StatusCreated     Status = "created"
+	StatusGathering   Status = "gathering"
+	StatusCalculating Status = "calculating"
+	StatusNotifying   Status = "notifying"
+	StatusCompleted   Status = "completed"
+	StatusFailed      Status = "failed"
+	StatusCancelled   Status = "cancelled"
 )
 
+// IsFinal reports whether no further processing happens for a job in this status.
+func (s Status) IsFinal() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCancelled
+}
+
+// statusPredecessors lists, for each status, the statuses a job can move to it from.
+// A job is created in StatusCreated and goes through gathering, calculating and notifying
+// before being completed; it can fail or be cancelled before its result is calculated.
+// A periodic report stays in gathering from its first run until it is completed.
+var statusPredecessors = map[Status][]Status{
+	StatusGathering:   {StatusCreated},
+	StatusCalculating: {StatusGathering},
+	StatusNotifying:   {StatusCalculating},
+	StatusCompleted:   {StatusNotifying, StatusGathering},
+	StatusFailed:      {StatusCreated, StatusGathering, StatusCalculating, StatusNotifying},
+	StatusCancelled:   {StatusCreated, StatusGathering, StatusCalculating},
+}
+
+// CanTransition reports whether a job can move from one status to another. A job without status is created.
+func CanTransition(from, to Status) bool {
+	if from == "" {
+		from = StatusCreated
+	}
+	for _, s := range statusPredecessors[to] {
+		if s == from {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition records a status change of a job.
+type Transition struct {
+	Status Status    `bson:"status"`
+	At     time.Time `bson:"at"`
+}
+
+type FailureCause string
+
+const (
+	// FailureCauseBackendError is a permanent error returned by a backend.
+	FailureCauseBackendError FailureCause = "backend-error"
+	// FailureCauseRetriesExhausted is an event dead-lettered after all its retries.
+	FailureCauseRetriesExhausted FailureCause = "retries-exhausted"
+	// FailureCauseTimeout is a job the watchdog could not resume before its deadline.
+	FailureCauseTimeout FailureCause = "timeout"
+)
+
+// Failure is the structured reason a job failed.
+type Failure struct {
+	// Stage is the status the job was in when it failed.
+	Stage Status       `bson:"stage"`
+	Cause FailureCause `bson:"cause"`
+	// EventType is the type of the event whose processing failed, if any.
+	EventType string `bson:"eventType,omitempty"`
+}
+
 type Job struct {
 	JobSpec `bson:",inline"`
+	// Subject identifies the principal that created the job.
+	Subject string `bson:"subject,omitempty"`
+	// XCorrelator is the x-correlator header of the request that created the job, if any.
+	XCorrelator string `bson:"xCorrelator,omitempty"`
+	// Status is the current processing status of the job.
+	// Absence means the job has not been picked up yet (created).
+	Status Status `bson:"status,omitempty"`
+	// Transitions records the status changes of the job, oldest first, starting with its creation.
+	Transitions []Transition `bson:"transitions,omitempty"`
+	// CreatedAt is the time at which the API accepted the request.
+	CreatedAt time.Time `bson:"createdAt"`
+	// UpdatedAt is the time of the last status change.
+	UpdatedAt *time.Time `bson:"updatedAt,omitempty"`
+	// CompletedAt is set when the job reaches a final status (completed, failed or cancelled).
+	CompletedAt *time.Time `bson:"completedAt,omitempty"`
+	// Result is the final calculated value: kWh for energy consumption jobs, tCO2e for carbon footprint jobs.
+	Result *float64 `bson:"result,omitempty"`
+	// Breakdown splits Result per application instance and network element. Only set when requested.
+	Breakdown *models.ResultBreakdown `bson:"breakdown,omitempty"`
+	// Coverage describes the data Result is based on. Only set when partial results are allowed.
+	Coverage *models.DataCoverage `bson:"coverage,omitempty"`
+	// Error describes why the job failed. Only set when Status is failed.
+	Error *models.ErrorInfo `bson:"error,omitempty"`
+	// Failure is the structured reason of Error.
+	Failure *Failure `bson:"failure,omitempty"`
 	// CalculationTriggered is set when the calculation event has been emitted.
 	// Absence or false means it can still be triggered.
 	CalculationTriggered bool `bson:"calculationTriggered,omitempty"`
-	// NotificationSent is set when a notification event has been sent to the subscriber.
+	// NotificationSent is set once the sink of the subscriber has confirmed the delivery of a notification.
 	// Prevents duplicate notifications from multiple DLQ events or concurrent failures.
 	NotificationSent bool `bson:"notificationSent,omitempty"`
+	// Delivery tracks the attempts to deliver the notification of the job. Absent until the first attempt.
+	Delivery *Delivery `bson:"delivery,omitempty"`
+	// Schedule is set on the job of a periodic report, which is never calculated itself
+	// but spawns a child job at every run.
+	Schedule *Schedule `bson:"schedule,omitempty"`
+	// ParentID is the ID of the periodic report a child job is a run of.
+	ParentID string `bson:"parentId,omitempty"`
+	// Run is the 1-based number of the run of a child job.
+	Run int `bson:"run,omitempty"`
+	// Resumes counts the times the watchdog sent the missing events of the job again, last at ResumedAt.
+	Resumes   int        `bson:"resumes,omitempty"`
+	ResumedAt *time.Time `bson:"resumedAt,omitempty"`
+}
+
+// TimeWindows splits the time period of the job into consecutive windows of size, whose data is fetched
+// from the backends separately. The last window is shorter when the period is not a multiple of size.
+// A period without end date ends when the job was created, so that the windows of a job never change.
+// The period is kept whole when size is zero or the period is unknown.
+func (j *Job) TimeWindows(size time.Duration) []*models.TimePeriod {
+	period := j.TimePeriod
+	if size <= 0 || period == nil {
+		return []*models.TimePeriod{period}
+	}
+	end := j.CreatedAt
+	if period.EndDate != nil {
+		end = *period.EndDate
+	}
+	if !end.After(period.StartDate) {
+		return []*models.TimePeriod{period}
+	}
+
+	windows := make([]*models.TimePeriod, 0, int(end.Sub(period.StartDate)/size)+1)
+	for start := period.StartDate; start.Before(end); start = start.Add(size) {
+		windowEnd := start.Add(size)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		windows = append(windows, &models.TimePeriod{StartDate: start, EndDate: &windowEnd})
+	}
+	return windows
+}
+
+// Schedule tracks the runs of a periodic report.
+type Schedule struct {
+	// Period is the time between two runs, and the length of the window each run covers.
+	Period time.Duration `bson:"period"`
+	// NextRunAt is the time at which the next run is due. The run covers the period ending at this time.
+	NextRunAt time.Time `bson:"nextRunAt"`
+	// Runs is the number of runs started so far.
+	Runs int `bson:"runs"`
+	// LeaseUntil is set while a scheduler instance is starting a run, so that it is not started twice.
+	LeaseUntil *time.Time `bson:"leaseUntil,omitempty"`
+}
+
+// DeliveryState is the state of the delivery of the notification of a job.
+type DeliveryState string
+
+const (
+	// DeliveryClaimed is set when a notification instance takes the delivery over, before calling the sink.
+	DeliveryClaimed DeliveryState = "claimed"
+	// DeliveryDelivering is set while the notification is being sent to the sink.
+	DeliveryDelivering DeliveryState = "delivering"
+	// DeliveryDelivered is final: the sink answered the notification with a 2xx status.
+	DeliveryDelivered DeliveryState = "delivered"
+	// DeliveryFailed is set when the last attempt failed. The delivery can be claimed again.
+	DeliveryFailed DeliveryState = "failed"
+)
+
+// Delivery tracks the delivery of the notification of a job, one attempt at a time. An attempt holds the
+// delivery until LeaseUntil: past it, the attempt is deemed crashed and the delivery can be claimed again.
+type Delivery struct {
+	State DeliveryState `bson:"state"`
+	// Owner identifies the attempt holding the delivery.
+	Owner      string    `bson:"owner"`
+	LeaseUntil time.Time `bson:"leaseUntil"`
+	// Attempts counts the attempts that claimed the delivery.
+	Attempts int `bson:"attempts"`
+	// LastError is the reason of the last failed attempt.
+	LastError   string     `bson:"lastError,omitempty"`
+	UpdatedAt   time.Time  `bson:"updatedAt"`
+	DeliveredAt *time.Time `bson:"deliveredAt,omitempty"`
 }
 
 type RequestKind string
@@ -61,7 +229,19 @@ type JobSpec struct {
 	TimePeriod          *models.TimePeriod         `bson:"timePeriod,omitempty"`
 }
 
-// JobAppResult represents the result of a single appId within a Job.
+// IncludeBreakdown reports whether the subscriber asked for the breakdown of the result.
+func (s JobSpec) IncludeBreakdown() bool {
+	include := s.SubscriptionRequest.Config.SubscriptionDetail.IncludeBreakdown
+	return include != nil && *include
+}
+
+// AllowPartialResults reports whether the subscriber accepts a result calculated without the data that cannot be retrieved.
+func (s JobSpec) AllowPartialResults() bool {
+	allow := s.SubscriptionRequest.Config.SubscriptionDetail.AllowPartialResults
+	return allow != nil && *allow
+}
+
+// JobAppResult represents the result of a single appId within a Job, over one window of its time period.
 // It stores the job reference, app identifier, current status, and the final result payload.
 type JobAppResult struct {
 	JobAppResultMetadata `bson:",inline"`
@@ -72,24 +252,205 @@ type JobAppResultMetadata struct {
 	// JobID is the identifier of the request this result belongs to.
 	JobID string `bson:"jobId"`
 	AppID string `bson:"appId"`
+	// Window is the 0-based index of the window of the time period of the job this result covers.
+	Window int `bson:"window"`
+	// NumberOfWindows is the number of windows the time period of the job is split into. Zero means one.
+	NumberOfWindows int `bson:"numberOfWindows,omitempty"`
 	// Total number of NEs considered in the calculation.
 	NumberOfTotalNEs int `bson:"numberOfTotalNEs"`
 }
 
+// Windows returns the number of windows the time period of the job is split into.
+func (m JobAppResultMetadata) Windows() int {
+	return max(m.NumberOfWindows, 1)
+}
+
+// NetworkElementID identifies a network element. Network elements of different vendors may share an ID,
+// so the results of an application instance are keyed by both.
+type NetworkElementID struct {
+	VendorID     string `bson:"vendorId,omitempty"`
+	NEInstanceID string `bson:"neInstanceId,omitempty"`
+}
+
+// networkElementKeyEscaper escapes the separator of a network element key, and the characters MongoDB
+// gives a meaning to in field names.
+var networkElementKeyEscaper = strings.NewReplacer("%", "%25", "/", "%2F", ".", "%2E", "$", "%24")
+
+// Key returns the key of the network element in the results of an application instance. A network element
+// without vendor is keyed by its ID alone.
+func (id NetworkElementID) Key() string {
+	ne := networkElementKeyEscaper.Replace(id.NEInstanceID)
+	if id.VendorID == "" {
+		return ne
+	}
+	return networkElementKeyEscaper.Replace(id.VendorID) + "/" + ne
+}
+
 // NetworkElementResult holds all results for a single NE in a job/app context.
 type NetworkElementResult struct {
+	NetworkElementID   `bson:",inline"`
 	EnergyConsumption  *float64 `bson:"energyConsumption,omitempty"`
 	AppInstanceTraffic *float64 `bson:"appInstanceTraffic,omitempty"`
 	TotalTraffic       *float64 `bson:"totalTraffic,omitempty"`
+	// Failure is set when the energy or traffic of the network element could not be retrieved
+	// and the job is calculated without it. It holds the reason.
+	Failure string `bson:"failure,omitempty"`
+	// TrafficEstimate is set when the traffic of the network element was missing from the Traffic Volume
+	// response and the job is calculated with an estimated share instead. It holds the estimation method.
+	TrafficEstimate models.EstimatedNetworkElementMethod `bson:"trafficEstimate,omitempty"`
+}
+
+// ID returns the ID of the network element stored under key. The results gathered before the ID was
+// stored along the values are keyed by the ID of the network element alone.
+func (ne NetworkElementResult) ID(key string) NetworkElementID {
+	if ne.NEInstanceID == "" {
+		return NetworkElementID{NEInstanceID: key}
+	}
+	return ne.NetworkElementID
+}
+
+// HasTraffic reports whether the traffic of the network element has been measured or estimated.
+func (ne NetworkElementResult) HasTraffic() bool {
+	return ne.TrafficEstimate != "" || (ne.AppInstanceTraffic != nil && ne.TotalTraffic != nil)
+}
+
+// IsComplete reports whether the application energy and all the expected network element
+// energy and traffic values have been gathered for this application instance.
+func (r JobAppResult) IsComplete() bool {
+	if r.Result == nil || r.Result.AppInstanceEnergyConsumption == nil {
+		return false
+	}
+	if r.NumberOfTotalNEs != len(r.Result.NetworkElements) {
+		return false
+	}
+	for _, ne := range r.Result.NetworkElements {
+		if ne.EnergyConsumption == nil || !ne.HasTraffic() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsResolved reports whether every value expected for this application instance has either been gathered
+// or been marked as failed, so that nothing more is awaited for it.
+func (r JobAppResult) IsResolved() bool {
+	if r.Result == nil || (r.Result.AppInstanceEnergyConsumption == nil && r.Result.AppInstanceFailure == "") {
+		return false
+	}
+	if r.NumberOfTotalNEs != len(r.Result.NetworkElements) {
+		return false
+	}
+	for _, ne := range r.Result.NetworkElements {
+		if ne.Failure == "" && (ne.EnergyConsumption == nil || !ne.HasTraffic()) {
+			return false
+		}
+	}
+	return true
 }
 
 // TaskResult holds the computed consumption/carbon data for an AppID.
 type TaskResult struct {
 	AppInstanceEnergyConsumption *float64 `bson:"appInstanceEnergyConsumption"`
-	// Maps network element instance ID to all NE results.
+	// AppInstanceFailure is set when the energy consumption of the application instance could not be retrieved
+	// and the job is calculated without it. It holds the reason.
+	AppInstanceFailure string `bson:"appInstanceFailure,omitempty"`
+	// Maps the key of each network element (NetworkElementID.Key) to its results.
 	NetworkElements map[string]NetworkElementResult `bson:"networkElements"`
 }
 
+// IdempotencyKey records a report submission made with an idempotency key, so that retries
+// of the same submission by the same subject are answered without creating a new job.
+type IdempotencyKey struct {
+	Subject string `bson:"subject"`
+	Key     string `bson:"key"`
+	// Fingerprint identifies the submitted request, to tell a retry from a different request reusing the key.
+	Fingerprint string `bson:"fingerprint"`
+	RequestID   string `bson:"requestId"`
+	// ResponseStatus and ResponseBody are the status and JSON body returned to the first submission: the
+	// request with a 201, or the report calculated inline with a 200. Absence means the submission is still
+	// in progress.
+	ResponseStatus int       `bson:"responseStatus,omitempty"`
+	ResponseBody   []byte    `bson:"responseBody,omitempty"`
+	CreatedAt      time.Time `bson:"createdAt"`
+	// LeaseUntil is the time until which the submission in progress holds the key. Past it, a retry of
+	// the same request takes the submission over.
+	LeaseUntil time.Time `bson:"leaseUntil"`
+	// ExpiresAt is the time after which the key is forgotten.
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// RequestCounter counts the calculate requests of a subject within a fixed rate limit window.
+type RequestCounter struct {
+	Subject string `bson:"subject"`
+	// Window is the start of the window.
+	Window time.Time `bson:"window"`
+	Count  int       `bson:"count"`
+	// ExpiresAt is the time after which the counter is removed.
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// JobSlots holds the slots of the jobs of a subject counted against its limit of reports in progress.
+// A slot is added for every job created by the API, and the slots of jobs that have reached a final status
+// are released when the limit is reached, by rebuilding them from the jobs in progress.
+type JobSlots struct {
+	Subject string    `bson:"_id"`
+	Jobs    []JobSlot `bson:"jobs"`
+	// Version is incremented on every change, so that a rebuild does not overwrite a concurrent reservation.
+	Version int `bson:"version"`
+}
+
+// JobSlot is the slot of a job, reserved at ReservedAt.
+type JobSlot struct {
+	JobID      string    `bson:"jobId"`
+	ReservedAt time.Time `bson:"reservedAt"`
+}
+
+// Measurement is a value retrieved from a backend for a network element over a time window, shared by the
+// jobs asking for it again before it expires. Only the values retrieved are set.
+type Measurement struct {
+	// Key identifies the network element, the time window and whatever else the value depends on.
+	Key                string   `bson:"_id"`
+	EnergyConsumption  *float64 `bson:"energyConsumption,omitempty"`
+	AppInstanceTraffic *float64 `bson:"appInstanceTraffic,omitempty"`
+	TotalTraffic       *float64 `bson:"totalTraffic,omitempty"`
+	// ExpiresAt is the time after which the measurement is retrieved again.
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// ProcessedEvent records an event the worker has handled, so that a redelivery of the same event is
+// acknowledged without being handled again.
+type ProcessedEvent struct {
+	EventID     string    `bson:"eventId"`
+	EventType   string    `bson:"eventType"`
+	ProcessedAt time.Time `bson:"processedAt"`
+	// ExpiresAt is the time after which the event is forgotten.
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// JobFilter selects the jobs returned by ListJobs. Zero-valued fields do not filter.
+type JobFilter struct {
+	// Subject restricts the jobs to the ones created by this principal. It is required.
+	Subject     string
+	Status      *Status
+	RequestKind *RequestKind
+	// CreatedFrom is inclusive, CreatedTo is exclusive.
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	AppInstanceID *models.AppInstanceId
+	// ParentID restricts the jobs to the runs of this periodic report.
+	ParentID string
+	// After resumes the listing right after the job it points to.
+	After *JobCursor
+	// Limit is the maximum number of jobs returned. Zero means no limit.
+	Limit int
+}
+
+// JobCursor is the position of a job in a listing ordered by creation time, most recent first.
+type JobCursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        string    `json:"id"`
+}
+
 type Interface interface {
 	// CreateJob inserts a new Job with immutable input and initial status.
 	CreateJob(ctx context.Context, r *Job) error
@@ -97,23 +458,113 @@ type Interface interface {
 	// GetJob returns a Job by its ID.
 	GetJob(ctx context.Context, id string) (*Job, error)
 
-	// SetJobStatus updates the status of a Job by its ID.
-	SetJobStatus(ctx context.Context, jobID string, status Status) error
+	// ListJobs returns the jobs matching the filter, most recent first.
+	ListJobs(ctx context.Context, filter JobFilter) ([]Job, error)
+
+	// SetJobStatus atomically moves a Job to status if its current status allows it, and records the transition.
+	// Returns true if this call performed the transition, false if the Job is already in status or cannot move to it.
+	SetJobStatus(ctx context.Context, jobID string, status Status) (bool, error)
+
+	// SetJobResult stores the calculated value of a Job, and its breakdown and coverage if any, and moves it from
+	// calculating to notifying. It is a no-op if the Job is not calculating.
+	SetJobResult(ctx context.Context, jobID string, result float64, breakdown *models.ResultBreakdown, coverage *models.DataCoverage) error
+
+	// CompleteInlineJob stores the result of a Job calculated inline, and its breakdown if any, and moves it from
+	// created to completed in one step, recording the transitions through gathering, calculating and notifying at at.
+	// Returns false if the Job is no longer created, e.g. because it has been cancelled meanwhile.
+	CompleteInlineJob(ctx context.Context, jobID string, result float64, breakdown *models.ResultBreakdown, at time.Time) (bool, error)
+
+	// SetJobError stores the reason a Job failed and moves it to failed.
+	// It is a no-op if the Job has already reached a final status.
+	SetJobError(ctx context.Context, jobID string, errorInfo models.ErrorInfo, failure Failure) error
+
+	// CancelJob atomically marks a Job as cancelled if its result has not been calculated yet.
+	// The runs of a periodic report still in progress are cancelled with it.
+	// Returns true if this call performed the transition, false otherwise.
+	CancelJob(ctx context.Context, jobID string) (bool, error)
+
+	// ClaimDueSchedule leases a periodic report whose next run is due at now and is not leased already.
+	// Returns nil if there is none.
+	ClaimDueSchedule(ctx context.Context, now time.Time, lease time.Duration) (*Job, error)
+
+	// AdvanceSchedule records that run has been started and releases the lease. The periodic report
+	// moves to gathering, or to completed when ended is true. It is a no-op if the report is already final.
+	AdvanceSchedule(ctx context.Context, jobID string, run int, nextRunAt time.Time, ended bool) error
+
+	// ListStuckJobs returns up to limit jobs still in progress that were created, or last resumed, before the given time,
+	// periodic reports excluded.
+	ListStuckJobs(ctx context.Context, before time.Time, limit int) ([]Job, error)
+
+	// MarkJobResumed records that the missing events of a Job have been sent again at the given time.
+	MarkJobResumed(ctx context.Context, jobID string, at time.Time) error
+
+	// AcquireLease takes or renews the named lease for holder until now+duration, unless another holder has it
+	// and it has not expired yet. Returns true if holder has the lease.
+	AcquireLease(ctx context.Context, name, holder string, now time.Time, duration time.Duration) (bool, error)
+
+	// ReserveIdempotencyKey stores the key unless an unexpired record exists for the same subject and key.
+	// A record of the same request still in progress past its lease is taken over with the lease of key.
+	// Returns true if this call reserved the key, along with the record taken over if any; otherwise
+	// false and the stored record.
+	ReserveIdempotencyKey(ctx context.Context, key IdempotencyKey) (*IdempotencyKey, bool, error)
+
+	// SetIdempotencyKeyResponse stores the status and JSON body returned to the submission that reserved the key.
+	SetIdempotencyKeyResponse(ctx context.Context, subject, key string, status int, body []byte) error
+
+	// DeleteIdempotencyKey forgets a key, so that the submission can be retried with any request.
+	DeleteIdempotencyKey(ctx context.Context, subject, key string) error
+
+	// ReleaseIdempotencyKey ends the lease of a submission in progress, so that a retry of the same
+	// request takes it over at once.
+	ReleaseIdempotencyKey(ctx context.Context, subject, key string) error
+
+	// IncrementRequestCount adds one request to the counter of the subject for the window starting at
+	// window, creating it if needed, and returns the number of requests counted so far in the window.
+	IncrementRequestCount(ctx context.Context, subject string, window, expiresAt time.Time) (int, error)
+
+	// ReserveJobSlot reserves one of the limit slots of the subject for the job about to be created with
+	// jobID, unless all of them are held by jobs that have not reached a final status yet, periodic reports
+	// excluded, or reserved for jobs not created yet. Returns whether the slot has been reserved.
+	ReserveJobSlot(ctx context.Context, subject, jobID string, limit int, now time.Time) (bool, error)
+
+	// GetMeasurements returns the measurements stored under keys that have not expired at now, by key.
+	GetMeasurements(ctx context.Context, keys []string, now time.Time) (map[string]Measurement, error)
+
+	// SetMeasurements stores the measurements, replacing the ones stored under the same keys.
+	SetMeasurements(ctx context.Context, measurements []Measurement) error
+
+	// IsEventProcessed reports whether the event of the given ID and type has been recorded as processed
+	// and has not expired at now.
+	IsEventProcessed(ctx context.Context, eventID, eventType string, now time.Time) (bool, error)
+
+	// SetEventProcessed records the event as processed, replacing any previous record of it.
+	SetEventProcessed(ctx context.Context, processed ProcessedEvent) error
 
 	// CreateOrUpdateNetworkElementResult adds a network element result to a specific JobAppResult. If the JobAppResult does not exist, it creates a new one.
-	CreateOrUpdateNetworkElementResult(ctx context.Context, creationMetadata JobAppResultMetadata, neInstanceID string, neResult NetworkElementResult) error
+	CreateOrUpdateNetworkElementResult(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, neResult NetworkElementResult) error
 
 	// SetNetworkElementEnergy stores only the energy consumption for a network element without affecting traffic fields.
-	SetNetworkElementEnergy(ctx context.Context, creationMetadata JobAppResultMetadata, neInstanceID string, energyConsumption float64) error
+	SetNetworkElementEnergy(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, energyConsumption float64) error
 
 	// SetNetworkElementTraffic stores only the traffic volume fields for a network element without affecting energy field.
-	SetNetworkElementTraffic(ctx context.Context, creationMetadata JobAppResultMetadata, neInstanceID string, appInstanceTraffic, totalTraffic float64) error
+	SetNetworkElementTraffic(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, appInstanceTraffic, totalTraffic float64) error
+
+	// SetNetworkElementTrafficEstimate records the method estimating the traffic share of a network element missing
+	// from the Traffic Volume response, without affecting its energy field.
+	SetNetworkElementTrafficEstimate(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, method models.EstimatedNetworkElementMethod) error
 
 	// CreateOrUpdateApplicationResult adds the energy consumption result for the service of an application instance to a specific JobAppResult. If the JobAppResult does not exist, it creates a new one.
 	CreateOrUpdateApplicationResult(ctx context.Context, creationMetadata JobAppResultMetadata, appInstanceConsumption float64) error
 
-	// GetJobAppResult returns the JobAppResult for a specific AppID within a Job.
-	GetJobAppResult(ctx context.Context, jobID, appID string) (*JobAppResult, error)
+	// SetNetworkElementFailure marks a network element whose data could not be retrieved, with the reason, without affecting
+	// the values already stored for it.
+	SetNetworkElementFailure(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, reason string) error
+
+	// SetApplicationFailure marks an application instance whose energy consumption could not be retrieved, with the reason.
+	SetApplicationFailure(ctx context.Context, creationMetadata JobAppResultMetadata, reason string) error
+
+	// GetJobAppResult returns the JobAppResult for a specific AppID within a Job, over the given window of its time period.
+	GetJobAppResult(ctx context.Context, jobID, appID string, window int) (*JobAppResult, error)
 
 	// GetAllJobAppResults returns all JobAppResults for a specific JobID.
 	GetAllJobAppResults(ctx context.Context, jobID string) ([]JobAppResult, error)
@@ -122,7 +573,20 @@ type Interface interface {
 	// Returns true if this call performed the transition (caller may emit event), false if it was already set.
 	TrySetCalculationTriggered(ctx context.Context, jobID string) (bool, error)
 
-	// TrySetNotificationSent atomically sets notificationSent=true if it was not already true.
-	// Returns true if this call performed the transition (caller may send notification), false if it was already set.
-	TrySetNotificationSent(ctx context.Context, jobID string) (bool, error)
+	// ClaimNotificationDelivery atomically hands the delivery of the notification of a job over to owner, until
+	// now+lease, when no attempt holds it: it has never been attempted, its last attempt failed, or the lease of the
+	// attempt in progress has expired. Returns false when the notification is delivered or another attempt holds it.
+	ClaimNotificationDelivery(ctx context.Context, jobID, owner string, now time.Time, lease time.Duration) (bool, error)
+
+	// SetNotificationDelivering records that owner is sending the notification, extending its lease to now+lease.
+	// Returns false when owner no longer holds the delivery.
+	SetNotificationDelivering(ctx context.Context, jobID, owner string, now time.Time, lease time.Duration) (bool, error)
+
+	// SetNotificationDelivered finalizes the delivery and sets notificationSent, whichever attempt holds it: the sink
+	// has confirmed the notification.
+	SetNotificationDelivered(ctx context.Context, jobID string, now time.Time) error
+
+	// SetNotificationFailed records the failure of the attempt of owner, so that the delivery can be claimed again.
+	// It does nothing when owner no longer holds the delivery.
+	SetNotificationFailed(ctx context.Context, jobID, owner, reason string, now time.Time) error
 }
@@ -0,0 +1,136 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from   Status
+		to     Status
+		expect bool
+	}{
+		{from: StatusCreated, to: StatusGathering, expect: true},
+		{from: "", to: StatusGathering, expect: true},
+		{from: StatusGathering, to: StatusCalculating, expect: true},
+		{from: StatusCalculating, to: StatusNotifying, expect: true},
+		{from: StatusNotifying, to: StatusCompleted, expect: true},
+		{from: StatusGathering, to: StatusCompleted, expect: true},
+		{from: StatusCreated, to: StatusCalculating},
+		{from: StatusGathering, to: StatusNotifying},
+		{from: StatusGathering, to: StatusGathering},
+		{from: StatusCalculating, to: StatusFailed, expect: true},
+		{from: StatusNotifying, to: StatusFailed, expect: true},
+		{from: StatusCreated, to: StatusCancelled, expect: true},
+		{from: StatusNotifying, to: StatusCancelled},
+		{from: StatusCompleted, to: StatusFailed},
+		{from: StatusFailed, to: StatusCancelled},
+		{from: StatusCancelled, to: StatusGathering},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expect, CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestTimeWindows(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(60 * time.Hour)
+	created := start.Add(30 * time.Hour)
+
+	tests := []struct {
+		name       string
+		timePeriod *models.TimePeriod
+		size       time.Duration
+		expects    [][2]time.Time
+	}{
+		{
+			name:       "period split into whole days and a shorter last window",
+			timePeriod: &models.TimePeriod{StartDate: start, EndDate: &end},
+			size:       24 * time.Hour,
+			expects: [][2]time.Time{
+				{start, start.Add(24 * time.Hour)},
+				{start.Add(24 * time.Hour), start.Add(48 * time.Hour)},
+				{start.Add(48 * time.Hour), end},
+			},
+		},
+		{
+			name:       "period without end date ends at the job creation",
+			timePeriod: &models.TimePeriod{StartDate: start},
+			size:       24 * time.Hour,
+			expects: [][2]time.Time{
+				{start, start.Add(24 * time.Hour)},
+				{start.Add(24 * time.Hour), created},
+			},
+		},
+		{
+			name:       "period shorter than a window",
+			timePeriod: &models.TimePeriod{StartDate: start, EndDate: &end},
+			size:       7 * 24 * time.Hour,
+			expects:    [][2]time.Time{{start, end}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{JobSpec: JobSpec{TimePeriod: tt.timePeriod}, CreatedAt: created}
+			windows := job.TimeWindows(tt.size)
+			assert.Len(t, windows, len(tt.expects))
+			for i, w := range windows {
+				assert.Equal(t, tt.expects[i][0], w.StartDate)
+				assert.Equal(t, tt.expects[i][1], *w.EndDate)
+			}
+		})
+	}
+
+	t.Run("period kept whole when splitting is disabled or the period is unknown", func(t *testing.T) {
+		period := &models.TimePeriod{StartDate: start, EndDate: &end}
+		assert.Equal(t, []*models.TimePeriod{period}, (&Job{JobSpec: JobSpec{TimePeriod: period}}).TimeWindows(0))
+		assert.Equal(t, []*models.TimePeriod{nil}, (&Job{}).TimeWindows(24*time.Hour))
+	})
+}
+
+func TestNetworkElementKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     NetworkElementID
+		expect string
+	}{
+		{name: "NE without vendor is keyed by its ID", id: NetworkElementID{NEInstanceID: "ne1"}, expect: "ne1"},
+		{name: "NE is keyed by vendor and ID", id: NetworkElementID{VendorID: "vendorA", NEInstanceID: "ne1"}, expect: "vendorA/ne1"},
+		{name: "separator and field name characters are escaped", id: NetworkElementID{VendorID: "a/b", NEInstanceID: "$ne.1%"}, expect: "a%2Fb/%24ne%2E1%25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expect, tt.id.Key())
+		})
+	}
+
+	t.Run("two vendors sharing an NE ID have distinct keys", func(t *testing.T) {
+		a := NetworkElementID{VendorID: "vendorA", NEInstanceID: "ne1"}
+		b := NetworkElementID{VendorID: "vendorB", NEInstanceID: "ne1"}
+		assert.NotEqual(t, a.Key(), b.Key())
+	})
+}
@@ -17,20 +17,38 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
 	servererr "github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/server/error"
 )
 
 var _ Interface = &mongoDB{}
 
+// finalStatuses lists the job statuses after which a job can no longer change.
+var finalStatuses = bson.A{StatusCompleted, StatusFailed, StatusCancelled}
+
+// jobSlotGrace is how long the slot reserved for a job is held before the job is created. Past it, the slot
+// of a job that was never created is released by the next rebuild.
+const jobSlotGrace = time.Minute
+
 type mongoDB struct {
-	jobs    *mongo.Collection
-	jobApps *mongo.Collection
+	jobs            *mongo.Collection
+	jobApps         *mongo.Collection
+	idempotencyKeys *mongo.Collection
+	requestCounts   *mongo.Collection
+	jobSlots        *mongo.Collection
+	leases          *mongo.Collection
+	measurements    *mongo.Collection
+	processedEvents *mongo.Collection
 }
 
 // NewMongoDB creates a new MongoDB connection using the provided URI and database name.
@@ -42,13 +60,23 @@ func NewMongoDB(conf config.Database) (Interface, error) {
 	}
 	jobsColl := client.Database(conf.Name).Collection("jobs")
 	jobAppsColl := client.Database(conf.Name).Collection("jobAppResults")
-
-	// Create unique compound index on (jobId, appId) to prevent race condition duplicates
+	idempotencyKeysColl := client.Database(conf.Name).Collection("idempotencyKeys")
+	requestCountsColl := client.Database(conf.Name).Collection("requestCounts")
+	jobSlotsColl := client.Database(conf.Name).Collection("jobSlots")
+	leasesColl := client.Database(conf.Name).Collection("leases")
+	measurementsColl := client.Database(conf.Name).Collection("measurements")
+	processedEventsColl := client.Database(conf.Name).Collection("processedEvents")
+
+	// Create unique compound index on (jobId, appId, window) to prevent race condition duplicates
 	// Use background context with timeout to avoid blocking startup
 	ctx := context.Background()
+	// The index used to be on (jobId, appId) only, before the time period was split into windows.
+	if err = jobAppsColl.Indexes().DropOne(ctx, "jobId_appId_unique"); err != nil && !isNotFound(err) {
+		return nil, err
+	}
 	indexModel := mongo.IndexModel{
-		Keys:    bson.D{{Key: "jobId", Value: 1}, {Key: "appId", Value: 1}},
-		Options: options.Index().SetUnique(true).SetName("jobId_appId_unique"),
+		Keys:    bson.D{{Key: "jobId", Value: 1}, {Key: "appId", Value: 1}, {Key: "window", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("jobId_appId_window_unique"),
 	}
 	// Try to create index, ignore error if index already exists
 	_, err = jobAppsColl.Indexes().CreateOne(ctx, indexModel)
@@ -59,22 +87,396 @@ func NewMongoDB(conf config.Database) (Interface, error) {
 		return nil, err
 	}
 
-	return &mongoDB{jobs: jobsColl, jobApps: jobAppsColl}, nil
+	// Indexes backing ListJobs: the listing is always scoped by subject and ordered by
+	// creation time, optionally narrowed by status.
+	jobIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "subject", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("subject_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "subject", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("subject_status_createdAt"),
+		},
+		// Periodic reports waiting for their next run, and the runs of each of them.
+		{
+			Keys:    bson.D{{Key: "schedule.nextRunAt", Value: 1}},
+			Options: options.Index().SetSparse(true).SetName("schedule_nextRunAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "parentId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetSparse(true).SetName("parentId_createdAt"),
+		},
+		// Jobs still in progress, swept by the watchdog.
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("status_createdAt"),
+		},
+	}
+	if _, err = jobsColl.Indexes().CreateMany(ctx, jobIndexes); err != nil {
+		return nil, err
+	}
+
+	// Idempotency keys are unique per subject and removed by MongoDB once expired.
+	idempotencyIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "subject", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("subject_key_unique"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0).SetName("expiresAt_ttl"),
+		},
+	}
+	if _, err = idempotencyKeysColl.Indexes().CreateMany(ctx, idempotencyIndexes); err != nil {
+		return nil, err
+	}
+
+	// Rate limit counters are unique per subject and window, and removed once the window has elapsed.
+	requestCountIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "subject", Value: 1}, {Key: "window", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("subject_window_unique"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0).SetName("expiresAt_ttl"),
+		},
+	}
+	if _, err = requestCountsColl.Indexes().CreateMany(ctx, requestCountIndexes); err != nil {
+		return nil, err
+	}
+
+	// Measurements shared between jobs are removed by MongoDB once expired.
+	measurementIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0).SetName("expiresAt_ttl"),
+	}
+	if _, err = measurementsColl.Indexes().CreateOne(ctx, measurementIndex); err != nil {
+		return nil, err
+	}
+
+	// An event is recorded once per ID and type, until MongoDB removes the record once expired.
+	processedEventIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "eventId", Value: 1}, {Key: "eventType", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("eventId_eventType_unique"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0).SetName("expiresAt_ttl"),
+		},
+	}
+	if _, err = processedEventsColl.Indexes().CreateMany(ctx, processedEventIndexes); err != nil {
+		return nil, err
+	}
+
+	return &mongoDB{
+		jobs:            jobsColl,
+		jobApps:         jobAppsColl,
+		idempotencyKeys: idempotencyKeysColl,
+		requestCounts:   requestCountsColl,
+		jobSlots:        jobSlotsColl,
+		leases:          leasesColl,
+		measurements:    measurementsColl,
+		processedEvents: processedEventsColl,
+	}, nil
 }
 
 func (m *mongoDB) CreateJob(ctx context.Context, r *Job) error {
 	_, err := m.jobs.InsertOne(ctx, r)
+	if mongo.IsDuplicateKeyError(err) {
+		return servererr.NewAlreadyExists("job with id '" + *r.RequestId + "'")
+	}
+	return err
+}
+
+// ReserveIdempotencyKey inserts the key if no record exists for the subject and key. A record that has
+// expired but has not been removed by the TTL monitor yet is replaced, and a record of the same request
+// left in progress past its lease is taken over.
+func (m *mongoDB) ReserveIdempotencyKey(ctx context.Context, key IdempotencyKey) (*IdempotencyKey, bool, error) {
+	filter := bson.M{"subject": key.Subject, "key": key.Key}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+	var stored IdempotencyKey
+	err := m.idempotencyKeys.FindOneAndUpdate(ctx, filter, bson.M{"$setOnInsert": key}, opts).Decode(&stored)
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return nil, true, nil
+	case mongo.IsDuplicateKeyError(err):
+		// A concurrent submission inserted the key first.
+		if err = m.idempotencyKeys.FindOne(ctx, filter).Decode(&stored); err != nil {
+			return nil, false, err
+		}
+		return &stored, false, nil
+	case err != nil:
+		return nil, false, err
+	}
+
+	now := time.Now()
+	if stored.ExpiresAt.After(now) {
+		if stored.ResponseStatus != 0 || stored.Fingerprint != key.Fingerprint || stored.LeaseUntil.After(now) {
+			return &stored, false, nil
+		}
+		// The submission holding the key was interrupted before answering: its job, if created, is resumed
+		// under the same request ID.
+		var lease any = stored.LeaseUntil
+		if stored.LeaseUntil.IsZero() {
+			// A released lease, or none for the records reserved before leases were introduced.
+			lease = bson.M{"$in": bson.A{time.Time{}, nil}}
+		}
+		takeover := bson.M{"subject": key.Subject, "key": key.Key, "responseBody": bson.M{"$exists": false}, "leaseUntil": lease}
+		res, err := m.idempotencyKeys.UpdateOne(ctx, takeover, bson.M{"$set": bson.M{"leaseUntil": key.LeaseUntil}})
+		if err != nil {
+			return nil, false, err
+		}
+		if res.MatchedCount == 1 {
+			stored.LeaseUntil = key.LeaseUntil
+			return &stored, true, nil
+		}
+	} else {
+		res, err := m.idempotencyKeys.ReplaceOne(ctx, bson.M{"subject": key.Subject, "key": key.Key, "expiresAt": stored.ExpiresAt}, key)
+		if err != nil {
+			return nil, false, err
+		}
+		if res.MatchedCount == 1 {
+			return nil, true, nil
+		}
+	}
+	// Another submission replaced or took over the record first.
+	if err = m.idempotencyKeys.FindOne(ctx, filter).Decode(&stored); err != nil {
+		return nil, false, err
+	}
+	return &stored, false, nil
+}
+
+func (m *mongoDB) IncrementRequestCount(ctx context.Context, subject string, window, expiresAt time.Time) (int, error) {
+	filter := bson.M{"subject": subject, "window": window}
+	update := bson.M{
+		"$inc":         bson.M{"count": 1},
+		"$setOnInsert": bson.M{"expiresAt": expiresAt},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var counter RequestCounter
+	err := m.requestCounts.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent request created the counter first: the update now matches it.
+		err = m.requestCounts.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return counter.Count, nil
+}
+
+// ReserveJobSlot adds the slot of the job to the slots of the subject only while fewer than limit are held,
+// which a single update checks and applies atomically. Slots are not released when their job ends: once
+// the limit is reached, the slots are rebuilt from the jobs in progress and the reservation is tried again.
+func (m *mongoDB) ReserveJobSlot(ctx context.Context, subject, jobID string, limit int, now time.Time) (bool, error) {
+	reserved, err := m.pushJobSlot(ctx, subject, jobID, limit, now)
+	if err != nil || reserved {
+		return reserved, err
+	}
+	if err = m.rebuildJobSlots(ctx, subject, now); err != nil {
+		return false, err
+	}
+	return m.pushJobSlot(ctx, subject, jobID, limit, now)
+}
+
+func (m *mongoDB) pushJobSlot(ctx context.Context, subject, jobID string, limit int, now time.Time) (bool, error) {
+	filter := bson.M{"_id": subject, fmt.Sprintf("jobs.%d", limit-1): bson.M{"$exists": false}}
+	update := bson.M{
+		"$push": bson.M{"jobs": JobSlot{JobID: jobID, ReservedAt: now}},
+		"$inc":  bson.M{"version": 1},
+	}
+	_, err := m.jobSlots.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// The slots of the subject exist, and all of them are held.
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// rebuildJobSlots replaces the slots of the subject with the ones of its jobs in progress, runs of periodic
+// reports included, and of the reservations whose job has not been created yet within jobSlotGrace.
+func (m *mongoDB) rebuildJobSlots(ctx context.Context, subject string, now time.Time) error {
+	var slots JobSlots
+	if err := m.jobSlots.FindOne(ctx, bson.M{"_id": subject}).Decode(&slots); err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"subject":  subject,
+		"status":   bson.M{"$nin": finalStatuses},
+		"schedule": bson.M{"$exists": false},
+	}
+	cursor, err := m.jobs.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1, "createdAt": 1}))
+	if err != nil {
+		return err
+	}
+	var active []Job
+	if err = cursor.All(ctx, &active); err != nil {
+		return err
+	}
+	jobs := make([]JobSlot, 0, len(active))
+	for _, job := range active {
+		jobs = append(jobs, JobSlot{JobID: *job.RequestId, ReservedAt: job.CreatedAt})
+	}
+
+	var recent []JobSlot
+	pending := bson.A{}
+	for _, slot := range slots.Jobs {
+		if slot.ReservedAt.After(now.Add(-jobSlotGrace)) {
+			recent = append(recent, slot)
+			pending = append(pending, slot.JobID)
+		}
+	}
+	if len(recent) > 0 {
+		// A recent reservation keeps its slot until its job is created, then holds it as a job in progress.
+		var created []string
+		if err = m.jobs.Distinct(ctx, "_id", bson.M{"_id": bson.M{"$in": pending}}).Decode(&created); err != nil {
+			return err
+		}
+		for _, slot := range recent {
+			if !slices.Contains(created, slot.JobID) {
+				jobs = append(jobs, slot)
+			}
+		}
+	}
+
+	update := bson.M{"$set": bson.M{"jobs": jobs}, "$inc": bson.M{"version": 1}}
+	// A reservation made since the slots were read is not overwritten: it is left to the next rebuild.
+	_, err = m.jobSlots.UpdateOne(ctx, bson.M{"_id": subject, "version": slots.Version}, update)
+	return err
+}
+
+// GetMeasurements filters on the expiry as well, the TTL monitor removing the expired measurements only
+// once a minute.
+func (m *mongoDB) GetMeasurements(ctx context.Context, keys []string, now time.Time) (map[string]Measurement, error) {
+	cursor, err := m.measurements.Find(ctx, bson.M{"_id": bson.M{"$in": keys}, "expiresAt": bson.M{"$gt": now}})
+	if err != nil {
+		return nil, err
+	}
+	var measurements []Measurement
+	if err = cursor.All(ctx, &measurements); err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]Measurement, len(measurements))
+	for _, measurement := range measurements {
+		byKey[measurement.Key] = measurement
+	}
+	return byKey, nil
+}
+
+func (m *mongoDB) SetMeasurements(ctx context.Context, measurements []Measurement) error {
+	if len(measurements) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, 0, len(measurements))
+	for _, measurement := range measurements {
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": measurement.Key}).
+			SetReplacement(measurement).
+			SetUpsert(true))
+	}
+	_, err := m.measurements.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// IsEventProcessed filters on the expiry as well, the TTL monitor removing the expired records only
+// once a minute.
+func (m *mongoDB) IsEventProcessed(ctx context.Context, eventID, eventType string, now time.Time) (bool, error) {
+	filter := bson.M{"eventId": eventID, "eventType": eventType, "expiresAt": bson.M{"$gt": now}}
+	count, err := m.processedEvents.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+func (m *mongoDB) SetEventProcessed(ctx context.Context, processed ProcessedEvent) error {
+	filter := bson.M{"eventId": processed.EventID, "eventType": processed.EventType}
+	_, err := m.processedEvents.ReplaceOne(ctx, filter, processed, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent delivery of the same event recorded it first.
+		return nil
+	}
+	return err
+}
+
+func (m *mongoDB) SetIdempotencyKeyResponse(ctx context.Context, subject, key string, status int, body []byte) error {
+	update := bson.M{"$set": bson.M{"responseStatus": status, "responseBody": body}}
+	_, err := m.idempotencyKeys.UpdateOne(ctx, bson.M{"subject": subject, "key": key}, update)
+	return err
+}
+
+func (m *mongoDB) DeleteIdempotencyKey(ctx context.Context, subject, key string) error {
+	_, err := m.idempotencyKeys.DeleteOne(ctx, bson.M{"subject": subject, "key": key})
+	return err
+}
+
+func (m *mongoDB) ReleaseIdempotencyKey(ctx context.Context, subject, key string) error {
+	filter := bson.M{"subject": subject, "key": key, "responseBody": bson.M{"$exists": false}}
+	_, err := m.idempotencyKeys.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"leaseUntil": time.Time{}}})
 	return err
 }
 
 func (m *mongoDB) GetJob(ctx context.Context, id string) (*Job, error) {
 	var job Job
 	if err := m.jobs.FindOne(ctx, bson.M{"_id": id}).Decode(&job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, servererr.NewNotFound("job with id '" + id + "'")
+		}
 		return nil, err
 	}
 	return &job, nil
 }
 
+// ListJobs returns the jobs of a subject matching the filter, ordered by creation time and ID, most recent first.
+func (m *mongoDB) ListJobs(ctx context.Context, filter JobFilter) ([]Job, error) {
+	query := bson.M{"subject": filter.Subject}
+	if filter.Status != nil {
+		query["status"] = *filter.Status
+	}
+	if filter.RequestKind != nil {
+		query["requestKind"] = *filter.RequestKind
+	}
+	if filter.AppInstanceID != nil {
+		query["service"] = *filter.AppInstanceID
+	}
+	if filter.ParentID != "" {
+		query["parentId"] = filter.ParentID
+	}
+	createdAt := bson.M{}
+	if filter.CreatedFrom != nil {
+		createdAt["$gte"] = *filter.CreatedFrom
+	}
+	if filter.CreatedTo != nil {
+		createdAt["$lt"] = *filter.CreatedTo
+	}
+	if len(createdAt) > 0 {
+		query["createdAt"] = createdAt
+	}
+	if filter.After != nil {
+		query["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$lt": filter.After.CreatedAt}},
+			bson.M{"createdAt": filter.After.CreatedAt, "_id": bson.M{"$lt": filter.After.ID}},
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+	cursor, err := m.jobs.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []Job{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
 // TrySetCalculationTriggered performs an atomic set of calculationTriggered=true if not yet true.
 // It returns true only if this invocation actually set the flag (i.e., caller is first and may emit the event).
 func (m *mongoDB) TrySetCalculationTriggered(ctx context.Context, jobID string) (bool, error) {
@@ -87,11 +489,27 @@ func (m *mongoDB) TrySetCalculationTriggered(ctx context.Context, jobID string)
 	return res.MatchedCount == 1, nil
 }
 
-// TrySetNotificationSent performs an atomic set of notificationSent=true if not yet true.
-// It returns true only if this invocation actually set the flag (i.e., caller is first and may send notification).
-func (m *mongoDB) TrySetNotificationSent(ctx context.Context, jobID string) (bool, error) {
-	filter := bson.M{"_id": jobID, "notificationSent": bson.M{"$ne": true}}
-	update := bson.M{"$set": bson.M{"notificationSent": true}}
+// ClaimNotificationDelivery matches the job only when its delivery is free, so that a single attempt
+// at a time can claim it, and counts the attempt.
+func (m *mongoDB) ClaimNotificationDelivery(ctx context.Context, jobID, owner string, now time.Time, lease time.Duration) (bool, error) {
+	filter := bson.M{
+		"_id":              jobID,
+		"notificationSent": bson.M{"$ne": true},
+		"$or": bson.A{
+			bson.M{"delivery": bson.M{"$exists": false}},
+			bson.M{"delivery.state": DeliveryFailed},
+			bson.M{"delivery.leaseUntil": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"delivery.state":      DeliveryClaimed,
+			"delivery.owner":      owner,
+			"delivery.leaseUntil": now.Add(lease),
+			"delivery.updatedAt":  now,
+		},
+		"$inc": bson.M{"delivery.attempts": 1},
+	}
 	res, err := m.jobs.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return false, err
@@ -99,84 +517,250 @@ func (m *mongoDB) TrySetNotificationSent(ctx context.Context, jobID string) (boo
 	return res.MatchedCount == 1, nil
 }
 
-func (m *mongoDB) SetJobStatus(ctx context.Context, jobID string, status Status) error {
-	res, err := m.jobs.UpdateOne(ctx, bson.M{"_id": jobID}, bson.M{"$set": bson.M{"State": status}})
-	if res.MatchedCount == 0 {
-		return servererr.NewNotFound("job with id '" + jobID + "'")
+func (m *mongoDB) SetNotificationDelivering(ctx context.Context, jobID, owner string, now time.Time, lease time.Duration) (bool, error) {
+	filter := bson.M{"_id": jobID, "delivery.owner": owner, "delivery.state": DeliveryClaimed}
+	update := bson.M{"$set": bson.M{
+		"delivery.state":      DeliveryDelivering,
+		"delivery.leaseUntil": now.Add(lease),
+		"delivery.updatedAt":  now,
+	}}
+	res, err := m.jobs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
 	}
+	return res.MatchedCount == 1, nil
+}
+
+func (m *mongoDB) SetNotificationDelivered(ctx context.Context, jobID string, now time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"notificationSent":     true,
+		"delivery.state":       DeliveryDelivered,
+		"delivery.updatedAt":   now,
+		"delivery.deliveredAt": now,
+	}}
+	_, err := m.jobs.UpdateOne(ctx, bson.M{"_id": jobID}, update)
 	return err
 }
 
-// app.Consumption -> result.AppInstanceEnergyConsumption = 0.0.
-// ne.COnsumption x ne -> result.networkElement.Add()
+func (m *mongoDB) SetNotificationFailed(ctx context.Context, jobID, owner, reason string, now time.Time) error {
+	filter := bson.M{"_id": jobID, "delivery.owner": owner, "delivery.state": bson.M{"$in": bson.A{DeliveryClaimed, DeliveryDelivering}}}
+	update := bson.M{"$set": bson.M{
+		"delivery.state":     DeliveryFailed,
+		"delivery.lastError": reason,
+		"delivery.updatedAt": now,
+	}}
+	_, err := m.jobs.UpdateOne(ctx, filter, update)
+	return err
+}
 
-func (m *mongoDB) CreateOrUpdateApplicationResult(ctx context.Context, creationMetadata JobAppResultMetadata, appInstanceConsumption float64) error {
-	filter := bson.M{
-		"jobId": creationMetadata.JobID,
-		"appId": creationMetadata.AppID,
-	}
+func (m *mongoDB) SetJobStatus(ctx context.Context, jobID string, status Status) (bool, error) {
+	return m.transition(ctx, jobID, status, nil)
+}
 
-	// Use dotted notation to update only the appInstanceEnergyConsumption field
-	// without replacing the entire result object
-	appConsumptionPath := "result.appInstanceEnergyConsumption"
+// SetJobResult stores the result and moves the job from calculating to notifying.
+func (m *mongoDB) SetJobResult(ctx context.Context, jobID string, result float64, breakdown *models.ResultBreakdown, coverage *models.DataCoverage) error {
+	set := bson.M{"result": result}
+	if breakdown != nil {
+		set["breakdown"] = breakdown
+	}
+	if coverage != nil {
+		set["coverage"] = coverage
+	}
+	_, err := m.transition(ctx, jobID, StatusNotifying, set)
+	return err
+}
 
+// CompleteInlineJob stores the result of a created job and completes it, recording every transition of its lifecycle.
+func (m *mongoDB) CompleteInlineJob(ctx context.Context, jobID string, result float64, breakdown *models.ResultBreakdown, at time.Time) (bool, error) {
+	set := bson.M{"result": result, "status": StatusCompleted, "updatedAt": at, "completedAt": at}
+	if breakdown != nil {
+		set["breakdown"] = breakdown
+	}
+	transitions := bson.A{}
+	for _, status := range []Status{StatusGathering, StatusCalculating, StatusNotifying, StatusCompleted} {
+		transitions = append(transitions, Transition{Status: status, At: at})
+	}
+	filter := bson.M{"_id": jobID, "status": bson.M{"$in": bson.A{StatusCreated, nil}}}
 	update := bson.M{
-		// things that should only be set on insert
-		"$setOnInsert": bson.M{
-			"jobId":            creationMetadata.JobID,
-			"appId":            creationMetadata.AppID,
-			"numberOfTotalNEs": creationMetadata.NumberOfTotalNEs,
-		},
-		// things we want to update every time
-		"$set": bson.M{
-			appConsumptionPath: appInstanceConsumption,
-		},
+		"$set":  set,
+		"$push": bson.M{"transitions": bson.M{"$each": transitions}},
 	}
+	res, err := m.jobs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
 
-	opts := options.UpdateOne().SetUpsert(true)
-	_, err := m.jobApps.UpdateOne(ctx, filter, update, opts)
+// SetJobError stores the failure reason and moves the job to failed, unless the job is already final.
+func (m *mongoDB) SetJobError(ctx context.Context, jobID string, errorInfo models.ErrorInfo, failure Failure) error {
+	_, err := m.transition(ctx, jobID, StatusFailed, bson.M{"error": errorInfo, "failure": failure})
 	return err
 }
 
-func (m *mongoDB) CreateOrUpdateNetworkElementResult(ctx context.Context, creationMetadata JobAppResultMetadata, neInstanceID string, neResult NetworkElementResult) error {
-	filter := bson.M{
-		"jobId": creationMetadata.JobID,
-		"appId": creationMetadata.AppID,
+// CancelJob moves the job to cancelled, unless its result has been calculated or it is already final,
+// along with its runs still in progress.
+func (m *mongoDB) CancelJob(ctx context.Context, jobID string) (bool, error) {
+	cancelled, err := m.transition(ctx, jobID, StatusCancelled, nil)
+	if err != nil || !cancelled {
+		return false, err
+	}
+	filter, update := transitionUpdate(bson.M{"parentId": jobID}, StatusCancelled, nil)
+	if _, err = m.jobs.UpdateMany(ctx, filter, update); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// transition moves the job to status if its current status allows it, setting the fields of set along with it.
+func (m *mongoDB) transition(ctx context.Context, jobID string, status Status, set bson.M) (bool, error) {
+	filter, update := transitionUpdate(bson.M{"_id": jobID}, status, set)
+	res, err := m.jobs.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
 	}
+	return res.ModifiedCount == 1, nil
+}
 
-	// build the dotted path for this NE
-	nePath := "result.networkElements." + neInstanceID
+// transitionUpdate restricts filter to the jobs that can move to status, and returns the update
+// performing the move and recording it.
+func transitionUpdate(filter bson.M, status Status, set bson.M) (bson.M, bson.M) {
+	from := bson.A{}
+	for _, s := range statusPredecessors[status] {
+		from = append(from, s)
+		if s == StatusCreated {
+			// Jobs stored without a status have not been picked up yet.
+			from = append(from, nil)
+		}
+	}
+	filter["status"] = bson.M{"$in": from}
 
+	now := time.Now().UTC()
+	if set == nil {
+		set = bson.M{}
+	}
+	set["status"] = status
+	set["updatedAt"] = now
+	if status.IsFinal() {
+		set["completedAt"] = now
+	}
 	update := bson.M{
-		// things that should only be set on insert
-		"$setOnInsert": bson.M{
-			"jobId":            creationMetadata.JobID,
-			"appId":            creationMetadata.AppID,
-			"numberOfTotalNEs": creationMetadata.NumberOfTotalNEs,
-		},
-		// things we want to update every time
-		"$set": bson.M{
-			nePath: bson.M{
-				"energyConsumption":  neResult.EnergyConsumption,
-				"appInstanceTraffic": neResult.AppInstanceTraffic,
-				"totalTraffic":       neResult.TotalTraffic,
-			},
+		"$set":  set,
+		"$push": bson.M{"transitions": Transition{Status: status, At: now}},
+	}
+	return filter, update
+}
+
+// ListStuckJobs returns the oldest jobs in progress whose deadline, counted from their creation or
+// from their last resume, has passed.
+func (m *mongoDB) ListStuckJobs(ctx context.Context, before time.Time, limit int) ([]Job, error) {
+	filter := bson.M{
+		"status":   bson.M{"$nin": finalStatuses},
+		"schedule": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"resumedAt": bson.M{"$lt": before}},
+			bson.M{"resumedAt": bson.M{"$exists": false}, "createdAt": bson.M{"$lt": before}},
 		},
 	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := m.jobs.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	jobs := []Job{}
+	if err = cursor.All(ctx, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
 
-	opts := options.UpdateOne().SetUpsert(true)
-	_, err := m.jobApps.UpdateOne(ctx, filter, update, opts)
+func (m *mongoDB) MarkJobResumed(ctx context.Context, jobID string, at time.Time) error {
+	update := bson.M{
+		"$inc": bson.M{"resumes": 1},
+		"$set": bson.M{"resumedAt": at},
+	}
+	_, err := m.jobs.UpdateOne(ctx, bson.M{"_id": jobID}, update)
 	return err
 }
 
-func (m *mongoDB) SetNetworkElementEnergy(ctx context.Context, creationMetadata JobAppResultMetadata, neInstanceID string, energyConsumption float64) error {
+// AcquireLease upserts the lease document when it is free, expired or already held by holder. When
+// another holder has a valid lease, the upsert collides with the existing document on its ID.
+func (m *mongoDB) AcquireLease(ctx context.Context, name, holder string, now time.Time, duration time.Duration) (bool, error) {
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": holder},
+			bson.M{"until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"holder": holder, "until": now.Add(duration)}}
+	_, err := m.leases.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ClaimDueSchedule leases the periodic report with the oldest due run, skipping the ones
+// leased by another scheduler instance. An expired lease can be claimed again.
+func (m *mongoDB) ClaimDueSchedule(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
 	filter := bson.M{
-		"jobId": creationMetadata.JobID,
-		"appId": creationMetadata.AppID,
+		"schedule.nextRunAt": bson.M{"$lte": now},
+		"status":             bson.M{"$nin": finalStatuses},
+		"$or": bson.A{
+			bson.M{"schedule.leaseUntil": bson.M{"$exists": false}},
+			bson.M{"schedule.leaseUntil": bson.M{"$lt": now}},
+		},
 	}
+	update := bson.M{"$set": bson.M{"schedule.leaseUntil": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "schedule.nextRunAt", Value: 1}}).
+		SetReturnDocument(options.After)
+	var job Job
+	if err := m.jobs.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &job, nil
+}
 
-	// build the dotted path for the energy field only
-	energyPath := "result.networkElements." + neInstanceID + ".energyConsumption"
+// AdvanceSchedule stores the progress of a periodic report and releases its lease, unless the report is already final.
+// The report moves to gathering with its first run.
+func (m *mongoDB) AdvanceSchedule(ctx context.Context, jobID string, run int, nextRunAt time.Time, ended bool) error {
+	filter := bson.M{"_id": jobID, "status": bson.M{"$nin": finalStatuses}}
+	set := bson.M{
+		"schedule.runs":      run,
+		"schedule.nextRunAt": nextRunAt,
+	}
+	update := bson.M{"$set": set, "$unset": bson.M{"schedule.leaseUntil": ""}}
+	if _, err := m.jobs.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+	if _, err := m.transition(ctx, jobID, StatusGathering, nil); err != nil {
+		return err
+	}
+	if ended {
+		_, err := m.transition(ctx, jobID, StatusCompleted, nil)
+		return err
+	}
+	return nil
+}
+
+// app.Consumption -> result.AppInstanceEnergyConsumption = 0.0.
+// ne.COnsumption x ne -> result.networkElement.Add()
+
+func (m *mongoDB) CreateOrUpdateApplicationResult(ctx context.Context, creationMetadata JobAppResultMetadata, appInstanceConsumption float64) error {
+	filter := bson.M{
+		"jobId":  creationMetadata.JobID,
+		"appId":  creationMetadata.AppID,
+		"window": creationMetadata.Window,
+	}
+
+	// Use dotted notation to update only the appInstanceEnergyConsumption field
+	// without replacing the entire result object
+	appConsumptionPath := "result.appInstanceEnergyConsumption"
 
 	update := bson.M{
 		// things that should only be set on insert
@@ -184,10 +768,11 @@ func (m *mongoDB) SetNetworkElementEnergy(ctx context.Context, creationMetadata
 			"jobId":            creationMetadata.JobID,
 			"appId":            creationMetadata.AppID,
 			"numberOfTotalNEs": creationMetadata.NumberOfTotalNEs,
+			"numberOfWindows":  creationMetadata.NumberOfWindows,
 		},
-		// only update the energy field
+		// things we want to update every time
 		"$set": bson.M{
-			energyPath: energyConsumption,
+			appConsumptionPath: appInstanceConsumption,
 		},
 	}
 
@@ -196,28 +781,68 @@ func (m *mongoDB) SetNetworkElementEnergy(ctx context.Context, creationMetadata
 	return err
 }
 
-func (m *mongoDB) SetNetworkElementTraffic(ctx context.Context, creationMetadata JobAppResultMetadata, neInstanceID string, appInstanceTraffic, totalTraffic float64) error {
-	filter := bson.M{
-		"jobId": creationMetadata.JobID,
-		"appId": creationMetadata.AppID,
-	}
+func (m *mongoDB) CreateOrUpdateNetworkElementResult(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, neResult NetworkElementResult) error {
+	return m.setJobAppResultFields(ctx, creationMetadata, networkElementFields(ne, bson.M{
+		"energyConsumption":  neResult.EnergyConsumption,
+		"appInstanceTraffic": neResult.AppInstanceTraffic,
+		"totalTraffic":       neResult.TotalTraffic,
+	}))
+}
 
-	// build the dotted paths for traffic volume fields only
-	appTrafficPath := "result.networkElements." + neInstanceID + ".appInstanceTraffic"
-	totalTrafficPath := "result.networkElements." + neInstanceID + ".totalTraffic"
+// SetNetworkElementEnergy only sets the energy field, leaving the traffic volume fields untouched.
+func (m *mongoDB) SetNetworkElementEnergy(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, energyConsumption float64) error {
+	return m.setJobAppResultFields(ctx, creationMetadata, networkElementFields(ne, bson.M{"energyConsumption": energyConsumption}))
+}
+
+// SetNetworkElementTraffic only sets the traffic volume fields, leaving the energy field untouched.
+func (m *mongoDB) SetNetworkElementTraffic(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, appInstanceTraffic, totalTraffic float64) error {
+	return m.setJobAppResultFields(ctx, creationMetadata, networkElementFields(ne, bson.M{
+		"appInstanceTraffic": appInstanceTraffic,
+		"totalTraffic":       totalTraffic,
+	}))
+}
 
+func (m *mongoDB) SetNetworkElementFailure(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, reason string) error {
+	return m.setJobAppResultFields(ctx, creationMetadata, networkElementFields(ne, bson.M{"failure": reason}))
+}
+
+func (m *mongoDB) SetNetworkElementTrafficEstimate(ctx context.Context, creationMetadata JobAppResultMetadata, ne NetworkElementID, method models.EstimatedNetworkElementMethod) error {
+	return m.setJobAppResultFields(ctx, creationMetadata, networkElementFields(ne, bson.M{"trafficEstimate": method}))
+}
+
+func (m *mongoDB) SetApplicationFailure(ctx context.Context, creationMetadata JobAppResultMetadata, reason string) error {
+	return m.setJobAppResultFields(ctx, creationMetadata, bson.M{"result.appInstanceFailure": reason})
+}
+
+// networkElementFields returns the dotted paths setting values on the result of a network element, along
+// with its ID, so that only the given fields are affected.
+func networkElementFields(ne NetworkElementID, values bson.M) bson.M {
+	path := "result.networkElements." + ne.Key()
+	fields := bson.M{path + ".neInstanceId": ne.NEInstanceID}
+	if ne.VendorID != "" {
+		fields[path+".vendorId"] = ne.VendorID
+	}
+	for name, value := range values {
+		fields[path+"."+name] = value
+	}
+	return fields
+}
+
+// setJobAppResultFields sets fields of a JobAppResult, creating the JobAppResult if it does not exist.
+func (m *mongoDB) setJobAppResultFields(ctx context.Context, creationMetadata JobAppResultMetadata, fields bson.M) error {
+	filter := bson.M{
+		"jobId":  creationMetadata.JobID,
+		"appId":  creationMetadata.AppID,
+		"window": creationMetadata.Window,
+	}
 	update := bson.M{
-		// things that should only be set on insert
 		"$setOnInsert": bson.M{
 			"jobId":            creationMetadata.JobID,
 			"appId":            creationMetadata.AppID,
 			"numberOfTotalNEs": creationMetadata.NumberOfTotalNEs,
+			"numberOfWindows":  creationMetadata.NumberOfWindows,
 		},
-		// only update the traffic volume fields
-		"$set": bson.M{
-			appTrafficPath:   appInstanceTraffic,
-			totalTrafficPath: totalTraffic,
-		},
+		"$set": fields,
 	}
 
 	opts := options.UpdateOne().SetUpsert(true)
@@ -247,11 +872,17 @@ func (m *mongoDB) GetAllJobAppResults(ctx context.Context, jobID string) ([]JobA
 	return results, nil
 }
 
-func (m *mongoDB) GetJobAppResult(ctx context.Context, jobID, appID string) (*JobAppResult, error) {
+func (m *mongoDB) GetJobAppResult(ctx context.Context, jobID, appID string, window int) (*JobAppResult, error) {
 	var result JobAppResult
-	err := m.jobApps.FindOne(ctx, bson.M{"jobId": jobID, "appId": appID}).Decode(&result)
+	err := m.jobApps.FindOne(ctx, bson.M{"jobId": jobID, "appId": appID, "window": window}).Decode(&result)
 	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
+
+// isNotFound reports whether a command failed because its index or collection does not exist.
+func isNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27)
+}
@@ -0,0 +1,196 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package inline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/backend"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
+)
+
+// Runner gathers the data of a report and calculates its result in the calling goroutine, without
+// going through the event broker. Nothing is stored: it serves callers waiting for small reports.
+type Runner struct {
+	clients *backend.Clients
+	// windowSize is the length of the windows the time period of a report is fetched in, the same as the
+	// worker's, so that a report calculated inline has the value the worker would have calculated.
+	windowSize time.Duration
+}
+
+func NewRunner(clients *backend.Clients, windowSize time.Duration) *Runner {
+	return &Runner{clients: clients, windowSize: windowSize}
+}
+
+// Result is the outcome of a report calculated inline.
+type Result struct {
+	// Value is expressed in kWh for energy consumption reports, in tCO2e for carbon footprint reports.
+	Value     float64
+	Breakdown *models.ResultBreakdown
+}
+
+// Run calculates the result of the report of job. It returns as soon as ctx is done,
+// even when a backend does not honour the context, and no backend is called past that point.
+func (r *Runner) Run(ctx context.Context, job *database.Job) (*Result, error) {
+	type outcome struct {
+		result *Result
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := r.run(ctx, job)
+		done <- outcome{result, err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case o := <-done:
+		return o.result, o.err
+	}
+}
+
+func (r *Runner) run(ctx context.Context, job *database.Job) (*Result, error) {
+	spec := job.JobSpec
+	windows := job.TimeWindows(r.windowSize)
+	results := make([]database.JobAppResult, 0, len(spec.Service)*len(windows))
+	for _, appInstanceID := range spec.Service {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := r.clients.Orchestrator.GatherInformation(ctx, appInstanceID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to gather information of application instance %s: %w", appInstanceID, err)
+		}
+		for i, timePeriod := range windows {
+			metadata := database.JobAppResultMetadata{
+				JobID:            *spec.RequestId,
+				AppID:            appInstanceID.String(),
+				Window:           i,
+				NumberOfWindows:  len(windows),
+				NumberOfTotalNEs: len(info.NE),
+			}
+			result, err := r.gather(ctx, metadata, info, timePeriod)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, *result)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	calc := r.clients.Calculator
+	var (
+		value *float64
+		err   error
+	)
+	if spec.RequestKind == database.RequestKindCarbonFootprint {
+		value, err = calc.CalculateCarbonFootprint(ctx, results)
+	} else {
+		value, err = calc.CalculateEnergyConsumption(ctx, results)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate result: %w", err)
+	}
+	result := &Result{Value: *value}
+	if spec.IncludeBreakdown() {
+		if spec.RequestKind == database.RequestKindCarbonFootprint {
+			result.Breakdown, err = calc.CalculateCarbonFootprintBreakdown(ctx, results)
+		} else {
+			result.Breakdown, err = calc.CalculateEnergyConsumptionBreakdown(ctx, results)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate result breakdown: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// gather collects the same values the worker stores for an application instance over a window of the
+// time period: the application energy consumption, then the energy consumption and traffic volumes of
+// each network element.
+func (r *Runner) gather(ctx context.Context, metadata database.JobAppResultMetadata, info orchestrator.Information, timePeriod *models.TimePeriod) (*database.JobAppResult, error) {
+	appInstanceID := metadata.AppID
+	log := logger.FromContext(ctx).With(zap.String("requestID", metadata.JobID), zap.String("appInstanceID", appInstanceID), zap.Int("window", metadata.Window))
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	appEnergy, err := r.clients.CloudObservability.RetrieveAppEnergyConsumption(ctx, appInstanceID, timePeriod, info.App.InfraType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve energy consumption of application instance %s: %w", appInstanceID, err)
+	}
+
+	networkElements := make(map[string]database.NetworkElementResult, len(info.NE))
+	tvNetworkElements := make([]trafficvolume.NetworkElement, 0, len(info.NE))
+	for _, ne := range info.NE {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		energy, err := r.clients.CloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, appInstanceID, ne.InstanceID, timePeriod, ne.InfraType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve energy consumption of network element %s: %w", ne.InstanceID, err)
+		}
+		id := database.NetworkElementID{VendorID: ne.VendorID, NEInstanceID: ne.InstanceID}
+		networkElements[id.Key()] = database.NetworkElementResult{NetworkElementID: id, EnergyConsumption: energy}
+		tvNetworkElements = append(tvNetworkElements, trafficvolume.NetworkElement{
+			VendorIdentifier: ne.VendorID,
+			NEIdentifier:     ne.InstanceID,
+		})
+	}
+
+	if len(info.NE) > 0 {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		volumes, err := r.clients.TrafficVolume.RetrieveTrafficVolumes(ctx, info.App.IPList, tvNetworkElements, timePeriod)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve traffic volumes of application instance %s: %w", appInstanceID, err)
+		}
+		// A network element missing from the response leaves the result incomplete: estimating its share is
+		// left to the worker, which reports it in the coverage of the result.
+		matched, _ := trafficvolume.Match(tvNetworkElements, volumes)
+		for tvNE, measure := range matched {
+			key := database.NetworkElementID{VendorID: tvNE.VendorIdentifier, NEInstanceID: tvNE.NEIdentifier}.Key()
+			ne := networkElements[key]
+			ne.AppInstanceTraffic = &measure.TrafficVolumeIP
+			ne.TotalTraffic = &measure.TrafficVolumeAll
+			networkElements[key] = ne
+		}
+	}
+
+	result := &database.JobAppResult{
+		JobAppResultMetadata: metadata,
+		Result: &database.TaskResult{
+			AppInstanceEnergyConsumption: appEnergy,
+			NetworkElements:              networkElements,
+		},
+	}
+	if !result.IsComplete() {
+		return nil, fmt.Errorf("traffic volume missing for some network elements of application instance %s", appInstanceID)
+	}
+	log.With(zap.Int("numberOfNEs", len(info.NE))).Debug("Gathered application instance data inline")
+	return result, nil
+}
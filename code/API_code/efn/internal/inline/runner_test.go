@@ -0,0 +1,202 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package inline
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/backend"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/calculator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
+)
+
+type stubOrchestrator struct {
+	delay time.Duration
+	calls *atomic.Int32
+}
+
+func (s stubOrchestrator) GatherInformation(ctx context.Context, appInstanceID string) (orchestrator.Information, error) {
+	if s.calls != nil {
+		s.calls.Add(1)
+	}
+	time.Sleep(s.delay)
+	return orchestrator.Information{
+		App: orchestrator.ApplicationInstance{IPList: []string{"10.0.0.1"}, InfraType: "os"},
+		NE:  []orchestrator.NEInfo{{InstanceID: "ne1", VendorID: "vendor"}},
+	}, nil
+}
+
+type stubCloudObservability struct {
+	err error
+}
+
+func (s stubCloudObservability) RetrieveAppEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, appInfraType string) (*float64, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	v := 2.0
+	return &v, nil
+}
+
+func (s stubCloudObservability) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID, neInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
+	v := 10.0
+	return &v, nil
+}
+
+type stubTrafficVolume struct {
+	measures []trafficvolume.TrafficVolumeMeasure
+}
+
+func (s stubTrafficVolume) RetrieveTrafficVolumes(ctx context.Context, appInstanceIPList []string, networkElements []trafficvolume.NetworkElement, timePeriod *models.TimePeriod) (*trafficvolume.TrafficVolumeMeasureList, error) {
+	return &trafficvolume.TrafficVolumeMeasureList{TrafficVolumeMeasureList: s.measures}, nil
+}
+
+func TestRun(t *testing.T) {
+	measures := []trafficvolume.TrafficVolumeMeasure{{
+		NetworkElement:   trafficvolume.NetworkElement{VendorIdentifier: "vendor", NEIdentifier: "ne1"},
+		TrafficVolumeIP:  100,
+		TrafficVolumeAll: 1000,
+	}}
+	includeBreakdown := true
+
+	tests := []struct {
+		name            string
+		clients         backend.Clients
+		kind            database.RequestKind
+		breakdown       *bool
+		timeout         time.Duration
+		expect          float64
+		expectBreakdown bool
+		expectErr       error
+	}{
+		{
+			name: "energy consumption of the application and its network element share",
+			clients: backend.Clients{
+				Orchestrator:       stubOrchestrator{},
+				CloudObservability: stubCloudObservability{},
+				TrafficVolume:      stubTrafficVolume{measures: measures},
+			},
+			kind:   database.RequestKindEnergyConsumption,
+			expect: 3.0,
+		},
+		{
+			name: "carbon footprint with breakdown",
+			clients: backend.Clients{
+				Orchestrator:       stubOrchestrator{},
+				CloudObservability: stubCloudObservability{},
+				TrafficVolume:      stubTrafficVolume{measures: measures},
+			},
+			kind:            database.RequestKindCarbonFootprint,
+			breakdown:       &includeBreakdown,
+			expect:          3.0 * 0.5,
+			expectBreakdown: true,
+		},
+		{
+			name: "missing traffic volume",
+			clients: backend.Clients{
+				Orchestrator:       stubOrchestrator{},
+				CloudObservability: stubCloudObservability{},
+				TrafficVolume:      stubTrafficVolume{},
+			},
+			kind:      database.RequestKindEnergyConsumption,
+			expectErr: errors.New("traffic volume missing"),
+		},
+		{
+			name: "backend error",
+			clients: backend.Clients{
+				Orchestrator:       stubOrchestrator{},
+				CloudObservability: stubCloudObservability{err: errors.New("unavailable")},
+				TrafficVolume:      stubTrafficVolume{measures: measures},
+			},
+			kind:      database.RequestKindEnergyConsumption,
+			expectErr: errors.New("unavailable"),
+		},
+		{
+			name: "deadline exceeded by a backend ignoring the context",
+			clients: backend.Clients{
+				Orchestrator:       stubOrchestrator{delay: time.Second},
+				CloudObservability: stubCloudObservability{},
+				TrafficVolume:      stubTrafficVolume{measures: measures},
+			},
+			kind:      database.RequestKindEnergyConsumption,
+			timeout:   10 * time.Millisecond,
+			expectErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.clients.Calculator = calculator.NewSimpleClient(0.5)
+			requestID := "req1"
+			spec := database.JobSpec{
+				RequestId:   &requestID,
+				RequestKind: tt.kind,
+				Service:     []models.AppInstanceId{uuid.New()},
+			}
+			spec.SubscriptionRequest.Config.SubscriptionDetail.IncludeBreakdown = tt.breakdown
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			result, err := NewRunner(&tt.clients, 0).Run(ctx, &database.Job{JobSpec: spec})
+			if tt.expectErr != nil {
+				assert.ErrorContains(t, err, tt.expectErr.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expect, result.Value, 1e-9)
+			assert.Equal(t, tt.expectBreakdown, result.Breakdown != nil)
+		})
+	}
+}
+
+func TestRunStopsAfterDeadline(t *testing.T) {
+	calls := &atomic.Int32{}
+	clients := backend.Clients{
+		Orchestrator:       stubOrchestrator{delay: 50 * time.Millisecond, calls: calls},
+		CloudObservability: stubCloudObservability{},
+		TrafficVolume:      stubTrafficVolume{},
+		Calculator:         calculator.NewSimpleClient(0.5),
+	}
+	requestID := "req1"
+	spec := database.JobSpec{
+		RequestId:   &requestID,
+		RequestKind: database.RequestKindEnergyConsumption,
+		Service:     []models.AppInstanceId{uuid.New(), uuid.New(), uuid.New()},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := NewRunner(&clients, 0).Run(ctx, &database.Job{JobSpec: spec})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The application instance being gathered when the deadline passed is the last one.
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+}
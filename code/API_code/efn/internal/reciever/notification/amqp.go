@@ -0,0 +1,144 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+
+	"github.com/Azure/go-amqp"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+)
+
+// amqpSink sends the notifications of AMQP subscriptions to the target address of their protocol settings,
+// or else the path of their sink, on the container of their sink URL (amqp://, or amqps:// for TLS). A
+// connection is opened for each notification. Notifications are sent unsettled, and confirmed by the receiver
+// settling them as accepted: nothing confirms a pre-settled message, so a subscription cannot ask for one.
+type amqpSink struct {
+	config config.HTTP
+}
+
+func (s *amqpSink) Deliver(ctx context.Context, msg message) error {
+	subscription := msg.subscription
+	address := subscription.AMQPTargetAddress()
+	if address == "" {
+		return fmt.Errorf("missing AMQP target address in subscription protocol settings")
+	}
+	sink, err := url.Parse(subscription.Sink)
+	if err != nil {
+		return fmt.Errorf("invalid AMQP sink: %w", err)
+	}
+	opts := &amqp.ConnOptions{HostName: sink.Hostname(), SASLType: amqpSASL(subscription.SinkCredential)}
+	switch sink.Scheme {
+	case "amqp":
+	case "amqps":
+		opts.TLSConfig = &tls.Config{
+			ServerName:         sink.Hostname(),
+			InsecureSkipVerify: s.config.InsecureSkipVerify && isInternalClusterHost(sink.Hostname()),
+		}
+	default:
+		return fmt.Errorf("unsupported AMQP sink scheme %q", sink.Scheme)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	conn, err := amqp.Dial(ctx, sink.Scheme+"://"+sink.Host, opts)
+	if err != nil {
+		return fmt.Errorf("failed to connect to AMQP container %s: %w", sink.Host, err)
+	}
+	defer conn.Close()
+	session, err := conn.NewSession(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin AMQP session: %w", err)
+	}
+
+	sender, err := session.NewSender(ctx, address, amqpSenderOptions(subscription.AMQPSettings))
+	if err != nil {
+		return fmt.Errorf("failed to attach AMQP link to %s: %w", address, err)
+	}
+	defer sender.Close(ctx)
+	receipt, err := sender.SendWithReceipt(ctx, amqpMessage(msg), nil)
+	if err != nil {
+		return fmt.Errorf("failed to send to AMQP address %s: %w", address, err)
+	}
+	state, err := receipt.Wait(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the settlement of AMQP address %s: %w", address, err)
+	}
+	switch state := state.(type) {
+	case *amqp.StateAccepted:
+		return nil
+	case *amqp.StateRejected:
+		if state.Error != nil {
+			return fmt.Errorf("AMQP address %s rejected the notification: %w", address, state.Error)
+		}
+		return fmt.Errorf("AMQP address %s rejected the notification", address)
+	case *amqp.StateReleased:
+		return fmt.Errorf("AMQP address %s released the notification", address)
+	case *amqp.StateModified:
+		return fmt.Errorf("AMQP address %s modified the notification", address)
+	}
+	return fmt.Errorf("AMQP address %s did not accept the notification", address)
+}
+
+// amqpSASL returns the SASL mechanism a sink credential authenticates with: PLAIN with the identifier and
+// secret of a PLAIN credential, or with the token of an ACCESSTOKEN credential as password and its type as
+// user name, as for MQTT; ANONYMOUS without credential.
+func amqpSASL(cred *models.SinkCredential) amqp.SASLType {
+	if username, password, ok := mqttCredentials(cred); ok {
+		return amqp.SASLTypePlain(username, password)
+	}
+	return amqp.SASLTypeAnonymous()
+}
+
+// amqpSenderOptions returns the options of the link the notifications are sent on, which sends them all
+// unsettled whatever the senderSettlementMode of a subscription stored while settled was still accepted.
+func amqpSenderOptions(settings *models.AMQPSettings) *amqp.SenderOptions {
+	mode := amqp.SenderSettleModeUnsettled
+	opts := &amqp.SenderOptions{SettlementMode: &mode}
+	if settings == nil {
+		return opts
+	}
+	if settings.LinkName != nil {
+		opts.Name = *settings.LinkName
+	}
+	if settings.LinkProperties != nil {
+		opts.Properties = make(map[string]any, len(*settings.LinkProperties))
+		for key, value := range *settings.LinkProperties {
+			opts.Properties[key] = value
+		}
+	}
+	return opts
+}
+
+// amqpMessage returns the message of msg, carrying the CloudEvent in structured mode with the x-correlator
+// as application property.
+func amqpMessage(msg message) *amqp.Message {
+	contentType := cloudEventsContentType
+	message := &amqp.Message{
+		Data:       [][]byte{msg.body},
+		Properties: &amqp.MessageProperties{MessageID: msg.event.Id, ContentType: &contentType},
+	}
+	if msg.xCorrelator != "" {
+		message.ApplicationProperties = map[string]any{correlator.Header: msg.xCorrelator}
+	}
+	return message
+}
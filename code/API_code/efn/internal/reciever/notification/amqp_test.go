@@ -0,0 +1,547 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+)
+
+// AMQP 1.0 descriptors of the performatives, SASL frames, message sections and outcomes the test peer uses.
+const (
+	amqpOpen           = 0x10
+	amqpBegin          = 0x11
+	amqpAttach         = 0x12
+	amqpTransfer       = 0x14
+	amqpDisposition    = 0x15
+	amqpDetach         = 0x16
+	amqpEnd            = 0x17
+	amqpClose          = 0x18
+	amqpError          = 0x1d
+	amqpAccepted       = 0x24
+	amqpRejected       = 0x25
+	amqpReleased       = 0x26
+	amqpSource         = 0x28
+	amqpTarget         = 0x29
+	amqpSASLMechanisms = 0x40
+	amqpSASLInit       = 0x41
+	amqpSASLOutcome    = 0x44
+	amqpMsgProperties  = 0x73
+	amqpAppProperties  = 0x74
+	amqpData           = 0x75
+)
+
+// amqpSymbol is an AMQP symbol, told apart from a string.
+type amqpSymbol string
+
+// amqpDescribed is an AMQP described value, such as a performative.
+type amqpDescribed struct {
+	code  uint64
+	value any
+}
+
+// amqpReceived is a message received by the test peer.
+type amqpReceived struct {
+	address        string
+	linkName       string
+	linkProperties map[any]any
+	settled        bool
+	messageID      any
+	contentType    amqpSymbol
+	appProperties  map[any]any
+	body           []byte
+}
+
+// amqpPeer is an AMQP 1.0 container receiving the messages sent to it on a single connection at a time.
+// It authenticates with SASL PLAIN or ANONYMOUS, and settles unsettled messages with outcome.
+type amqpPeer struct {
+	users map[string]string
+	// outcome is the descriptor of the outcome unsettled messages are settled with.
+	outcome  uint64
+	received chan amqpReceived
+}
+
+// startAMQPPeer runs peer, and returns its address.
+func startAMQPPeer(t *testing.T, peer *amqpPeer) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	peer.received = make(chan amqpReceived, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if err := peer.serve(conn); err != nil && err != io.EOF {
+					t.Log("AMQP peer:", err)
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (p *amqpPeer) serve(conn net.Conn) error {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[4] == 3 {
+		if _, err := conn.Write(header); err != nil {
+			return err
+		}
+		if ok, err := p.authenticate(conn); !ok || err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return err
+		}
+	}
+	if _, err := conn.Write(header); err != nil {
+		return err
+	}
+
+	links := map[uint64]amqpReceived{}
+	for {
+		channel, performative, payload, err := readAMQPFrame(conn)
+		if err != nil {
+			return err
+		}
+		if performative == nil {
+			continue
+		}
+		fields, _ := performative.value.([]any)
+		var reply []byte
+		switch performative.code {
+		case amqpOpen:
+			reply = amqpList(amqpOpen, amqpString("peer"), amqpNull(), amqpUint(65536), amqpUshort(65535))
+		case amqpBegin:
+			reply = amqpList(amqpBegin, amqpUshort(channel), amqpUint(0), amqpUint(5000), amqpUint(5000), amqpUint(255))
+		case amqpAttach:
+			handle := field(fields, 1).(uint64)
+			target := field(fields, 6).(amqpDescribed).value.([]any)
+			properties, _ := field(fields, 13).(map[any]any)
+			links[handle] = amqpReceived{address: target[0].(string), linkName: field(fields, 0).(string), linkProperties: properties}
+			reply = amqpList(amqpAttach,
+				amqpString(links[handle].linkName), amqpUint(uint32(handle)), amqpBool(true),
+				amqpUbyte(field(fields, 3).(uint64)), amqpUbyte(0),
+				amqpList(amqpSource), amqpList(amqpTarget, amqpString(links[handle].address)))
+			if err := writeAMQPFrame(conn, 0, channel, reply); err != nil {
+				return err
+			}
+			// Grant credit to the sender.
+			reply = amqpList(0x13, amqpUint(0), amqpUint(5000), amqpUint(0), amqpUint(5000), amqpUint(uint32(handle)), amqpUint(0), amqpUint(100))
+		case amqpTransfer:
+			received := links[field(fields, 0).(uint64)]
+			received.settled = field(fields, 4) == true
+			if err := received.decode(payload); err != nil {
+				return err
+			}
+			p.received <- received
+			if received.settled {
+				continue
+			}
+			outcome := amqpList(p.outcome)
+			if p.outcome == amqpRejected {
+				outcome = amqpList(amqpRejected, amqpList(amqpError, amqpSymbolValue("amqp:not-allowed"), amqpString("rejected by peer")))
+			}
+			id := amqpUint(uint32(field(fields, 1).(uint64)))
+			reply = amqpList(amqpDisposition, amqpBool(true), id, id, amqpBool(true), outcome)
+		case amqpDetach:
+			reply = amqpList(amqpDetach, amqpUint(uint32(field(fields, 0).(uint64))), amqpBool(true))
+		case amqpEnd:
+			reply = amqpList(amqpEnd)
+		case amqpClose:
+			return writeAMQPFrame(conn, 0, channel, amqpList(amqpClose))
+		default:
+			continue
+		}
+		if err := writeAMQPFrame(conn, 0, channel, reply); err != nil {
+			return err
+		}
+	}
+}
+
+// authenticate offers PLAIN and ANONYMOUS, and returns whether the client authenticated.
+func (p *amqpPeer) authenticate(conn net.Conn) (bool, error) {
+	mechanisms := amqpList(amqpSASLMechanisms, amqpSymbolArray("PLAIN", "ANONYMOUS"))
+	if err := writeAMQPFrame(conn, 1, 0, mechanisms); err != nil {
+		return false, err
+	}
+	_, init, _, err := readAMQPFrame(conn)
+	if err != nil {
+		return false, err
+	}
+	fields := init.value.([]any)
+	ok := field(fields, 0) == amqpSymbol("ANONYMOUS") && p.users == nil
+	if field(fields, 0) == amqpSymbol("PLAIN") {
+		credentials := bytes.Split(field(fields, 1).([]byte), []byte{0})
+		ok = len(credentials) == 3 && p.users[string(credentials[1])] == string(credentials[2])
+	}
+	code := uint64(1)
+	if ok {
+		code = 0
+	}
+	return ok, writeAMQPFrame(conn, 1, 0, amqpList(amqpSASLOutcome, amqpUbyte(code)))
+}
+
+// decode reads the message sections of a transfer.
+func (r *amqpReceived) decode(payload []byte) error {
+	for len(payload) > 0 {
+		value, rest, err := decodeAMQP(payload)
+		if err != nil {
+			return err
+		}
+		payload = rest
+		section, ok := value.(amqpDescribed)
+		if !ok {
+			return fmt.Errorf("unexpected message section %v", value)
+		}
+		switch section.code {
+		case amqpMsgProperties:
+			properties := section.value.([]any)
+			r.messageID = field(properties, 0)
+			r.contentType, _ = field(properties, 6).(amqpSymbol)
+		case amqpAppProperties:
+			r.appProperties = section.value.(map[any]any)
+		case amqpData:
+			r.body = append(r.body, section.value.([]byte)...)
+		}
+	}
+	return nil
+}
+
+func field(fields []any, i int) any {
+	if i < len(fields) {
+		return fields[i]
+	}
+	return nil
+}
+
+// readAMQPFrame reads a frame, and returns its channel, its performative, nil for an empty frame, and the
+// payload that follows it.
+func readAMQPFrame(conn net.Conn) (uint16, *amqpDescribed, []byte, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return 0, nil, nil, err
+	}
+	body := make([]byte, binary.BigEndian.Uint32(header)-8)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return 0, nil, nil, err
+	}
+	channel := binary.BigEndian.Uint16(header[6:])
+	if len(body) == 0 {
+		return channel, nil, nil, nil
+	}
+	value, payload, err := decodeAMQP(body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	performative, ok := value.(amqpDescribed)
+	if !ok {
+		return 0, nil, nil, fmt.Errorf("unexpected frame body %v", value)
+	}
+	return channel, &performative, payload, nil
+}
+
+// writeAMQPFrame writes an AMQP (frameType 0) or SASL (frameType 1) frame.
+func writeAMQPFrame(conn net.Conn, frameType byte, channel uint16, body []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(8+len(body)))
+	header[4], header[5] = 2, frameType
+	binary.BigEndian.PutUint16(header[6:], channel)
+	_, err := conn.Write(append(header, body...))
+	return err
+}
+
+func amqpNull() []byte { return []byte{0x40} }
+
+func amqpBool(v bool) []byte {
+	if v {
+		return []byte{0x41}
+	}
+	return []byte{0x42}
+}
+
+func amqpUbyte(v uint64) []byte { return []byte{0x50, byte(v)} }
+
+func amqpUshort(v uint16) []byte { return binary.BigEndian.AppendUint16([]byte{0x60}, v) }
+
+func amqpUint(v uint32) []byte { return binary.BigEndian.AppendUint32([]byte{0x70}, v) }
+
+func amqpVariable(constructor byte, v string) []byte {
+	return append(binary.BigEndian.AppendUint32([]byte{constructor}, uint32(len(v))), v...)
+}
+
+func amqpString(v string) []byte { return amqpVariable(0xb1, v) }
+
+func amqpSymbolValue(v string) []byte { return amqpVariable(0xb3, v) }
+
+func amqpSymbolArray(values ...string) []byte {
+	elements := []byte{0xb3}
+	for _, v := range values {
+		elements = append(binary.BigEndian.AppendUint32(elements, uint32(len(v))), v...)
+	}
+	array := binary.BigEndian.AppendUint32([]byte{0xf0}, uint32(4+len(elements)))
+	array = binary.BigEndian.AppendUint32(array, uint32(len(values)))
+	return append(array, elements...)
+}
+
+// amqpList encodes a list of encoded fields described by code.
+func amqpList(code uint64, fields ...[]byte) []byte {
+	items := bytes.Join(fields, nil)
+	list := []byte{0x00, 0x53, byte(code), 0xd0}
+	list = binary.BigEndian.AppendUint32(list, uint32(4+len(items)))
+	list = binary.BigEndian.AppendUint32(list, uint32(len(fields)))
+	return append(list, items...)
+}
+
+// decodeAMQP decodes the AMQP value at the start of b, and returns the bytes after it. Integers are decoded
+// as uint64 or int64, compound values as []any or map[any]any.
+func decodeAMQP(b []byte) (any, []byte, error) {
+	if len(b) == 0 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	constructor, b := b[0], b[1:]
+	fixed := map[byte]int{
+		0x50: 1, 0x51: 1, 0x52: 1, 0x53: 1, 0x54: 1, 0x55: 1, 0x56: 1, 0x60: 2, 0x61: 2,
+		0x70: 4, 0x71: 4, 0x72: 4, 0x73: 4, 0x80: 8, 0x81: 8, 0x82: 8, 0x83: 8, 0x98: 16,
+	}
+	if n, ok := fixed[constructor]; ok {
+		if len(b) < n {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		var v uint64
+		for _, octet := range b[:n] {
+			v = v<<8 | uint64(octet)
+		}
+		switch constructor {
+		case 0x51, 0x54:
+			return int64(int8(v)), b[n:], nil
+		case 0x61:
+			return int64(int16(v)), b[n:], nil
+		case 0x71:
+			return int64(int32(v)), b[n:], nil
+		case 0x55:
+			return int64(int8(v)), b[n:], nil
+		case 0x81, 0x83:
+			return int64(v), b[n:], nil
+		case 0x56:
+			return v != 0, b[n:], nil
+		case 0x98:
+			return append([]byte(nil), b[:n]...), b[n:], nil
+		}
+		return v, b[n:], nil
+	}
+
+	switch constructor {
+	case 0x00:
+		descriptor, rest, err := decodeAMQP(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		code, _ := descriptor.(uint64)
+		value, rest, err := decodeAMQP(rest)
+		return amqpDescribed{code: code, value: value}, rest, err
+	case 0x40:
+		return nil, b, nil
+	case 0x41:
+		return true, b, nil
+	case 0x42:
+		return false, b, nil
+	case 0x43, 0x44:
+		return uint64(0), b, nil
+	case 0x45:
+		return []any{}, b, nil
+	}
+
+	// Variable width and compound values start with their size, on one octet or four.
+	sizeWidth := 4
+	if constructor&0xf0 == 0xa0 || constructor&0xf0 == 0xc0 || constructor&0xf0 == 0xe0 {
+		sizeWidth = 1
+	}
+	if len(b) < sizeWidth {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	size := int(b[0])
+	if sizeWidth == 4 {
+		size = int(binary.BigEndian.Uint32(b))
+	}
+	b = b[sizeWidth:]
+	if len(b) < size {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	data, rest := b[:size], b[size:]
+	switch constructor {
+	case 0xa0, 0xb0:
+		return append([]byte(nil), data...), rest, nil
+	case 0xa1, 0xb1:
+		return string(data), rest, nil
+	case 0xa3, 0xb3:
+		return amqpSymbol(data), rest, nil
+	}
+
+	// The count of a compound value follows its size, with the same width.
+	count := int(data[0])
+	if sizeWidth == 4 {
+		count = int(binary.BigEndian.Uint32(data))
+	}
+	data = data[sizeWidth:]
+	switch constructor {
+	case 0xc0, 0xd0, 0xc1, 0xd1:
+		items := make([]any, 0, count)
+		for range count {
+			item, next, err := decodeAMQP(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			items, data = append(items, item), next
+		}
+		if constructor == 0xc0 || constructor == 0xd0 {
+			return items, rest, nil
+		}
+		m := make(map[any]any, count/2)
+		for i := 0; i+1 < len(items); i += 2 {
+			m[items[i]] = items[i+1]
+		}
+		return m, rest, nil
+	case 0xe0, 0xf0:
+		// The elements of an array share the constructor that precedes them.
+		elementConstructor, data := data[0], data[1:]
+		items := make([]any, 0, count)
+		for range count {
+			item, next, err := decodeAMQP(append([]byte{elementConstructor}, data...))
+			if err != nil {
+				return nil, nil, err
+			}
+			items, data = append(items, item), next
+		}
+		return items, rest, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported AMQP constructor 0x%02x", constructor)
+}
+
+func TestAMQPSinkDeliver(t *testing.T) {
+	plain := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	plain.Identifier, plain.Secret = "efn", "secret"
+	wrong := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	wrong.Identifier, wrong.Secret = "efn", "wrong"
+	// settled is no longer accepted, but may still be the mode of a subscription stored before.
+	settled, unsettled := models.AMQPSettingsSenderSettlementMode("settled"), models.Unsettled
+	linkName, address := "efn-link", "efn/reports"
+
+	tests := []struct {
+		name       string
+		peer       amqpPeer
+		credential *models.SinkCredential
+		settings   *models.AMQPSettings
+		expectErr  bool
+	}{
+		{
+			name:       "sends unsettled to the sink path, accepted by the receiver",
+			peer:       amqpPeer{users: map[string]string{"efn": "secret"}, outcome: amqpAccepted},
+			credential: plain,
+			settings: &models.AMQPSettings{
+				LinkName:       &linkName,
+				LinkProperties: &map[string]string{"tenant": "acme"},
+			},
+		},
+		{
+			name:     "sends to the address of the settings",
+			peer:     amqpPeer{outcome: amqpAccepted},
+			settings: &models.AMQPSettings{Address: &address},
+		},
+		{
+			name:     "sends unsettled a subscription stored with the settled mode",
+			peer:     amqpPeer{outcome: amqpAccepted},
+			settings: &models.AMQPSettings{SenderSettlementMode: &settled},
+		},
+		{
+			name:      "fails when the receiver rejects the message",
+			peer:      amqpPeer{outcome: amqpRejected},
+			settings:  &models.AMQPSettings{SenderSettlementMode: &unsettled},
+			expectErr: true,
+		},
+		{
+			name:      "fails when the receiver releases the message",
+			peer:      amqpPeer{outcome: amqpReleased},
+			expectErr: true,
+		},
+		{
+			name:       "fails when the receiver rejects the credential",
+			peer:       amqpPeer{users: map[string]string{"efn": "secret"}},
+			credential: wrong,
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peer := tt.peer
+			sink := "amqp://" + startAMQPPeer(t, &peer) + "/notifications"
+			msg := message{
+				subscription: models.SubscriptionRequest{
+					Protocol:       models.AMQP,
+					Sink:           sink,
+					SinkCredential: tt.credential,
+					AMQPSettings:   tt.settings,
+				},
+				event:       models.CloudEvent{Id: "evt-1"},
+				body:        []byte(`{"id":"evt-1"}`),
+				xCorrelator: "corr-1",
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := (&amqpSink{config: config.HTTP{}}).Deliver(ctx, msg)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			select {
+			case received := <-peer.received:
+				assert.Equal(t, msg.subscription.AMQPTargetAddress(), received.address)
+				assert.False(t, received.settled)
+				assert.Equal(t, "evt-1", received.messageID)
+				assert.Equal(t, amqpSymbol(cloudEventsContentType), received.contentType)
+				assert.Equal(t, "corr-1", received.appProperties["x-correlator"])
+				assert.Equal(t, msg.body, received.body)
+				if tt.settings != nil && tt.settings.LinkName != nil {
+					assert.Equal(t, linkName, received.linkName)
+					assert.Equal(t, "acme", received.linkProperties[amqpSymbol("tenant")])
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("notification not received")
+			}
+		})
+	}
+}
@@ -16,84 +16,58 @@ limitations under the License.
 package notification
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
-	"strings"
 	"time"
 
 	cloudevent "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
 )
 
 // Handler processes NotificationRequested events for the Notification service.
 type Handler struct {
-	db     database.Interface
-	config config.HTTP
+	db       database.Interface
+	delivery config.Delivery
+	// sinks deliver the notifications, by delivery protocol of the subscription.
+	sinks map[models.Protocol]sinkClient
 }
 
-func NewHandler(db database.Interface, httpConfig config.HTTP) *Handler {
+func NewHandler(db database.Interface, httpConfig config.HTTP, deliveryConfig config.Delivery) *Handler {
+	mqtt := &mqttSink{config: httpConfig}
 	return &Handler{
-		db:     db,
-		config: httpConfig,
+		db:       db,
+		delivery: deliveryConfig,
+		sinks: map[models.Protocol]sinkClient{
+			models.HTTP:  &httpSink{config: httpConfig},
+			models.MQTT3: mqtt,
+			models.MQTT5: mqtt,
+			models.KAFKA: &kafkaSink{config: httpConfig},
+			models.AMQP:  &amqpSink{config: httpConfig},
+			models.NATS:  &natsSink{config: httpConfig},
+		},
 	}
 }
 
-// getHTTPClient returns an HTTP client configured based on the sink URL.
-// For internal cluster services (*.svc.cluster.local), TLS verification
-// can be skipped if configured via HTTP_INSECURE_SKIP_VERIFY.
-func (h *Handler) getHTTPClient(sinkURL string) *http.Client {
-	// Check if the sink is an internal cluster service
-	if isInternalClusterService(sinkURL) {
-		logger.Get().Debug("Detected internal cluster service", zap.String("sink", sinkURL), zap.Bool("insecureSkipVerify", h.config.InsecureSkipVerify))
-		return &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: h.config.InsecureSkipVerify,
-				},
-			},
-		}
-	}
-
-	// For external services, use standard client with system CA pool
-	return &http.Client{
-		Timeout: 30 * time.Second,
-	}
-}
-
-// isInternalClusterService checks if the URL points to an internal Kubernetes service.
-// Returns true for URLs with hostnames ending in .svc.cluster.local or just .svc
-func isInternalClusterService(sinkURL string) bool {
-	u, err := url.Parse(sinkURL)
-	if err != nil {
-		return false
-	}
-
-	hostname := strings.ToLower(u.Hostname())
-	return strings.HasSuffix(hostname, ".svc.cluster.local") ||
-		strings.HasSuffix(hostname, ".svc") ||
-		strings.Contains(hostname, ".svc.")
-}
-
-// Handle receives the internal NotificationRequested or NotificationErrorRequested event and delivers a CAMARA-compliant
-// CloudEvent to the subscriber sink. It then emits an internal NotificationSent event.
+// Handle receives the internal NotificationRequested, NotificationErrorRequested or NotificationCancelledRequested event
+// and delivers a CAMARA-compliant CloudEvent to the subscriber sink. It then emits an internal NotificationSent event.
 func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	ctx = event.ContextWithCorrelator(ctx, e)
+	log := logger.FromContext(ctx)
 	log.With(zap.String("type", e.Type()), zap.String("source", e.Source())).Info("Received event")
 
 	eventType := e.Type()
-	if eventType != event.EventTypeNotificationRequested.String() && eventType != event.EventTypeNotificationErrorRequested.String() {
+	if eventType != event.EventTypeNotificationRequested.String() &&
+		eventType != event.EventTypeNotificationErrorRequested.String() &&
+		eventType != event.EventTypeNotificationCancelledRequested.String() {
 		msg := "Unexpected event type"
 		log.Error(msg, zap.String("received", e.Type()))
 		return nil, fmt.Errorf("%s: %s", msg, e.Type())
@@ -102,6 +76,7 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 	// Parse internal notification data based on event type
 	var (
 		isErrorNotification bool
+		isCancellation      bool
 		requestID           string
 		resultValue         float64
 		errorInfo           *models.ErrorInfo
@@ -124,6 +99,16 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 		}
 		resultValue = -1.0
 		log.With(zap.Any("notificationErrorData", errorData)).Error("Received notification error requested event")
+	case event.EventTypeNotificationCancelledRequested.String():
+		isCancellation = true
+		data := event.NotificationCancelledRequestedData{}
+		if err := e.DataAs(&data); err != nil {
+			msg := "Failed to parse cancellation event data"
+			log.With(zap.Error(err)).Error(msg)
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		requestID = data.RequestID
+		log.Debug("Received notification cancelled requested event")
 	case event.EventTypeNotificationRequested.String():
 		isErrorNotification = false
 		data := event.NotificationRequestedData{}
@@ -141,21 +126,6 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 		return nil, fmt.Errorf("%s: %s", msg, e.Type())
 	}
 
-	// Atomically check and set notification flag FIRST to prevent any duplicates
-	// This is the definitive check - only ONE handler instance will proceed past this point
-	shouldSend, dbErr := h.db.TrySetNotificationSent(ctx, requestID)
-	if dbErr != nil {
-		log.With(zap.Error(dbErr)).Error("Failed to atomically check/set notificationSent flag")
-		return nil, fmt.Errorf("failed to check notificationSent for job %s: %w", requestID, dbErr)
-	}
-
-	if !shouldSend {
-		log.Info("Notification already sent by another instance, skipping duplicate")
-		return nil, nil
-	}
-
-	log.Debug("Acquired exclusive right to send notification, proceeding")
-
 	// Retrieve Job to determine sink and request kind
 	job, err := h.db.GetJob(ctx, requestID)
 	if err != nil {
@@ -164,6 +134,12 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 	}
 	log.With(zap.Any("job", job)).Debug("Fetched job for notification")
 
+	// Once cancelled, the only callback the subscriber receives is the cancellation one
+	if job.Status == database.StatusCancelled && !isCancellation {
+		log.Info("Job has been cancelled, skipping notification")
+		return nil, nil
+	}
+
 	// Check if subscription has expired
 	if job.SubscriptionRequest.Config.SubscriptionExpireTime != nil {
 		if time.Now().UTC().After(*job.SubscriptionRequest.Config.SubscriptionExpireTime) {
@@ -171,15 +147,42 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 				zap.Time("expiredAt", *job.SubscriptionRequest.Config.SubscriptionExpireTime),
 				zap.String("requestId", e.ID()),
 			).Warn("Subscription has expired, notification not sent")
+			if !isErrorNotification && !isCancellation {
+				h.completeJob(ctx, requestID)
+			}
 			// Return OK to stop knative retry loop
 			return nil, nil
 		}
 	}
 
+	// Claim the delivery, so that a single attempt at a time sends the notification. It is only final once
+	// the sink confirms it: a failed or crashed attempt leaves it to the redelivery of the event.
+	owner := uuid.NewString()
+	claimed, err := h.db.ClaimNotificationDelivery(ctx, requestID, owner, time.Now().UTC(), h.delivery.LeaseDuration)
+	if err != nil {
+		log.With(zap.Error(err)).Error("Failed to claim the notification delivery")
+		return nil, fmt.Errorf("failed to claim the notification delivery of job %s: %w", requestID, err)
+	}
+	if !claimed {
+		current, err := h.db.GetJob(ctx, requestID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job %s from DB: %w", requestID, err)
+		}
+		if current.NotificationSent {
+			log.Info("Notification already delivered, skipping duplicate")
+			return nil, nil
+		}
+		// The attempt in progress may still fail: the event is retried until it succeeds or its lease expires.
+		msg := "Notification is being delivered by another attempt"
+		log.Warn(msg)
+		return nil, fmt.Errorf("%s for job %s", msg, requestID)
+	}
+	log.With(zap.String("owner", owner)).Debug("Claimed the notification delivery, proceeding")
+
 	sink := job.SubscriptionRequest.Sink
 	if sink == "" {
 		log.Error("job sink is empty; cannot deliver callback notification")
-		return nil, fmt.Errorf("missing sink in subscription request for job %s", e.ID())
+		return nil, h.failDelivery(ctx, requestID, owner, fmt.Errorf("missing sink in subscription request for job %s", e.ID()))
 	}
 
 	// Map internal RequestKind to CAMARA event type
@@ -199,15 +202,29 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 	dataMap := map[string]interface{}{
 		"requestId": requestID,
 	}
+	// The runs of a periodic report are notified under the request of the subscriber,
+	// together with the period each of them covers.
+	if job.ParentID != "" {
+		dataMap["requestId"] = job.ParentID
+		dataMap["timePeriod"] = job.TimePeriod
+	}
 
-	switch job.RequestKind {
-	case database.RequestKindEnergyConsumption:
-		dataMap["energyConsumption"] = resultValue
-	case database.RequestKindCarbonFootprint:
+	switch {
+	case isCancellation:
+		dataMap["status"] = models.Cancelled
+	case job.RequestKind == database.RequestKindCarbonFootprint:
 		dataMap["carbonFootprint"] = resultValue
 	default:
 		dataMap["energyConsumption"] = resultValue
 	}
+	if !isErrorNotification && !isCancellation {
+		if job.Breakdown != nil {
+			dataMap["breakdown"] = job.Breakdown
+		}
+		if job.Coverage != nil {
+			dataMap["coverage"] = job.Coverage
+		}
+	}
 
 	cloudEvt := models.CloudEvent{
 		Id:              e.ID(),
@@ -223,68 +240,62 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 	if err != nil {
 		msg := "Failed to marshal cloud event for callback"
 		log.With(zap.Error(err)).Error(msg)
-		return nil, fmt.Errorf("%s: %w", msg, err)
+		return nil, h.failDelivery(ctx, requestID, owner, fmt.Errorf("%s: %w", msg, err))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink, bytes.NewReader(body))
-	if err != nil {
-		msg := "Failed to create HTTP request for callback"
-		log.With(zap.Error(err)).Error(msg)
-		return nil, fmt.Errorf("%s to sink %s: %w", msg, sink, err)
+	// Set x-correlator from the event extension, falling back to the one stored with the job
+	xCorrelator := correlator.FromContext(ctx)
+	if xCorrelator == "" {
+		xCorrelator = job.XCorrelator
 	}
-	req.Header.Set("Content-Type", string(contentType))
 
-	// Set x-correlator header if present in event extensions
-	if xcorr, ok := e.Extensions()["x-correlator"]; ok {
-		if xcorrStr, ok := xcorr.(string); ok && xcorrStr != "" {
-			req.Header.Set("x-correlator", xcorrStr)
-		}
+	// Jobs stored before other protocols were supported have none
+	protocol := job.SubscriptionRequest.Protocol
+	if protocol == "" {
+		protocol = models.HTTP
 	}
-
-	// Set custom headers from protocolSettings if present
-	if job.SubscriptionRequest.ProtocolSettings != nil && job.SubscriptionRequest.ProtocolSettings.Headers != nil {
-		for key, value := range *job.SubscriptionRequest.ProtocolSettings.Headers {
-			req.Header.Set(key, value)
-			log.Debug("Set custom header from protocolSettings", zap.String("header", key))
-		}
+	client, ok := h.sinks[protocol]
+	if !ok {
+		msg := "Subscription protocol not implemented"
+		log.With(zap.String("protocol", string(protocol))).Error(msg)
+		return nil, h.failDelivery(ctx, requestID, owner, fmt.Errorf("%s: %s", msg, protocol))
 	}
 
-	// Set Authorization header if sinkCredential is present
-	if job.SubscriptionRequest.SinkCredential != nil {
-		cred := job.SubscriptionRequest.SinkCredential
-		switch cred.CredentialType {
-		case models.SinkCredentialCredentialTypeACCESSTOKEN:
-			at := cred.AccessTokenCredential
-			if at.AccessToken != "" && at.AccessTokenType == models.AccessTokenCredentialAccessTokenTypeBearer {
-				req.Header.Set("Authorization", "Bearer "+at.AccessToken)
-			}
-		default:
-			log.Warn("credential type not implemented", zap.String("type", string(cred.CredentialType)))
-		}
+	delivering, err := h.db.SetNotificationDelivering(ctx, requestID, owner, time.Now().UTC(), h.delivery.LeaseDuration)
+	if err != nil {
+		msg := "Failed to record the notification delivery"
+		log.With(zap.Error(err)).Error(msg)
+		return nil, h.failDelivery(ctx, requestID, owner, fmt.Errorf("%s: %w", msg, err))
+	}
+	if !delivering {
+		// The lease expired and another attempt claimed the delivery in the meantime
+		msg := "Notification delivery claimed by another attempt"
+		log.Warn(msg)
+		return nil, fmt.Errorf("%s for job %s", msg, requestID)
 	}
 
 	start := time.Now()
-	client := h.getHTTPClient(sink)
-	resp, err := client.Do(req)
-	if err != nil {
+	delivered := message{subscription: job.SubscriptionRequest, event: cloudEvt, body: body, xCorrelator: xCorrelator}
+	if err := client.Deliver(ctx, delivered); err != nil {
 		msg := "Failed to deliver notification to sink"
-		log.With(zap.Error(err), zap.String("sink", sink)).Error(msg)
-		return nil, fmt.Errorf("%s %s: %w", msg, sink, err)
+		log.With(zap.Error(err), zap.String("sink", sink), zap.String("protocol", string(protocol))).Error(msg)
+		return nil, h.failDelivery(ctx, requestID, owner, fmt.Errorf("%s %s: %w", msg, sink, err))
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		msg := "Callback delivery returned non-success status"
-		log.With(zap.Int("status", resp.StatusCode), zap.String("sink", sink)).Error(msg)
-		return nil, fmt.Errorf("%s: sink %s returned status %d", msg, sink, resp.StatusCode)
+	// The sink may receive the notification again when the delivery cannot be finalized, rather than never
+	if err := h.db.SetNotificationDelivered(ctx, requestID, time.Now().UTC()); err != nil {
+		msg := "Failed to record the delivered notification"
+		log.With(zap.Error(err)).Error(msg)
+		return nil, h.failDelivery(ctx, requestID, owner, fmt.Errorf("%s: %w", msg, err))
 	}
 
 	logFields := []zap.Field{
 		zap.String("sink", sink),
-		zap.Int("status", resp.StatusCode),
+		zap.String("protocol", string(protocol)),
 		zap.Duration("latency", time.Since(start)),
 		zap.String("camaraEventType", string(camaraType)),
 		zap.Bool("isError", isErrorNotification),
+		zap.Bool("isCancellation", isCancellation),
 		zap.Float64("result", resultValue),
 	}
 
@@ -293,7 +304,27 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 	}
 
 	log.With(logFields...).Info("Notification callback delivered successfully")
+	if !isErrorNotification && !isCancellation {
+		h.completeJob(ctx, requestID)
+	}
 
 	// Emit internal event to indicate notification was sent
-	return event.Event(requestID, event.EventTypeNotificationSent, event.SourceEFNNotify, nil)
+	return event.Event(requestID, event.EventTypeNotificationSent, event.SourceEFNNotify, nil, event.WithCorrelator(xCorrelator))
+}
+
+// failDelivery records the failure of the delivery attempt of owner, so that the redelivery of the event
+// claims the delivery again without waiting for its lease to expire, and returns err.
+func (h *Handler) failDelivery(ctx context.Context, requestID, owner string, err error) error {
+	if dbErr := h.db.SetNotificationFailed(ctx, requestID, owner, err.Error(), time.Now().UTC()); dbErr != nil {
+		logger.FromContext(ctx).With(zap.Error(dbErr)).Error("Failed to record the failed notification delivery")
+	}
+	return err
+}
+
+// completeJob moves a job whose result notification has been handled from notifying to completed.
+// A failure is only logged: the callback has been handled and must not be sent again.
+func (h *Handler) completeJob(ctx context.Context, requestID string) {
+	if _, err := h.db.SetJobStatus(ctx, requestID, database.StatusCompleted); err != nil {
+		logger.FromContext(ctx).With(zap.Error(err), zap.String("requestID", requestID)).Error("failed to complete job")
+	}
 }
@@ -0,0 +1,174 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cloudevent "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+)
+
+type mockDatabase struct {
+	mock.Mock
+	database.Interface
+}
+
+func (m *mockDatabase) GetJob(ctx context.Context, id string) (*database.Job, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*database.Job), args.Error(1)
+}
+
+func (m *mockDatabase) SetJobStatus(ctx context.Context, jobID string, status database.Status) (bool, error) {
+	args := m.Called(ctx, jobID, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockDatabase) ClaimNotificationDelivery(ctx context.Context, jobID, owner string, now time.Time, lease time.Duration) (bool, error) {
+	args := m.Called(ctx, jobID, owner, now, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockDatabase) SetNotificationDelivering(ctx context.Context, jobID, owner string, now time.Time, lease time.Duration) (bool, error) {
+	args := m.Called(ctx, jobID, owner, now, lease)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockDatabase) SetNotificationDelivered(ctx context.Context, jobID string, now time.Time) error {
+	args := m.Called(ctx, jobID, now)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) SetNotificationFailed(ctx context.Context, jobID, owner, reason string, now time.Time) error {
+	args := m.Called(ctx, jobID, owner, reason, now)
+	return args.Error(0)
+}
+
+func TestHandleDelivery(t *testing.T) {
+	requestID := "req1"
+
+	tests := []struct {
+		name            string
+		sinkStatus      int
+		claimed         bool
+		delivered       bool
+		delivering      bool
+		expectErr       bool
+		expectRequest   bool
+		expectDelivered bool
+		expectFailed    bool
+		expectSentEvent bool
+	}{
+		{
+			name:            "finalizes the delivery confirmed by the sink",
+			sinkStatus:      http.StatusNoContent,
+			claimed:         true,
+			delivering:      true,
+			expectRequest:   true,
+			expectDelivered: true,
+			expectSentEvent: true,
+		},
+		{
+			name:          "records the failed delivery so that it is retried",
+			sinkStatus:    http.StatusServiceUnavailable,
+			claimed:       true,
+			delivering:    true,
+			expectErr:     true,
+			expectRequest: true,
+			expectFailed:  true,
+		},
+		{
+			name:      "skips a notification already delivered",
+			delivered: true,
+		},
+		{
+			name:      "retries while another attempt holds the delivery",
+			expectErr: true,
+		},
+		{
+			name:      "does not send once the delivery was claimed by another attempt",
+			claimed:   true,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(tt.sinkStatus)
+			}))
+			defer sink.Close()
+
+			job := &database.Job{
+				JobSpec: database.JobSpec{RequestId: &requestID, RequestKind: database.RequestKindEnergyConsumption},
+				Status:  database.StatusNotifying,
+			}
+			job.SubscriptionRequest.Sink = sink.URL
+			db := &mockDatabase{}
+			db.On("GetJob", mock.Anything, requestID).Return(job, nil).Once()
+			db.On("GetJob", mock.Anything, requestID).Return(&database.Job{NotificationSent: tt.delivered}, nil)
+			db.On("ClaimNotificationDelivery", mock.Anything, requestID, mock.Anything, mock.Anything, time.Minute).Return(tt.claimed, nil)
+			db.On("SetNotificationDelivering", mock.Anything, requestID, mock.Anything, mock.Anything, time.Minute).Return(tt.delivering, nil)
+			db.On("SetNotificationDelivered", mock.Anything, requestID, mock.Anything).Return(nil)
+			db.On("SetNotificationFailed", mock.Anything, requestID, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			db.On("SetJobStatus", mock.Anything, requestID, database.StatusCompleted).Return(true, nil)
+			h := NewHandler(db, config.HTTP{}, config.Delivery{LeaseDuration: time.Minute})
+
+			e := cloudevent.NewEvent()
+			e.SetID(requestID)
+			e.SetSource(event.SourceEFNWorker.String())
+			e.SetType(event.EventTypeNotificationRequested.String())
+			assert.NoError(t, e.SetData(cloudevent.ApplicationJSON, event.NewNotificationRequestedData(requestID, 1.5)))
+
+			sent, err := h.Handle(context.Background(), e)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectRequest, requests == 1)
+			if tt.expectSentEvent {
+				assert.NotNil(t, sent)
+				assert.Equal(t, event.EventTypeNotificationSent.String(), sent.Type())
+				db.AssertCalled(t, "SetJobStatus", mock.Anything, requestID, database.StatusCompleted)
+			} else {
+				assert.Nil(t, sent)
+				db.AssertNotCalled(t, "SetJobStatus", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectDelivered {
+				db.AssertCalled(t, "SetNotificationDelivered", mock.Anything, requestID, mock.Anything)
+			} else {
+				db.AssertNotCalled(t, "SetNotificationDelivered", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.expectFailed {
+				db.AssertCalled(t, "SetNotificationFailed", mock.Anything, requestID, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				db.AssertNotCalled(t, "SetNotificationFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.uber.org/zap"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+)
+
+// httpSink posts the notifications to the sink URL of HTTP subscriptions. The sink confirms a
+// notification with a 2xx status.
+type httpSink struct {
+	config config.HTTP
+}
+
+func (s *httpSink) Deliver(ctx context.Context, msg message) error {
+	log := logger.FromContext(ctx)
+	subscription := msg.subscription
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Sink, bytes.NewReader(msg.body))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request for callback: %w", err)
+	}
+	req.Header.Set("Content-Type", string(*msg.event.Datacontenttype))
+	if msg.xCorrelator != "" {
+		req.Header.Set(correlator.Header, msg.xCorrelator)
+	}
+
+	// Set custom headers from protocolSettings if present
+	if subscription.ProtocolSettings != nil && subscription.ProtocolSettings.Headers != nil {
+		for key, value := range *subscription.ProtocolSettings.Headers {
+			req.Header.Set(key, value)
+			log.Debug("Set custom header from protocolSettings", zap.String("header", key))
+		}
+	}
+
+	// Set Authorization header if sinkCredential is present
+	if subscription.SinkCredential != nil {
+		cred := subscription.SinkCredential
+		switch cred.CredentialType {
+		case models.SinkCredentialCredentialTypeACCESSTOKEN:
+			at := cred.AccessTokenCredential
+			if at.AccessToken != "" && at.AccessTokenType == models.AccessTokenCredentialAccessTokenTypeBearer {
+				req.Header.Set("Authorization", "Bearer "+at.AccessToken)
+			}
+		default:
+			log.Warn("credential type not implemented", zap.String("type", string(cred.CredentialType)))
+		}
+	}
+
+	resp, err := s.getHTTPClient(subscription.Sink).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback delivery returned non-success status %d", resp.StatusCode)
+	}
+	log.With(zap.Int("status", resp.StatusCode)).Debug("Callback accepted by the sink")
+	return nil
+}
+
+// getHTTPClient returns an HTTP client configured based on the sink URL.
+// For internal cluster services (*.svc.cluster.local), TLS verification
+// can be skipped if configured via HTTP_INSECURE_SKIP_VERIFY.
+func (s *httpSink) getHTTPClient(sinkURL string) *http.Client {
+	// Check if the sink is an internal cluster service
+	if isInternalClusterService(sinkURL) {
+		logger.Get().Debug("Detected internal cluster service", zap.String("sink", sinkURL), zap.Bool("insecureSkipVerify", s.config.InsecureSkipVerify))
+		return &http.Client{
+			Timeout: deliveryTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: s.config.InsecureSkipVerify,
+				},
+			},
+		}
+	}
+
+	// For external services, use standard client with system CA pool
+	return &http.Client{
+		Timeout: deliveryTimeout,
+	}
+}
+
+// isInternalClusterService checks if the URL points to an internal Kubernetes service.
+// Returns true for URLs with hostnames ending in .svc.cluster.local or just .svc
+func isInternalClusterService(sinkURL string) bool {
+	u, err := url.Parse(sinkURL)
+	if err != nil {
+		return false
+	}
+
+	return isInternalClusterHost(u.Hostname())
+}
+
+// isInternalClusterHost checks if a hostname is the one of an internal Kubernetes service.
+func isInternalClusterHost(hostname string) bool {
+	return models.IsInternalClusterHost(hostname)
+}
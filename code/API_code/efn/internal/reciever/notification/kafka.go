@@ -0,0 +1,216 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/oauth"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+)
+
+// defaultKafkaPort is the port of a bootstrap broker of a Kafka sink that has none.
+const defaultKafkaPort = "9092"
+
+// kafkaSink produces the notifications of KAFKA subscriptions to the topic of their protocol settings, on
+// the cluster of the bootstrap brokers of their sink URL (kafka://, or kafkas:// for TLS). A client is
+// created for each notification. The brokers confirm a notification by acknowledging it as required by
+// the ackMode of the subscription; with an ackMode of 0, by accepting the request it is written in.
+type kafkaSink struct {
+	config config.HTTP
+}
+
+func (s *kafkaSink) Deliver(ctx context.Context, msg message) error {
+	settings := msg.subscription.KafkaSettings
+	if settings == nil || settings.TopicName == "" {
+		return fmt.Errorf("missing Kafka topic in subscription protocol settings")
+	}
+	opts, err := s.clientOptions(msg.subscription)
+	if err != nil {
+		return err
+	}
+	record, err := kafkaRecord(msg)
+	if err != nil {
+		return err
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	if err := client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce to Kafka topic %s: %w", settings.TopicName, err)
+	}
+	return nil
+}
+
+// clientOptions returns the options of a client producing to the sink of a subscription, with its
+// credential and the acknowledgements it requires.
+func (s *kafkaSink) clientOptions(subscription models.SubscriptionRequest) ([]kgo.Opt, error) {
+	sink, err := url.Parse(subscription.Sink)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Kafka sink: %w", err)
+	}
+	if sink.Scheme != "kafka" && sink.Scheme != "kafkas" {
+		return nil, fmt.Errorf("unsupported Kafka sink scheme %q", sink.Scheme)
+	}
+	var (
+		brokers  []string
+		internal = true
+	)
+	for _, broker := range strings.Split(sink.Host, ",") {
+		host, port, err := net.SplitHostPort(broker)
+		if err != nil {
+			host, port = broker, defaultKafkaPort
+		}
+		brokers = append(brokers, net.JoinHostPort(host, port))
+		internal = internal && isInternalClusterHost(host)
+	}
+
+	settings := subscription.KafkaSettings
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(brokers...),
+		kgo.DialTimeout(deliveryTimeout),
+		kgo.RecordDeliveryTimeout(deliveryTimeout),
+	}
+	if sink.Scheme == "kafkas" {
+		opts = append(opts, kgo.DialTLSConfig(&tls.Config{
+			InsecureSkipVerify: s.config.InsecureSkipVerify && internal,
+		}))
+	}
+	if settings.ClientId != nil && *settings.ClientId != "" {
+		opts = append(opts, kgo.ClientID(*settings.ClientId))
+	}
+	// Idempotent writes need the acknowledgement of all in-sync replicas.
+	switch ackMode := settings.AckMode; {
+	case ackMode == nil || *ackMode == -1:
+		opts = append(opts, kgo.RequiredAcks(kgo.AllISRAcks()))
+	case *ackMode == 1:
+		opts = append(opts, kgo.RequiredAcks(kgo.LeaderAck()), kgo.DisableIdempotentWrite())
+	case *ackMode == 0:
+		opts = append(opts, kgo.RequiredAcks(kgo.NoAck()), kgo.DisableIdempotentWrite())
+	default:
+		return nil, fmt.Errorf("unsupported Kafka ackMode %d", *ackMode)
+	}
+
+	if cred := subscription.SinkCredential; cred != nil {
+		switch cred.CredentialType {
+		case models.SinkCredentialCredentialTypePLAIN:
+			opts = append(opts, kgo.SASL(plain.Auth{User: cred.Identifier, Pass: cred.Secret}.AsMechanism()))
+		case models.SinkCredentialCredentialTypeACCESSTOKEN:
+			opts = append(opts, kgo.SASL(oauth.Auth{Token: cred.AccessToken}.AsMechanism()))
+		}
+	}
+	return opts, nil
+}
+
+// kafkaRecord returns the record of msg in the content mode of its subscription, keyed by its
+// partitionKeyExtractor. The x-correlator is sent as a header in both modes.
+func kafkaRecord(msg message) (*kgo.Record, error) {
+	settings := msg.subscription.KafkaSettings
+	record := &kgo.Record{Topic: settings.TopicName}
+	if settings.PartitionKeyExtractor != nil {
+		if key, ok := kafkaKey(msg.event, *settings.PartitionKeyExtractor); ok {
+			record.Key = []byte(key)
+		}
+	}
+
+	if settings.ContentMode != nil && *settings.ContentMode == models.Binary {
+		value, err := json.Marshal(msg.event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal CloudEvent data: %w", err)
+		}
+		record.Value = value
+		event := msg.event
+		addHeader(record, "ce_specversion", string(event.Specversion))
+		addHeader(record, "ce_id", event.Id)
+		addHeader(record, "ce_source", event.Source)
+		addHeader(record, "ce_type", string(event.Type))
+		addHeader(record, "ce_time", event.Time.Format(time.RFC3339Nano))
+		if event.Datacontenttype != nil {
+			addHeader(record, "content-type", string(*event.Datacontenttype))
+		}
+	} else {
+		record.Value = msg.body
+		addHeader(record, "content-type", cloudEventsContentType)
+	}
+	if msg.xCorrelator != "" {
+		addHeader(record, correlator.Header, msg.xCorrelator)
+	}
+	return record, nil
+}
+
+// kafkaKey returns the value extractor selects in event: a CloudEvent attribute, or a field of the event
+// data after "data.". A field that is not a string is keyed by its JSON encoding.
+func kafkaKey(event models.CloudEvent, extractor string) (string, bool) {
+	path, isData := strings.CutPrefix(extractor, "data.")
+	if !isData {
+		switch extractor {
+		case "id":
+			return event.Id, true
+		case "source":
+			return event.Source, true
+		case "type":
+			return string(event.Type), true
+		case "specversion":
+			return string(event.Specversion), true
+		case "time":
+			return event.Time.Format(time.RFC3339Nano), true
+		}
+		return "", false
+	}
+	if event.Data == nil {
+		return "", false
+	}
+	var value any = *event.Data
+	for _, field := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if value, ok = object[field]; !ok {
+			return "", false
+		}
+	}
+	if s, ok := value.(string); ok {
+		return s, true
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+	return string(encoded), true
+}
+
+func addHeader(record *kgo.Record, key, value string) {
+	record.Headers = append(record.Headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+}
@@ -0,0 +1,207 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+)
+
+// consumeRecord returns the first record of topic in the cluster of brokers.
+func consumeRecord(t *testing.T, brokers []string, topic string) *kgo.Record {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.SASL(plain.Auth{User: "efn", Pass: "secret"}.AsMechanism()),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	fetches := client.PollRecords(ctx, 1)
+	require.NoError(t, fetches.Err())
+	records := fetches.Records()
+	require.Len(t, records, 1)
+	return records[0]
+}
+
+func headers(record *kgo.Record) map[string]string {
+	values := make(map[string]string, len(record.Headers))
+	for _, header := range record.Headers {
+		values[header.Key] = string(header.Value)
+	}
+	return values
+}
+
+func TestKafkaSinkDeliver(t *testing.T) {
+	topics := []string{"efn.structured", "efn.binary", "efn.noack"}
+	cluster, err := kfake.NewCluster(
+		kfake.NumBrokers(1),
+		kfake.EnableSASL(),
+		kfake.Superuser("PLAIN", "efn", "secret"),
+		kfake.SeedTopics(1, topics...),
+	)
+	require.NoError(t, err)
+	t.Cleanup(cluster.Close)
+	brokers := cluster.ListenAddrs()
+
+	plainCred := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	plainCred.Identifier, plainCred.Secret = "efn", "secret"
+	wrong := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	wrong.Identifier, wrong.Secret = "efn", "wrong"
+	requestID, id := "data.requestId", "id"
+	leader, none := 1, 0
+	binary := models.Binary
+
+	contentType := models.Applicationjson
+	event := models.CloudEvent{
+		Id:              "evt-1",
+		Source:          "/efn",
+		Specversion:     models.N10,
+		Type:            "org.camaraproject.energy-footprint-notification.v0.carbon-footprint",
+		Time:            time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Datacontenttype: &contentType,
+		Data:            &map[string]interface{}{"requestId": "req-1"},
+	}
+
+	tests := []struct {
+		name          string
+		credential    *models.SinkCredential
+		settings      models.ApacheKafkaSettings
+		expectErr     bool
+		expectKey     string
+		expectValue   string
+		expectHeaders map[string]string
+	}{
+		{
+			name:        "produces a structured event keyed by a data field",
+			credential:  plainCred,
+			settings:    models.ApacheKafkaSettings{TopicName: "efn.structured", PartitionKeyExtractor: &requestID},
+			expectKey:   "req-1",
+			expectValue: `{"id":"evt-1"}`,
+			expectHeaders: map[string]string{
+				"content-type": cloudEventsContentType,
+				"x-correlator": "corr-1",
+			},
+		},
+		{
+			name:       "produces a binary event keyed by an attribute, acknowledged by the leader",
+			credential: plainCred,
+			settings: models.ApacheKafkaSettings{
+				TopicName:             "efn.binary",
+				PartitionKeyExtractor: &id,
+				AckMode:               &leader,
+				ContentMode:           &binary,
+			},
+			expectKey:   "evt-1",
+			expectValue: `{"requestId":"req-1"}`,
+			expectHeaders: map[string]string{
+				"ce_specversion": "1.0",
+				"ce_id":          "evt-1",
+				"ce_source":      "/efn",
+				"ce_type":        "org.camaraproject.energy-footprint-notification.v0.carbon-footprint",
+				"ce_time":        "2025-06-01T12:00:00Z",
+				"content-type":   "application/json",
+				"x-correlator":   "corr-1",
+			},
+		},
+		{
+			name:        "produces without key or acknowledgement",
+			credential:  plainCred,
+			settings:    models.ApacheKafkaSettings{TopicName: "efn.noack", AckMode: &none},
+			expectValue: `{"id":"evt-1"}`,
+			expectHeaders: map[string]string{
+				"content-type": cloudEventsContentType,
+				"x-correlator": "corr-1",
+			},
+		},
+		{
+			name:       "fails when the cluster rejects the credential",
+			credential: wrong,
+			settings:   models.ApacheKafkaSettings{TopicName: "efn.structured"},
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := tt.settings
+			msg := message{
+				subscription: models.SubscriptionRequest{
+					Protocol:       models.KAFKA,
+					Sink:           "kafka://" + strings.Join(brokers, ","),
+					SinkCredential: tt.credential,
+					KafkaSettings:  &settings,
+				},
+				event:       event,
+				body:        []byte(`{"id":"evt-1"}`),
+				xCorrelator: "corr-1",
+			}
+
+			// The cluster drops the connection of a rejected credential, which the client retries until
+			// the delivery is abandoned.
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			err := (&kafkaSink{config: config.HTTP{}}).Deliver(ctx, msg)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			record := consumeRecord(t, brokers, settings.TopicName)
+			if tt.expectKey == "" {
+				assert.Nil(t, record.Key)
+			} else {
+				assert.Equal(t, tt.expectKey, string(record.Key))
+			}
+			assert.JSONEq(t, tt.expectValue, string(record.Value))
+			assert.Equal(t, tt.expectHeaders, headers(record))
+		})
+	}
+}
+
+func TestKafkaKey(t *testing.T) {
+	event := models.CloudEvent{
+		Id:   "evt-1",
+		Data: &map[string]interface{}{"requestId": "req-1", "request": map[string]interface{}{"count": 2}},
+	}
+	for extractor, expected := range map[string]string{
+		"id":                 "evt-1",
+		"data.requestId":     "req-1",
+		"data.request.count": "2",
+	} {
+		key, ok := kafkaKey(event, extractor)
+		assert.True(t, ok, extractor)
+		assert.Equal(t, expected, key, extractor)
+	}
+	for _, extractor := range []string{"subject", "data.missing", "data.requestId.value"} {
+		_, ok := kafkaKey(event, extractor)
+		assert.False(t, ok, extractor)
+	}
+}
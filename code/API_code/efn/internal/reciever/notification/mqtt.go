@@ -0,0 +1,230 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+)
+
+// cloudEventsContentType is the content type of a CloudEvent in structured mode, published as is.
+const cloudEventsContentType = "application/cloudevents+json"
+
+// defaultMQTTQoS is the QoS of the notifications when the subscription does not set one: the broker
+// acknowledges them.
+const defaultMQTTQoS = 1
+
+// mqttSink publishes the notifications of MQTT3 and MQTT5 subscriptions to the topic of their protocol
+// settings, on the broker of their sink URL (mqtt://, or mqtts:// for TLS). A connection is opened for each
+// notification. With QoS 1 or 2 the broker confirms a notification by acknowledging it; with QoS 0, by
+// accepting the connection it is written to.
+type mqttSink struct {
+	config config.HTTP
+}
+
+func (s *mqttSink) Deliver(ctx context.Context, msg message) error {
+	settings := msg.subscription.MQTTSettings
+	if settings == nil || settings.TopicName == "" {
+		return fmt.Errorf("missing MQTT topic in subscription protocol settings")
+	}
+	sink, err := url.Parse(msg.subscription.Sink)
+	if err != nil {
+		return fmt.Errorf("invalid MQTT sink: %w", err)
+	}
+	var tlsConfig *tls.Config
+	port := "1883"
+	switch sink.Scheme {
+	case "mqtt":
+	case "mqtts":
+		port = "8883"
+		tlsConfig = &tls.Config{
+			ServerName:         sink.Hostname(),
+			InsecureSkipVerify: s.config.InsecureSkipVerify && isInternalClusterService(msg.subscription.Sink),
+		}
+	default:
+		return fmt.Errorf("unsupported MQTT sink scheme %q", sink.Scheme)
+	}
+	if sink.Port() != "" {
+		port = sink.Port()
+	}
+	address := net.JoinHostPort(sink.Hostname(), port)
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	if msg.subscription.Protocol == models.MQTT5 {
+		return s.publish5(ctx, address, tlsConfig, msg)
+	}
+	return s.publish3(ctx, address, tlsConfig, msg)
+}
+
+// publish3 publishes msg with MQTT 3.1.1, which has neither message expiry nor user properties.
+func (s *mqttSink) publish3(ctx context.Context, address string, tlsConfig *tls.Config, msg message) error {
+	settings := msg.subscription.MQTTSettings
+	scheme := "tcp"
+	if tlsConfig != nil {
+		scheme = "ssl"
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(scheme + "://" + address).
+		SetClientID(mqttClientID()).
+		SetProtocolVersion(4).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(deliveryTimeout).
+		SetTLSConfig(tlsConfig)
+	if username, password, ok := mqttCredentials(msg.subscription.SinkCredential); ok {
+		opts.SetUsername(username).SetPassword(password)
+	}
+
+	client := mqtt.NewClient(opts)
+	if err := waitToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker %s: %w", address, err)
+	}
+	defer client.Disconnect(250)
+
+	token := client.Publish(settings.TopicName, mqttQoS(settings), settings.Retain != nil && *settings.Retain, msg.body)
+	if err := waitToken(ctx, token); err != nil {
+		return fmt.Errorf("failed to publish to MQTT topic %s: %w", settings.TopicName, err)
+	}
+	return nil
+}
+
+// publish5 publishes msg with MQTT 5, with the content type of a structured CloudEvent, the expiry and user
+// properties of the subscription and the x-correlator as a user property.
+func (s *mqttSink) publish5(ctx context.Context, address string, tlsConfig *tls.Config, msg message) error {
+	settings := msg.subscription.MQTTSettings
+	var (
+		conn net.Conn
+		err  error
+	)
+	dialer := &net.Dialer{}
+	if tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker %s: %w", address, err)
+	}
+
+	client := paho.NewClient(paho.ClientConfig{Conn: packets.NewThreadSafeConn(conn)})
+	connect := &paho.Connect{ClientID: mqttClientID(), CleanStart: true, KeepAlive: uint16(deliveryTimeout.Seconds())}
+	if username, password, ok := mqttCredentials(msg.subscription.SinkCredential); ok {
+		connect.Username, connect.UsernameFlag = username, true
+		connect.Password, connect.PasswordFlag = []byte(password), true
+	}
+	if _, err := client.Connect(ctx, connect); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to MQTT broker %s: %w", address, err)
+	}
+	defer client.Disconnect(&paho.Disconnect{ReasonCode: 0})
+
+	properties := &paho.PublishProperties{ContentType: cloudEventsContentType, User: mqttUserProperties(settings)}
+	if settings.Expiry != nil {
+		expiry := uint32(*settings.Expiry)
+		properties.MessageExpiry = &expiry
+	}
+	if msg.xCorrelator != "" {
+		properties.User.Add(correlator.Header, msg.xCorrelator)
+	}
+	_, err = client.Publish(ctx, &paho.Publish{
+		Topic:      settings.TopicName,
+		QoS:        mqttQoS(settings),
+		Retain:     settings.Retain != nil && *settings.Retain,
+		Payload:    msg.body,
+		Properties: properties,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to MQTT topic %s: %w", settings.TopicName, err)
+	}
+	return nil
+}
+
+// mqttCredentials returns the user name and password a sink credential connects with: the identifier and
+// secret of a PLAIN credential, or the token of an ACCESSTOKEN credential as password, with its type as
+// user name since MQTT 3.1.1 allows no password without one.
+func mqttCredentials(cred *models.SinkCredential) (string, string, bool) {
+	if cred == nil {
+		return "", "", false
+	}
+	switch cred.CredentialType {
+	case models.SinkCredentialCredentialTypePLAIN:
+		return cred.Identifier, cred.Secret, true
+	case models.SinkCredentialCredentialTypeACCESSTOKEN:
+		return string(cred.AccessTokenType), cred.AccessToken, cred.AccessToken != ""
+	}
+	return "", "", false
+}
+
+func mqttQoS(settings *models.MQTTSettings) byte {
+	if settings.Qos == nil {
+		return defaultMQTTQoS
+	}
+	return byte(*settings.Qos)
+}
+
+// mqttUserProperties returns the user properties of the subscription sorted by name. A value that is not a
+// string is sent as JSON.
+func mqttUserProperties(settings *models.MQTTSettings) paho.UserProperties {
+	var properties paho.UserProperties
+	if settings.UserProperties == nil {
+		return properties
+	}
+	keys := make([]string, 0, len(*settings.UserProperties))
+	for key := range *settings.UserProperties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		value, ok := (*settings.UserProperties)[key].(string)
+		if !ok {
+			encoded, _ := json.Marshal((*settings.UserProperties)[key])
+			value = string(encoded)
+		}
+		properties.Add(key, value)
+	}
+	return properties
+}
+
+// mqttClientID returns a client ID unique to a delivery, short enough for any MQTT 3.1.1 broker.
+func mqttClientID() string {
+	return "efn-" + strings.ReplaceAll(uuid.NewString(), "-", "")[:16]
+}
+
+// waitToken waits for an MQTT 3.1.1 operation to complete, until ctx is done.
+func waitToken(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,142 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	server "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+)
+
+// startBroker runs an MQTT broker accepting the users of rules, and returns its address and the messages
+// published to it.
+func startBroker(t *testing.T, rules auth.AuthRules) (string, <-chan packets.Packet) {
+	broker := server.New(&server.Options{InlineClient: true, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	require.NoError(t, broker.AddHook(new(auth.Hook), &auth.Options{Ledger: &auth.Ledger{Auth: rules}}))
+	tcp := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	require.NoError(t, broker.AddListener(tcp))
+	require.NoError(t, broker.Serve())
+	t.Cleanup(func() { broker.Close() })
+
+	published := make(chan packets.Packet, 1)
+	require.NoError(t, broker.Subscribe("efn/#", 1, func(_ *server.Client, _ packets.Subscription, pk packets.Packet) {
+		published <- pk
+	}))
+	return tcp.Address(), published
+}
+
+func TestMQTTSinkDeliver(t *testing.T) {
+	address, published := startBroker(t, auth.AuthRules{
+		{Username: "efn", Password: "secret", Allow: true},
+		{Username: "bearer", Password: "token", Allow: true},
+	})
+	plain := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	plain.Identifier, plain.Secret = "efn", "secret"
+	token := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypeACCESSTOKEN}
+	token.AccessToken, token.AccessTokenType = "token", models.AccessTokenCredentialAccessTokenTypeBearer
+	wrong := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	wrong.Identifier, wrong.Secret = "efn", "wrong"
+	expiry := int32(60)
+	retain := true
+
+	tests := []struct {
+		name        string
+		protocol    models.Protocol
+		credential  *models.SinkCredential
+		settings    models.MQTTSettings
+		expectErr   bool
+		expectProps []packets.UserProperty
+	}{
+		{
+			name:       "publishes with MQTT 3.1.1 and a plain credential",
+			protocol:   models.MQTT3,
+			credential: plain,
+			settings:   models.MQTTSettings{TopicName: "efn/reports", Retain: &retain},
+		},
+		{
+			name:       "publishes with MQTT 5 and an access token, with the user properties",
+			protocol:   models.MQTT5,
+			credential: token,
+			settings: models.MQTTSettings{
+				TopicName:      "efn/reports",
+				Expiry:         &expiry,
+				UserProperties: &map[string]interface{}{"tenant": "acme", "priority": 2},
+			},
+			expectProps: []packets.UserProperty{{Key: "priority", Val: "2"}, {Key: "tenant", Val: "acme"}, {Key: "x-correlator", Val: "corr-1"}},
+		},
+		{
+			name:       "fails when the broker rejects the credential with MQTT 3.1.1",
+			protocol:   models.MQTT3,
+			credential: wrong,
+			settings:   models.MQTTSettings{TopicName: "efn/reports"},
+			expectErr:  true,
+		},
+		{
+			name:       "fails when the broker rejects the credential with MQTT 5",
+			protocol:   models.MQTT5,
+			credential: wrong,
+			settings:   models.MQTTSettings{TopicName: "efn/reports"},
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := tt.settings
+			msg := message{
+				subscription: models.SubscriptionRequest{
+					Protocol:       tt.protocol,
+					Sink:           "mqtt://" + address,
+					SinkCredential: tt.credential,
+					MQTTSettings:   &settings,
+				},
+				body:        []byte(`{"id":"req1"}`),
+				xCorrelator: "corr-1",
+			}
+
+			err := (&mqttSink{config: config.HTTP{}}).Deliver(context.Background(), msg)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			select {
+			case pk := <-published:
+				assert.Equal(t, "efn/reports", pk.TopicName)
+				assert.Equal(t, msg.body, pk.Payload)
+				if tt.protocol == models.MQTT5 {
+					assert.Equal(t, cloudEventsContentType, string(pk.Properties.ContentType))
+					assert.Equal(t, tt.expectProps, pk.Properties.User)
+					assert.Equal(t, uint32(60), pk.Properties.MessageExpiryInterval)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("notification not published")
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+)
+
+// natsSink publishes the notifications of NATS subscriptions to the subject of their protocol settings, on
+// the servers of their sink URL (nats://, or tls:// for TLS). A connection is opened for each notification.
+// When a JetStream stream captures the subject, the stream confirms a notification by acknowledging it, and
+// deduplicates its redeliveries by event ID; otherwise the server confirms it by answering the flush that
+// follows it. An account not allowed the JetStream API publishes as without stream.
+type natsSink struct {
+	config config.HTTP
+}
+
+// errJetStreamDenied is the lookup of the stream of a subject by an account not allowed the JetStream API.
+var errJetStreamDenied = errors.New("JetStream API not allowed")
+
+func (s *natsSink) Deliver(ctx context.Context, msg message) error {
+	settings := msg.subscription.NATSSettings
+	if settings == nil || settings.Subject == "" {
+		return fmt.Errorf("missing NATS subject in subscription protocol settings")
+	}
+	servers, opts, err := s.connectOptions(msg.subscription)
+	if err != nil {
+		return err
+	}
+	// The server leaves a request it does not allow unanswered, and reports the violation on its own.
+	jsDenied := make(chan struct{})
+	var denyOnce sync.Once
+	opts = append(opts, nats.ErrorHandler(func(_ *nats.Conn, _ *nats.Subscription, err error) {
+		if errors.Is(err, nats.ErrPermissionViolation) && strings.Contains(err.Error(), jetstream.DefaultAPIPrefix) {
+			denyOnce.Do(func() { close(jsDenied) })
+		}
+	}))
+	conn, err := nats.Connect(servers, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS servers %s: %w", servers, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+	natsMsg := natsMessage(msg, settings.Subject)
+	err = natsLookupStream(ctx, js, settings.Subject, jsDenied)
+	switch {
+	case err == nil:
+		if _, err := js.PublishMsg(ctx, natsMsg, jetstream.WithMsgID(msg.event.Id)); err != nil {
+			return fmt.Errorf("failed to publish to JetStream subject %s: %w", settings.Subject, err)
+		}
+		return nil
+	case errors.Is(err, jetstream.ErrStreamNotFound), errors.Is(err, jetstream.ErrJetStreamNotEnabled),
+		errors.Is(err, nats.ErrNoResponders), errors.Is(err, errJetStreamDenied):
+		// No stream captures the subject; without JetStream on the server, nothing answers the lookup.
+		if err := conn.PublishMsg(natsMsg); err != nil {
+			return fmt.Errorf("failed to publish to NATS subject %s: %w", settings.Subject, err)
+		}
+		if err := conn.FlushWithContext(ctx); err != nil {
+			return fmt.Errorf("failed to flush NATS subject %s: %w", settings.Subject, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("failed to look up the JetStream stream of subject %s: %w", settings.Subject, err)
+	}
+}
+
+// natsLookupStream looks up the stream capturing subject, and fails with errJetStreamDenied once denied is
+// closed, rather than waiting for the answer the server will never send.
+func natsLookupStream(ctx context.Context, js jetstream.JetStream, subject string, denied <-chan struct{}) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go func() {
+		select {
+		case <-denied:
+			cancel(errJetStreamDenied)
+		case <-ctx.Done():
+		}
+	}()
+	_, err := js.StreamNameBySubject(ctx, subject)
+	if err != nil && errors.Is(context.Cause(ctx), errJetStreamDenied) {
+		return errJetStreamDenied
+	}
+	return err
+}
+
+// connectOptions returns the servers of the sink of a subscription, as a list of URLs, and the options of a
+// connection to them with its credential: PLAIN as user and password, ACCESSTOKEN as token.
+func (s *natsSink) connectOptions(subscription models.SubscriptionRequest) (string, []nats.Option, error) {
+	sink, err := url.Parse(subscription.Sink)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid NATS sink: %w", err)
+	}
+	if sink.Scheme != "nats" && sink.Scheme != "tls" {
+		return "", nil, fmt.Errorf("unsupported NATS sink scheme %q", sink.Scheme)
+	}
+	var (
+		servers  []string
+		internal = true
+	)
+	for _, server := range strings.Split(sink.Host, ",") {
+		servers = append(servers, sink.Scheme+"://"+server)
+		internal = internal && isInternalClusterService(sink.Scheme+"://"+server)
+	}
+
+	opts := []nats.Option{
+		nats.Name("efn-notification"),
+		nats.Timeout(deliveryTimeout),
+		nats.NoReconnect(),
+	}
+	if sink.Scheme == "tls" {
+		opts = append(opts, nats.Secure(&tls.Config{InsecureSkipVerify: s.config.InsecureSkipVerify && internal}))
+	}
+	if cred := subscription.SinkCredential; cred != nil {
+		switch cred.CredentialType {
+		case models.SinkCredentialCredentialTypePLAIN:
+			opts = append(opts, nats.UserInfo(cred.Identifier, cred.Secret))
+		case models.SinkCredentialCredentialTypeACCESSTOKEN:
+			opts = append(opts, nats.Token(cred.AccessToken))
+		}
+	}
+	return strings.Join(servers, ","), opts, nil
+}
+
+// natsMessage returns the message of msg on subject, carrying the CloudEvent in structured mode with the
+// x-correlator as header.
+func natsMessage(msg message, subject string) *nats.Msg {
+	natsMsg := nats.NewMsg(subject)
+	natsMsg.Data = msg.body
+	natsMsg.Header.Set("Content-Type", cloudEventsContentType)
+	if msg.xCorrelator != "" {
+		natsMsg.Header.Set(correlator.Header, msg.xCorrelator)
+	}
+	return natsMsg
+}
@@ -0,0 +1,166 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+)
+
+// startNATSServer runs a NATS server with opts, and returns a connection to it with credentials.
+func startNATSServer(t *testing.T, opts *server.Options, credentials nats.Option) (*server.Server, *nats.Conn) {
+	opts.Host, opts.Port, opts.NoLog, opts.NoSigs = "127.0.0.1", -1, true, true
+	ns, err := server.NewServer(opts)
+	require.NoError(t, err)
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+	require.True(t, ns.ReadyForConnections(5*time.Second))
+
+	conn, err := nats.Connect(ns.ClientURL(), credentials)
+	require.NoError(t, err)
+	t.Cleanup(conn.Close)
+	return ns, conn
+}
+
+func natsTestMessage(sink string, credential *models.SinkCredential, subject string) message {
+	return message{
+		subscription: models.SubscriptionRequest{
+			Protocol:       models.NATS,
+			Sink:           sink,
+			SinkCredential: credential,
+			NATSSettings:   &models.NATSSettings{Subject: subject},
+		},
+		event:       models.CloudEvent{Id: "evt-1"},
+		body:        []byte(`{"id":"evt-1"}`),
+		xCorrelator: "corr-1",
+	}
+}
+
+func TestNATSSinkDeliver(t *testing.T) {
+	ns, conn := startNATSServer(t, &server.Options{
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		Users: []*server.User{
+			{Username: "efn", Password: "secret"},
+			// publisher is allowed the subjects of the notifications only, not the JetStream API.
+			{Username: "publisher", Password: "secret", Permissions: &server.Permissions{
+				Publish:   &server.SubjectPermission{Allow: []string{"efn.>"}},
+				Subscribe: &server.SubjectPermission{Allow: []string{"_INBOX.>"}},
+			}},
+		},
+	}, nats.UserInfo("efn", "secret"))
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+	ctx := context.Background()
+	stream, err := js.CreateStream(ctx, jetstream.StreamConfig{Name: "REPORTS", Subjects: []string{"efn.reports.>"}})
+	require.NoError(t, err)
+	_, err = js.CreateStream(ctx, jetstream.StreamConfig{
+		Name:     "FULL",
+		Subjects: []string{"efn.full"},
+		MaxMsgs:  1,
+		Discard:  jetstream.DiscardNew,
+	})
+	require.NoError(t, err)
+	_, err = js.Publish(ctx, "efn.full", []byte("{}"))
+	require.NoError(t, err)
+
+	sink := "nats://" + ns.Addr().String()
+	plain := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	plain.Identifier, plain.Secret = "efn", "secret"
+	wrong := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+	wrong.Identifier, wrong.Secret = "efn", "wrong"
+	sinkClient := &natsSink{config: config.HTTP{}}
+
+	t.Run("publishes to the stream of the subject, acknowledged once per event", func(t *testing.T) {
+		msg := natsTestMessage(sink, plain, "efn.reports.acme")
+		require.NoError(t, sinkClient.Deliver(ctx, msg))
+		require.NoError(t, sinkClient.Deliver(ctx, msg))
+
+		info, err := stream.Info(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), info.State.Msgs)
+		stored, err := stream.GetLastMsgForSubject(ctx, "efn.reports.acme")
+		require.NoError(t, err)
+		assert.Equal(t, msg.body, stored.Data)
+		assert.Equal(t, cloudEventsContentType, stored.Header.Get("Content-Type"))
+		assert.Equal(t, "corr-1", stored.Header.Get("x-correlator"))
+		assert.Equal(t, "evt-1", stored.Header.Get(jetstream.MsgIDHeader))
+	})
+
+	t.Run("publishes to a subject without stream", func(t *testing.T) {
+		sub, err := conn.SubscribeSync("efn.core")
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+		require.NoError(t, conn.Flush())
+
+		msg := natsTestMessage(sink, plain, "efn.core")
+		require.NoError(t, sinkClient.Deliver(ctx, msg))
+		received, err := sub.NextMsg(5 * time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, msg.body, received.Data)
+		assert.Equal(t, "corr-1", received.Header.Get("x-correlator"))
+	})
+
+	t.Run("publishes without stream when the JetStream API is not allowed", func(t *testing.T) {
+		sub, err := conn.SubscribeSync("efn.core")
+		require.NoError(t, err)
+		defer sub.Unsubscribe()
+		require.NoError(t, conn.Flush())
+
+		publisher := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypePLAIN}
+		publisher.Identifier, publisher.Secret = "publisher", "secret"
+		msg := natsTestMessage(sink, publisher, "efn.core")
+		start := time.Now()
+		require.NoError(t, sinkClient.Deliver(ctx, msg))
+		assert.Less(t, time.Since(start), deliveryTimeout/2)
+		received, err := sub.NextMsg(5 * time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, msg.body, received.Data)
+	})
+
+	t.Run("fails when the stream refuses the notification", func(t *testing.T) {
+		assert.Error(t, sinkClient.Deliver(ctx, natsTestMessage(sink, plain, "efn.full")))
+	})
+
+	t.Run("fails when the server rejects the credential", func(t *testing.T) {
+		assert.Error(t, sinkClient.Deliver(ctx, natsTestMessage(sink, wrong, "efn.reports.acme")))
+	})
+
+	t.Run("publishes with an access token to a server without JetStream", func(t *testing.T) {
+		ns, conn := startNATSServer(t, &server.Options{Authorization: "token"}, nats.Token("token"))
+		sub, err := conn.SubscribeSync("efn.core")
+		require.NoError(t, err)
+		require.NoError(t, conn.Flush())
+
+		token := &models.SinkCredential{CredentialType: models.SinkCredentialCredentialTypeACCESSTOKEN}
+		token.AccessToken, token.AccessTokenType = "token", models.AccessTokenCredentialAccessTokenTypeBearer
+		msg := natsTestMessage("nats://"+ns.Addr().String(), token, "efn.core")
+		require.NoError(t, sinkClient.Deliver(ctx, msg))
+		received, err := sub.NextMsg(5 * time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, msg.body, received.Data)
+	})
+}
@@ -0,0 +1,41 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+)
+
+// deliveryTimeout bounds the delivery of a notification to a sink, connection included.
+const deliveryTimeout = 30 * time.Second
+
+// message is the CAMARA CloudEvent of a notification, ready to be delivered to the sink of a subscription.
+type message struct {
+	subscription models.SubscriptionRequest
+	event        models.CloudEvent
+	// body is the JSON encoding of event.
+	body        []byte
+	xCorrelator string
+}
+
+// sinkClient delivers notifications with a delivery protocol. Deliver returns once the sink has confirmed
+// the message: a nil error finalizes the delivery.
+type sinkClient interface {
+	Deliver(ctx context.Context, msg message) error
+}
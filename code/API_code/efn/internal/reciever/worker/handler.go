@@ -19,21 +19,33 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"os"
-	"strconv"
+	"time"
 
 	cloudevent "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/backend"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/scheduler"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/calculator"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/cloudobservability"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
 )
 
+// Reasons recorded on the data that could not be retrieved when a job is calculated without it.
+const (
+	reasonEnergyUnavailable  = "energy consumption could not be retrieved"
+	reasonTrafficUnavailable = "traffic volume could not be retrieved"
+	// reasonTopologyUnavailable marks an application instance the orchestrator could not resolve.
+	reasonTopologyUnavailable = "application instance could not be resolved"
+)
+
 // Handler processes JobRequested events for the Worker service.
 type Handler struct {
 	calculator         calculator.Interface
@@ -42,60 +54,78 @@ type Handler struct {
 	database           database.Interface
 	events             event.Sender
 	orchestrator       orchestrator.Interface
+	scheduler          *scheduler.Scheduler
+	watchdog           config.Watchdog
+	// allowPartialResults calculates every job with the data available, whatever its subscriber asked.
+	allowPartialResults bool
+	// missingTraffic estimates the traffic of the network elements missing from a Traffic Volume response,
+	// empty to fail them.
+	missingTraffic models.EstimatedNetworkElementMethod
+	// windowSize is the length of the windows the time period of a job is fetched in, zero for the whole period.
+	windowSize time.Duration
+	// instanceID identifies this replica as the holder of the watchdog lease.
+	instanceID string
+	// ledgerTTL is how long a handled event is remembered, zero to handle every delivery.
+	ledgerTTL time.Duration
 }
 
-func NewHandler(db database.Interface, orch orchestrator.Interface) (*Handler, error) {
+func NewHandler(db database.Interface) (*Handler, error) {
 	sender, err := event.NewSender()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cloud event sender: %w", err)
 	}
-	var cloudObs cloudobservability.Interface
-	// Check for configurable client first
-	if os.Getenv("CLIENT_TYPE") == "configurable" {
-		cloudObs, err = cloudobservability.NewConfigurableClient()
-	} else if os.Getenv("CLOUDOBS_FAIL_NE") == "true" || os.Getenv("CLOUDOBS_FAIL_THROTTLE") == "true" {
-		cloudObs, err = cloudobservability.NewErrorDummyClient()
-	} else {
-		cloudObs, err = cloudobservability.NewDummyClient()
-	}
+	clients, err := backend.NewFromEnv()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create cloud observability client: %w", err)
+		return nil, err
 	}
 
-	var tv trafficvolume.Interface
-	// Check for configurable traffic volume client
-	if os.Getenv("TRAFFIC_CLIENT_TYPE") == "configurable" {
-		tv, err = trafficvolume.NewConfigurableClient()
-	} else {
-		tv, err = trafficvolume.NewDummyClient()
-	}
+	cfg := config.GetConf()
+	missingTraffic, err := calculator.ParseTrafficFallback(cfg.Results.MissingTraffic)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create traffic volume client: %w", err)
+		return nil, err
 	}
-
-	// Read CO2 conversion factor from environment (tCO2e per kWh)
-	// Default to 0.00035 if not provided or invalid
-	var carbonFactor float64 = 0.00035
-	if val := os.Getenv("CARBON_FACTOR_TCO2E_PER_KWH"); val != "" {
-		if f, perr := strconv.ParseFloat(val, 64); perr == nil && f > 0 {
-			carbonFactor = f
-		}
+	var store backend.MeasurementStore
+	if cfg.Cache.Shared {
+		store = db
 	}
-
+	clients = backend.Cache(clients, store, cfg.Cache)
 	return &Handler{
-		calculator:         calculator.NewSimpleClient(carbonFactor),
-		cloudObservability: cloudObs,
-		trafficVolume:      tv,
-		database:           db,
-		events:             sender,
-		orchestrator:       orch,
+		calculator:          clients.Calculator,
+		cloudObservability:  clients.CloudObservability,
+		trafficVolume:       clients.TrafficVolume,
+		database:            db,
+		events:              sender,
+		orchestrator:        clients.Orchestrator,
+		scheduler:           scheduler.New(db, sender, cfg.Scheduler.LeaseDuration),
+		watchdog:            cfg.Watchdog,
+		allowPartialResults: cfg.Results.AllowPartial,
+		missingTraffic:      missingTraffic,
+		windowSize:          cfg.Gathering.WindowSize,
+		instanceID:          instanceID(),
+		ledgerTTL:           cfg.Ledger.TTL,
 	}, nil
 }
 
 func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	ctx = event.ContextWithCorrelator(ctx, e)
+	log := logger.FromContext(ctx)
 	log.With(zap.String("type", e.Type()), zap.String("source", e.Source())).Debug("Received event")
 
+	// Brokers deliver at least once: a redelivery of an event already handled is acknowledged as is.
+	if h.isProcessed(ctx, e) {
+		log.With(zap.String("type", e.Type()), zap.String("eventID", e.ID())).Info("Event already processed, skipping")
+		return nil, nil
+	}
+	res, err := h.dispatch(ctx, e)
+	if err == nil {
+		h.markProcessed(ctx, e)
+	}
+	return res, err
+}
+
+// dispatch runs the handler of the type of e.
+func (h *Handler) dispatch(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
+	log := logger.FromContext(ctx)
 	switch e.Type() {
 	case event.EventTypeGatherInfoRequested.String():
 		return h.handleGatherInfoRequested(ctx, e)
@@ -107,6 +137,8 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 		return h.handleNetworkElementTrafficRequested(ctx, e)
 	case event.EventTypeCalculationRequested.String():
 		return h.handleCalculationRequested(ctx, e)
+	case event.EventTypeScheduleTick.String():
+		return nil, h.scheduler.RunDue(ctx, time.Now().UTC())
 	default:
 		log.Error("unknown event: " + e.Type())
 		return nil, nil
@@ -115,28 +147,52 @@ func (h *Handler) Handle(ctx context.Context, e cloudevent.Event) (*cloudevent.E
 
 // handleCalculationRequested processes the calculation event after all data is gathered.
 func (h *Handler) handleCalculationRequested(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 	log.Debug("Handling Calculation Requested event")
 
 	requestID := e.ID()
 	log = log.With(zap.String("request/job id", requestID))
 
 	jobID := requestID
-	appResults, err := h.database.GetAllJobAppResults(ctx, jobID)
+	// Retrieve job to determine request kind
+	job, err := h.database.GetJob(ctx, jobID)
 	if err != nil {
-		msg := "Failed to fetch all job app results for calculation"
+		msg := "Failed to fetch job for calculation"
 		log.With(zap.Error(err)).Error(msg)
 		return nil, fmt.Errorf("%s: %w", msg, err)
 	}
-	log.With(zap.Int("numAppResults", len(appResults))).Debug("Fetched all JobAppResults for calculation")
+	if job.Status == database.StatusCancelled {
+		log.Info("Job has been cancelled, skipping calculation")
+		return nil, nil
+	}
+	moved, err := h.database.SetJobStatus(ctx, jobID, database.StatusCalculating)
+	if err != nil {
+		msg := "Failed to move job to calculating"
+		log.With(zap.Error(err)).Error(msg)
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	if !moved {
+		// A job already calculating resumes its interrupted calculation. A job the watchdog failed, or a
+		// redelivered event of a job already completed, is not calculated and notified again.
+		job, err = h.database.GetJob(ctx, jobID)
+		if err != nil {
+			msg := "Failed to fetch job for calculation"
+			log.With(zap.Error(err)).Error(msg)
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		if job.Status.IsFinal() {
+			log.With(zap.String("status", string(job.Status))).Info("Job is already final, skipping calculation")
+			return nil, nil
+		}
+	}
 
-	// Retrieve job to determine request kind
-	job, err := h.database.GetJob(ctx, jobID)
+	appResults, err := h.database.GetAllJobAppResults(ctx, jobID)
 	if err != nil {
-		msg := "Failed to fetch job for calculation"
+		msg := "Failed to fetch all job app results for calculation"
 		log.With(zap.Error(err)).Error(msg)
 		return nil, fmt.Errorf("%s: %w", msg, err)
 	}
+	log.With(zap.Int("numAppResults", len(appResults))).Debug("Fetched all JobAppResults for calculation")
 
 	// Calculate based on request kind
 	var result *float64
@@ -169,6 +225,47 @@ func (h *Handler) handleCalculationRequested(ctx context.Context, e cloudevent.E
 		log.With(zap.Float64("energyConsumption", *result)).Debug("Successfully calculated energy consumption")
 	}
 
+	var breakdown *models.ResultBreakdown
+	if job.IncludeBreakdown() {
+		if job.RequestKind == database.RequestKindCarbonFootprint {
+			breakdown, err = h.calculator.CalculateCarbonFootprintBreakdown(ctx, appResults)
+		} else {
+			breakdown, err = h.calculator.CalculateEnergyConsumptionBreakdown(ctx, appResults)
+		}
+		if err != nil {
+			msg := "Failed to calculate result breakdown"
+			log.With(zap.Error(err)).Error(msg)
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		log.With(zap.Int("numApplications", len(breakdown.Applications))).Debug("Successfully calculated result breakdown")
+	}
+
+	// The coverage tells the subscriber which data is missing or estimated
+	var coverage *models.DataCoverage
+	if h.allowPartialResults || job.AllowPartialResults() || calculator.HasEstimates(appResults) {
+		coverage, err = calculator.Coverage(appResults)
+		if err != nil {
+			msg := "Failed to calculate data coverage"
+			log.With(zap.Error(err)).Error(msg)
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		log.With(zap.Float64("coverage", coverage.Ratio)).Debug("Successfully calculated data coverage")
+		if coverage.Ratio == 0 {
+			log.Error("None of the data of the job could be retrieved")
+			if err := h.failJob(ctx, jobID, http.StatusInternalServerError, "None of the energy and traffic data could be retrieved", database.Failure{Cause: database.FailureCauseBackendError, EventType: e.Type()}); err != nil {
+				log.With(zap.Error(err)).Error("Failed to send error notification")
+				return nil, fmt.Errorf("failed to send error notification: %w", err)
+			}
+			return nil, nil
+		}
+	}
+
+	if err := h.database.SetJobResult(ctx, jobID, *result, breakdown, coverage); err != nil {
+		msg := "Failed to store calculation result in database"
+		log.With(zap.Error(err)).Error(msg)
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+
 	log.Info("Calculation completed and sending notification event", zap.String("jobID", jobID))
 	notificationData := event.NewNotificationRequestedData(requestID, *result)
 	if err := h.events.Send(ctx, requestID, event.EventTypeNotificationRequested, event.SourceEFNWorker, notificationData); err != nil {
@@ -179,7 +276,7 @@ func (h *Handler) handleCalculationRequested(ctx context.Context, e cloudevent.E
 }
 
 func (h *Handler) handleAppConsumptionRequested(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 	log.Debug("Handling App Consumption Requested")
 
 	data := event.AppConsumptionData{}
@@ -192,34 +289,33 @@ func (h *Handler) handleAppConsumptionRequested(ctx context.Context, e cloudeven
 	log.With(zap.Any("data", data)).Debug("App Consumption Data parsed from event")
 	log = log.With(zap.String("request/job id", data.RequestID), zap.String("appInstanceID", data.ApplicationInstanceID))
 
-	consumption, err := h.cloudObservability.RetrieveAppEnergyConsumption(ctx, data.ApplicationInstanceID, data.TimePeriod, data.AppInfraType)
+	cancelled, err := h.isJobCancelled(ctx, data.RequestID)
 	if err != nil {
-		if cloudobservability.IsThrottlingError(err) {
-			log.With(zap.Error(err)).Warn("Throttling error retrieving app energy consumption")
-			return nil, fmt.Errorf("throttling error: %w", err)
-		}
-		log.With(zap.Error(err)).Error("Permanent error retrieving app energy consumption")
-
-		notificationData := event.NewNotificationErrorRequestedData(
-			data.RequestID,
-			http.StatusInternalServerError,
-			"Failed to retrieve app energy consumption",
-		)
-		if sendErr := h.events.Send(ctx, data.RequestID, event.EventTypeNotificationErrorRequested, event.SourceEFNWorker, notificationData); sendErr != nil {
-			log.With(zap.Error(sendErr)).Error("Failed to send error notification")
-			return nil, fmt.Errorf("failed to send error notification: %w", sendErr)
-		}
-		log.Info("Sent error notification for failed app consumption retrieval")
+		msg := "Failed to check job cancellation"
+		log.With(zap.Error(err)).Error(msg)
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	if cancelled {
+		log.Info("Job has been cancelled, skipping app energy consumption retrieval")
 		return nil, nil
 	}
-	log.With(zap.Float64("consumption", *consumption)).Debug("Successfully retrieved app energy consumption")
 
-	// Store the result in the database
 	creationMetadata := database.JobAppResultMetadata{
 		JobID:            data.RequestID,
 		AppID:            data.ApplicationInstanceID,
+		Window:           data.Window.Index,
+		NumberOfWindows:  data.Window.Count,
 		NumberOfTotalNEs: data.NumberOfTotalNEs,
 	}
+	consumption, err := h.cloudObservability.RetrieveAppEnergyConsumption(ctx, data.ApplicationInstanceID, data.TimePeriod, data.AppInfraType)
+	if err != nil {
+		return nil, h.handleDataError(ctx, e, data.RequestID, err, "Failed to retrieve app energy consumption", func() error {
+			return h.database.SetApplicationFailure(ctx, creationMetadata, reasonEnergyUnavailable)
+		})
+	}
+	log.With(zap.Float64("consumption", *consumption)).Debug("Successfully retrieved app energy consumption")
+
+	// Store the result in the database
 	if err := h.database.CreateOrUpdateApplicationResult(ctx, creationMetadata, *consumption); err != nil {
 		msg := "Failed to store energy consumption for the application instance in database"
 		log.With(zap.Error(err)).Error(msg)
@@ -250,7 +346,7 @@ func (h *Handler) handleAppConsumptionRequested(ctx context.Context, e cloudeven
 }
 
 func (h *Handler) handleGatherInfoRequested(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 	data := event.GatherInfoData{}
 	err := e.DataAs(&data)
 	if err != nil {
@@ -268,33 +364,90 @@ func (h *Handler) handleGatherInfoRequested(ctx context.Context, e cloudevent.Ev
 		return nil, fmt.Errorf("failed to read job "+jobID+" from DB: %w", err)
 	}
 	log.With(zap.Any("Job", job)).Debug("Successfully gotten job from DB")
+	if job.Status == database.StatusCancelled {
+		log.Info("Job has been cancelled, skipping information gathering")
+		return nil, nil
+	}
+	// The first application instance picked up starts the gathering of the job.
+	if database.CanTransition(job.Status, database.StatusGathering) {
+		if _, err = h.database.SetJobStatus(ctx, jobID, database.StatusGathering); err != nil {
+			msg := "Failed to move job to gathering"
+			log.With(zap.Error(err)).Error(msg)
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+	}
 
 	info, err := h.orchestrator.GatherInformation(ctx, appInstanceID)
 	if err != nil {
-		msg := "Failed to gather application instance information with ID " + string(appInstanceID)
-		log.With(zap.Error(err)).Error(msg)
-		return nil, fmt.Errorf(msg+": %w", err)
+		// Without its topology, nothing is gathered for the application instance, over any window.
+		return nil, h.handleDataError(ctx, e, jobID, err, "Failed to gather application instance information with ID "+appInstanceID, func() error {
+			windows := job.TimeWindows(h.windowSize)
+			for i := range windows {
+				creationMetadata := database.JobAppResultMetadata{
+					JobID:           jobID,
+					AppID:           appInstanceID,
+					Window:          i,
+					NumberOfWindows: len(windows),
+				}
+				if err := h.database.SetApplicationFailure(ctx, creationMetadata, reasonTopologyUnavailable); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
 	}
 	log.With(zap.Any("Info", info)).Debug("Successfully gathered info from orchestrator")
 
-	// Send App Consumption Requested event for the application instance
-	eventId := event.EventIDForApp(jobID, appInstanceID)
+	return nil, h.requestAppData(ctx, job, appInstanceID, info, nil)
+}
+
+// requestAppData sends, for every window of the time period of the job, the events gathering the energy
+// and traffic data of an application instance that is still missing from results, the results stored so far
+// for the application instance. The events have deterministic IDs, so that sending them again for the same
+// job, application instance and window is recognisable downstream.
+func (h *Handler) requestAppData(ctx context.Context, job *database.Job, appInstanceID string, info orchestrator.Information, results []database.JobAppResult) error {
+	gathered := make(map[int]*database.TaskResult, len(results))
+	for _, r := range results {
+		gathered[r.Window] = r.Result
+	}
+	windows := job.TimeWindows(h.windowSize)
+	for i, timePeriod := range windows {
+		window := event.Window{Index: i, Count: len(windows)}
+		if err := h.requestWindowData(ctx, job, appInstanceID, info, window, timePeriod, gathered[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestWindowData sends the events gathering the data of an application instance over a window that is
+// still missing from gathered, or all of them when gathered is nil.
+func (h *Handler) requestWindowData(ctx context.Context, job *database.Job, appInstanceID string, info orchestrator.Information, window event.Window, timePeriod *models.TimePeriod, gathered *database.TaskResult) error {
+	log := logger.FromContext(ctx).With(zap.String("requestID", *job.RequestId), zap.String("appInstanceID", appInstanceID), zap.Int("window", window.Index))
+	jobID := *job.RequestId
 	numberOfTotalNEs := len(info.NE)
-	eventData := event.NewAppConsumptionData(
-		jobID,
-		appInstanceID,
-		job.TimePeriod,
-		info.App.InfraType,
-		numberOfTotalNEs,
-	)
-	if err = h.events.Send(ctx, eventId, event.EventTypeAppConsumptionRequested, event.SourceEFNWorker, eventData); err != nil {
-		msg := "Failed to send cloud event to get app consumption"
-		log.With(zap.Error(err), zap.String("Event ID", eventId)).Error(msg)
-		return nil, fmt.Errorf("%s: %w", msg, err)
+
+	// Send App Consumption Requested event for the application instance
+	if gathered == nil || (gathered.AppInstanceEnergyConsumption == nil && gathered.AppInstanceFailure == "") {
+		eventId := event.EventIDForAppWindow(jobID, appInstanceID, window.Index)
+		eventData := event.NewAppConsumptionData(
+			jobID,
+			appInstanceID,
+			timePeriod,
+			info.App.InfraType,
+			numberOfTotalNEs,
+			window,
+		)
+		if err := h.events.Send(ctx, eventId, event.EventTypeAppConsumptionRequested, event.SourceEFNWorker, eventData); err != nil {
+			msg := "Failed to send cloud event to get app consumption"
+			log.With(zap.Error(err), zap.String("Event ID", eventId)).Error(msg)
+			return fmt.Errorf("%s: %w", msg, err)
+		}
 	}
 
 	// Build array of all network elements
 	networkElements := make([]event.NetworkElementInfo, 0, len(info.NE))
+	trafficMissing := false
 	for _, neInfo := range info.NE {
 		networkElements = append(networkElements, event.NetworkElementInfo{
 			NEInstanceID: neInfo.InstanceID,
@@ -302,45 +455,70 @@ func (h *Handler) handleGatherInfoRequested(ctx context.Context, e cloudevent.Ev
 			NetworkID:    neInfo.NetworkID,
 			NEInfraType:  neInfo.InfraType,
 		})
+		neResult := gatheredNE(gathered, neInfo)
+		if neResult.Failure == "" && !neResult.HasTraffic() {
+			trafficMissing = true
+		}
 	}
 
 	// Send individual Network Element Energy Requested events for each NE
 	for _, neInfo := range info.NE {
-		eventId := event.EventIDForNE(jobID, appInstanceID, neInfo.InstanceID)
+		if neResult := gatheredNE(gathered, neInfo); neResult.EnergyConsumption != nil || neResult.Failure != "" {
+			continue
+		}
+		eventId := event.EventIDForNE(jobID, appInstanceID, neInfo.VendorID, neInfo.InstanceID, window.Index)
 		neEnergyEventData := event.NewNetworkElementEnergyData(
 			jobID,
 			appInstanceID,
+			neInfo.VendorID,
 			neInfo.InstanceID,
 			neInfo.InfraType,
-			job.TimePeriod,
+			timePeriod,
 			numberOfTotalNEs,
+			window,
 		)
-		if err = h.events.Send(ctx, eventId, event.EventTypeNetworkElementEnergyRequested, event.SourceEFNWorker, neEnergyEventData); err != nil {
+		if err := h.events.Send(ctx, eventId, event.EventTypeNetworkElementEnergyRequested, event.SourceEFNWorker, neEnergyEventData); err != nil {
 			msg := "Failed to send cloud event to get network element energy consumption"
 			log.With(zap.Error(err), zap.String("Event ID", eventId), zap.String("NE ID", neInfo.InstanceID)).Error(msg)
-			return nil, fmt.Errorf("%s: %w", msg, err)
+			return fmt.Errorf("%s: %w", msg, err)
 		}
 	}
 
 	// Send single Network Element Traffic Requested event with all network elements
-	eventId = event.EventIDForTraffic(jobID, appInstanceID)
-	neTrafficEventData := event.NewNetworkElementTrafficData(
-		jobID,
-		appInstanceID,
-		info.App.IPList,
-		job.TimePeriod,
-		networkElements,
-	)
-	if err = h.events.Send(ctx, eventId, event.EventTypeNetworkElementTrafficRequested, event.SourceEFNWorker, neTrafficEventData); err != nil {
-		msg := "Failed to send cloud event to get network element traffic volume"
-		log.With(zap.Error(err), zap.String("Event ID", eventId), zap.Int("NE Count", len(networkElements))).Error(msg)
-		return nil, fmt.Errorf("%s: %w", msg, err)
+	if gathered == nil || trafficMissing {
+		eventId := event.EventIDForTraffic(jobID, appInstanceID, window.Index)
+		neTrafficEventData := event.NewNetworkElementTrafficData(
+			jobID,
+			appInstanceID,
+			info.App.IPList,
+			timePeriod,
+			networkElements,
+			window,
+		)
+		if err := h.events.Send(ctx, eventId, event.EventTypeNetworkElementTrafficRequested, event.SourceEFNWorker, neTrafficEventData); err != nil {
+			msg := "Failed to send cloud event to get network element traffic volume"
+			log.With(zap.Error(err), zap.String("Event ID", eventId), zap.Int("NE Count", len(networkElements))).Error(msg)
+			return fmt.Errorf("%s: %w", msg, err)
+		}
 	}
-	return nil, nil
+	return nil
+}
+
+// gatheredNE returns the values gathered so far for a network element, empty if there are none.
+func gatheredNE(gathered *database.TaskResult, neInfo orchestrator.NEInfo) database.NetworkElementResult {
+	if gathered == nil {
+		return database.NetworkElementResult{}
+	}
+	return gathered.NetworkElements[database.NetworkElementID{VendorID: neInfo.VendorID, NEInstanceID: neInfo.InstanceID}.Key()]
+}
+
+// networkElementID returns the ID the results of a network element of a Traffic Volume request are stored under.
+func networkElementID(ne trafficvolume.NetworkElement) database.NetworkElementID {
+	return database.NetworkElementID{VendorID: ne.VendorIdentifier, NEInstanceID: ne.NEIdentifier}
 }
 
 func (h *Handler) handleNetworkElementEnergyRequested(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 	log.Debug("Handling Network Element Energy Requested")
 
 	data := event.NetworkElementEnergyData{}
@@ -362,35 +540,35 @@ func (h *Handler) handleNetworkElementEnergyRequested(ctx context.Context, e clo
 		zap.String("neInstanceID", data.NEInstanceID),
 	)
 
-	consumption, err := h.cloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, data.ApplicationInstanceID, data.TimePeriod, data.NEInfraType)
+	cancelled, err := h.isJobCancelled(ctx, data.RequestID)
 	if err != nil {
-		if cloudobservability.IsThrottlingError(err) {
-			log.With(zap.Error(err)).Warn("Throttling error retrieving network element energy consumption")
-			return nil, fmt.Errorf("throttling error for NE %s: %w", data.NEInstanceID, err)
-		}
-		log.With(zap.Error(err)).Error("Permanent error retrieving network element energy consumption")
-
-		notificationData := event.NewNotificationErrorRequestedData(
-			data.RequestID,
-			http.StatusInternalServerError,
-			"Failed to retrieve network element energy consumption",
-		)
-		if sendErr := h.events.Send(ctx, data.RequestID, event.EventTypeNotificationErrorRequested, event.SourceEFNWorker, notificationData); sendErr != nil {
-			log.With(zap.Error(sendErr)).Error("Failed to send error notification")
-			return nil, fmt.Errorf("failed to send error notification: %w", sendErr)
-		}
-		log.Info("Sent error notification for failed network element energy retrieval")
+		msg := "Failed to check job cancellation"
+		log.With(zap.Error(err)).Error(msg)
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	if cancelled {
+		log.Info("Job has been cancelled, skipping network element energy retrieval")
 		return nil, nil
 	}
-	log.With(zap.Float64("consumption", *consumption)).Debug("Successfully retrieved network element energy consumption")
 
-	// Store only the energy consumption in the database
 	creationMetadata := database.JobAppResultMetadata{
 		JobID:            data.RequestID,
 		AppID:            data.ApplicationInstanceID,
+		Window:           data.Window.Index,
+		NumberOfWindows:  data.Window.Count,
 		NumberOfTotalNEs: data.NumberOfTotalNEs,
 	}
-	if err := h.database.SetNetworkElementEnergy(ctx, creationMetadata, data.NEInstanceID, *consumption); err != nil {
+	ne := database.NetworkElementID{VendorID: data.VendorID, NEInstanceID: data.NEInstanceID}
+	consumption, err := h.cloudObservability.RetrieveNetworkElementEnergyConsumption(ctx, data.ApplicationInstanceID, data.NEInstanceID, data.TimePeriod, data.NEInfraType)
+	if err != nil {
+		return nil, h.handleDataError(ctx, e, data.RequestID, err, "Failed to retrieve network element energy consumption", func() error {
+			return h.database.SetNetworkElementFailure(ctx, creationMetadata, ne, reasonEnergyUnavailable)
+		})
+	}
+	log.With(zap.Float64("consumption", *consumption)).Debug("Successfully retrieved network element energy consumption")
+
+	// Store only the energy consumption in the database
+	if err := h.database.SetNetworkElementEnergy(ctx, creationMetadata, ne, *consumption); err != nil {
 		msg := "Failed to store energy consumption for the network element in database"
 		log.With(zap.Error(err)).Error(msg)
 		return nil, fmt.Errorf("%s for NE %s: %w", msg, data.NEInstanceID, err)
@@ -420,7 +598,7 @@ func (h *Handler) handleNetworkElementEnergyRequested(ctx context.Context, e clo
 }
 
 func (h *Handler) handleNetworkElementTrafficRequested(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 	log.Debug("Handling Network Element Traffic Requested")
 
 	data := event.NetworkElementTrafficData{}
@@ -442,6 +620,17 @@ func (h *Handler) handleNetworkElementTrafficRequested(ctx context.Context, e cl
 		zap.String("appInstanceID", data.ApplicationInstanceID),
 	)
 
+	cancelled, err := h.isJobCancelled(ctx, data.RequestID)
+	if err != nil {
+		msg := "Failed to check job cancellation"
+		log.With(zap.Error(err)).Error(msg)
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	if cancelled {
+		log.Info("Job has been cancelled, skipping network element traffic retrieval")
+		return nil, nil
+	}
+
 	// Build list of network elements for Traffic Volume API call
 	tvNetworkElements := make([]trafficvolume.NetworkElement, 0, len(data.NetworkElements))
 	for _, neInfo := range data.NetworkElements {
@@ -451,56 +640,61 @@ func (h *Handler) handleNetworkElementTrafficRequested(ctx context.Context, e cl
 		})
 	}
 
+	creationMetadata := database.JobAppResultMetadata{
+		JobID:            data.RequestID,
+		AppID:            data.ApplicationInstanceID,
+		Window:           data.Window.Index,
+		NumberOfWindows:  data.Window.Count,
+		NumberOfTotalNEs: len(data.NetworkElements),
+	}
 	// Retrieve traffic volumes for all network elements in one call
 	trafficVolumes, err := h.trafficVolume.RetrieveTrafficVolumes(ctx, data.AppInstanceIPList, tvNetworkElements, data.TimePeriod)
 	if err != nil {
-		msg := "Failed to retrieve traffic volumes from Traffic Volume API"
-		log.With(zap.Error(err)).Error(msg)
-		return nil, fmt.Errorf("%s: %w", msg, err)
+		return nil, h.handleDataError(ctx, e, data.RequestID, err, "Failed to retrieve traffic volumes from Traffic Volume API", func() error {
+			return h.markTrafficMissing(ctx, creationMetadata, tvNetworkElements)
+		})
 	}
 	log.With(zap.Int("measureCount", len(trafficVolumes.TrafficVolumeMeasureList))).Debug("Successfully retrieved traffic volumes from Traffic Volume API")
 
-	// Create a map for quick lookup of traffic volumes by NE identifier
-	trafficVolumeMap := make(map[string]trafficvolume.TrafficVolumeMeasure)
-	for _, measure := range trafficVolumes.TrafficVolumeMeasureList {
-		trafficVolumeMap[measure.NetworkElement.NEIdentifier] = measure
-	}
+	// Measures are matched by vendor and NE identifier together
+	matched, missing := trafficvolume.Match(tvNetworkElements, trafficVolumes)
 
 	// Process and store traffic for each network element
-	for _, neInfo := range data.NetworkElements {
-		log.With(
-			zap.String("neInstanceID", neInfo.NEInstanceID),
-			zap.String("vendorID", neInfo.VendorID),
-		).Debug("Processing network element traffic")
-
-		// Get traffic volumes from the map
-		trafficVolume, ok := trafficVolumeMap[neInfo.NEInstanceID]
+	for _, ne := range tvNetworkElements {
+		trafficVolume, ok := matched[ne]
 		if !ok {
-			msg := "Traffic volume not found in Traffic Volume API response"
-			log.With(zap.String("neInstanceID", neInfo.NEInstanceID)).Error(msg)
-			return nil, fmt.Errorf("%s for NE %s", msg, neInfo.NEInstanceID)
+			continue
 		}
-
 		traffic := trafficVolume.TrafficVolumeIP
 		totalNETraffic := trafficVolume.TrafficVolumeAll
 		log.With(
 			zap.Float64("traffic", traffic),
 			zap.Float64("totalTraffic", totalNETraffic),
-			zap.String("neInstanceID", neInfo.NEInstanceID),
+			zap.String("neInstanceID", ne.NEIdentifier),
+			zap.String("vendorID", ne.VendorIdentifier),
 		).Debug("Retrieved traffic volumes for network element")
 
 		// Store only the traffic volume fields in the database
-		creationMetadata := database.JobAppResultMetadata{
-			JobID:            data.RequestID,
-			AppID:            data.ApplicationInstanceID,
-			NumberOfTotalNEs: len(data.NetworkElements),
-		}
-		if err := h.database.SetNetworkElementTraffic(ctx, creationMetadata, neInfo.NEInstanceID, traffic, totalNETraffic); err != nil {
+		if err := h.database.SetNetworkElementTraffic(ctx, creationMetadata, networkElementID(ne), traffic, totalNETraffic); err != nil {
 			msg := "Failed to store traffic for the network element in database"
-			log.With(zap.Error(err), zap.String("neInstanceID", neInfo.NEInstanceID)).Error(msg)
-			return nil, fmt.Errorf("%s for NE %s: %w", msg, neInfo.NEInstanceID, err)
+			log.With(zap.Error(err), zap.String("neInstanceID", ne.NEIdentifier)).Error(msg)
+			return nil, fmt.Errorf("%s for NE %s: %w", msg, ne.NEIdentifier, err)
+		}
+		log.With(zap.String("neInstanceID", ne.NEIdentifier)).Debug("Successfully stored network element traffic in database")
+	}
+	if len(missing) > 0 {
+		if h.missingTraffic == "" {
+			// Another call would get the same response: the job fails, or is calculated without the network
+			// elements when partial results are allowed, the traffic of the other network elements being kept.
+			msg := "Traffic volume not found in Traffic Volume API response"
+			notFound := datasource.NewNotFound(fmt.Sprintf("%s for NE %s and %d more", msg, missing[0].NEIdentifier, len(missing)-1))
+			return nil, h.handleDataError(ctx, e, data.RequestID, notFound, msg, func() error {
+				return h.markTrafficMissing(ctx, creationMetadata, missing)
+			})
+		}
+		if err := h.estimateMissingTraffic(ctx, creationMetadata, missing); err != nil {
+			return nil, err
 		}
-		log.With(zap.String("neInstanceID", neInfo.NEInstanceID)).Debug("Successfully stored network element traffic in database")
 	}
 
 	isAllDataGathered, err := h.isAllDataGathered(ctx, data.RequestID)
@@ -525,8 +719,35 @@ func (h *Handler) handleNetworkElementTrafficRequested(ctx context.Context, e cl
 	return nil, nil
 }
 
+// estimateMissingTraffic records the configured method estimating the share of the network elements missing
+// from a Traffic Volume response.
+func (h *Handler) estimateMissingTraffic(ctx context.Context, creationMetadata database.JobAppResultMetadata, missing []trafficvolume.NetworkElement) error {
+	log := logger.FromContext(ctx)
+	for _, ne := range missing {
+		log.With(zap.String("neInstanceID", ne.NEIdentifier), zap.String("vendorID", ne.VendorIdentifier), zap.String("method", string(h.missingTraffic))).
+			Warn("Traffic volume not found in Traffic Volume API response, estimating the share of the network element")
+		if err := h.database.SetNetworkElementTrafficEstimate(ctx, creationMetadata, networkElementID(ne), h.missingTraffic); err != nil {
+			msg := "Failed to record the traffic estimate of the network element in database"
+			log.With(zap.Error(err), zap.String("neInstanceID", ne.NEIdentifier)).Error(msg)
+			return fmt.Errorf("%s for NE %s: %w", msg, ne.NEIdentifier, err)
+		}
+	}
+	return nil
+}
+
+// markTrafficMissing marks the network elements whose traffic could not be retrieved, for the job to be
+// calculated without them.
+func (h *Handler) markTrafficMissing(ctx context.Context, creationMetadata database.JobAppResultMetadata, nes []trafficvolume.NetworkElement) error {
+	for _, ne := range nes {
+		if err := h.database.SetNetworkElementFailure(ctx, creationMetadata, networkElementID(ne), reasonTrafficUnavailable); err != nil {
+			return fmt.Errorf("NE %s: %w", ne.NEIdentifier, err)
+		}
+	}
+	return nil
+}
+
 func (h *Handler) isAllDataGathered(ctx context.Context, requestID string) (bool, error) {
-	log := logger.Get().With(zap.String("requestID", requestID))
+	log := logger.FromContext(ctx).With(zap.String("requestID", requestID))
 
 	// Fetch job to know expected number of app instances
 	job, err := h.database.GetJob(ctx, requestID)
@@ -539,17 +760,27 @@ func (h *Handler) isAllDataGathered(ctx context.Context, requestID string) (bool
 		return false, fmt.Errorf("failed to get all job app results for requestID %s: %w", requestID, err)
 	}
 
-	// If it hasn't yet created results for every app instance, it's not done.
-	expectedApps := len(job.JobSpec.Service)
-	if len(results) != expectedApps {
-		log.With(zap.Int("expected", expectedApps), zap.Int("actual", len(results))).Debug("Not all app results present yet")
+	// A cancelled job is never calculated, whatever data has been gathered.
+	if job.Status == database.StatusCancelled {
+		log.Debug("Job has been cancelled, calculation will not be triggered")
 		return false, nil
 	}
 
-	// Check each app instance is fully populated.
+	// If it hasn't yet created results for every app instance and window, it's not done.
+	// All the results of a job share the same number of windows.
+	expectedResults := len(job.JobSpec.Service)
+	if len(results) > 0 {
+		expectedResults *= results[0].Windows()
+	}
+	if len(results) != expectedResults {
+		log.With(zap.Int("expected", expectedResults), zap.Int("actual", len(results))).Debug("Not all app results present yet")
+		return false, nil
+	}
+
+	// Check each app instance is fully populated. Values marked as failed are not awaited any more.
 	for i, appResult := range results {
 		result := appResult.Result
-		if result == nil || result.AppInstanceEnergyConsumption == nil {
+		if result == nil || (result.AppInstanceEnergyConsumption == nil && result.AppInstanceFailure == "") {
 			log.With(zap.Int("appIndex", i), zap.String("appID", appResult.AppID)).Debug("App result incomplete: missing result or consumption")
 			return false, nil
 		}
@@ -563,7 +794,7 @@ func (h *Handler) isAllDataGathered(ctx context.Context, requestID string) (bool
 			return false, nil
 		}
 		for neID, neResult := range result.NetworkElements {
-			if neResult.EnergyConsumption == nil || neResult.AppInstanceTraffic == nil || neResult.TotalTraffic == nil {
+			if neResult.Failure == "" && (neResult.EnergyConsumption == nil || !neResult.HasTraffic()) {
 				log.With(
 					zap.Int("appIndex", i),
 					zap.String("appID", appResult.AppID),
@@ -591,7 +822,8 @@ func (h *Handler) isAllDataGathered(ctx context.Context, requestID string) (bool
 }
 
 func (h *Handler) HandleDLQEvent(ctx context.Context, e cloudevent.Event) (*cloudevent.Event, error) {
-	log := logger.Get()
+	ctx = event.ContextWithCorrelator(ctx, e)
+	log := logger.FromContext(ctx)
 	log.With(zap.String("eventType", e.Type()), zap.String("eventID", e.ID())).Info("DLQ event received after broker retries exhausted")
 
 	// All event types in our system have RequestID field, so we can use a generic approach
@@ -604,17 +836,109 @@ func (h *Handler) HandleDLQEvent(ctx context.Context, e cloudevent.Event) (*clou
 	}
 
 	requestID := eventData.RequestID
+	if requestID == "" {
+		// Schedule ticks are not bound to a job: the due runs are started again by the next tick.
+		log.Warn("DLQ event without request ID, nothing to fail")
+		return nil, nil
+	}
 	log = log.With(zap.String("requestID", requestID))
 	log.Debug("Extracted request ID from DLQ event")
-	notificationData := event.NewNotificationErrorRequestedData(
-		requestID,
-		http.StatusInternalServerError,
-		"Event processing failed after multiple retries",
-	)
-	if err := h.events.Send(ctx, requestID, event.EventTypeNotificationErrorRequested, event.SourceEFNWorker, notificationData); err != nil {
+	if err := h.failJob(ctx, requestID, http.StatusInternalServerError, "Event processing failed after multiple retries", database.Failure{Cause: database.FailureCauseRetriesExhausted, EventType: e.Type()}); err != nil {
 		log.With(zap.Error(err)).Error("Failed to send error notification from DLQ")
 		return nil, err
 	}
 	log.Info("Successfully sent error notification event from DLQ")
 	return nil, nil
 }
+
+// handleDataError decides what becomes of an event whose data could not be retrieved because of err, the
+// same way whatever the data source. A retryable error is returned, for the broker to deliver the event again.
+// Otherwise, markMissing records the data as missing when the job allows partial results, or the job fails
+// with the status matching the class of err.
+func (h *Handler) handleDataError(ctx context.Context, e cloudevent.Event, requestID string, err error, message string, markMissing func() error) error {
+	log := logger.FromContext(ctx).With(zap.Error(err), zap.String("errorClass", string(datasource.ClassOf(err))))
+	if datasource.IsRetryable(err) {
+		log.With(zap.Duration("retryAfter", datasource.RetryAfter(err))).Warn(message + ", the event will be retried")
+		return fmt.Errorf("%s: %w", message, err)
+	}
+	log.Error(message)
+
+	partial, partialErr := h.partialResultsAllowed(ctx, requestID)
+	if partialErr != nil {
+		log.With(zap.Error(partialErr)).Error("Failed to check partial results policy")
+		return partialErr
+	}
+	if partial {
+		log.Warn("Calculating without the data that could not be retrieved")
+		if markErr := markMissing(); markErr != nil {
+			msg := "Failed to mark the data as missing in database"
+			log.With(zap.Error(markErr)).Error(msg)
+			return fmt.Errorf("%s: %w", msg, markErr)
+		}
+		return h.requestCalculationIfGathered(ctx, requestID)
+	}
+
+	if sendErr := h.failJob(ctx, requestID, datasource.HTTPStatus(err), message, database.Failure{Cause: database.FailureCauseBackendError, EventType: e.Type()}); sendErr != nil {
+		log.With(zap.Error(sendErr)).Error("Failed to send error notification")
+		return fmt.Errorf("failed to send error notification: %w", sendErr)
+	}
+	log.Info("Sent error notification for the data that could not be retrieved")
+	return nil
+}
+
+// partialResultsAllowed reports whether the job is calculated with the data available when part of it
+// cannot be retrieved, either because its subscriber asked for it or because it is enabled for all jobs.
+func (h *Handler) partialResultsAllowed(ctx context.Context, requestID string) (bool, error) {
+	if h.allowPartialResults {
+		return true, nil
+	}
+	job, err := h.database.GetJob(ctx, requestID)
+	if err != nil {
+		return false, fmt.Errorf("failed to read job %s: %w", requestID, err)
+	}
+	return job.AllowPartialResults(), nil
+}
+
+// requestCalculationIfGathered sends the calculation event of the job once nothing is awaited for it any more.
+func (h *Handler) requestCalculationIfGathered(ctx context.Context, requestID string) error {
+	gathered, err := h.isAllDataGathered(ctx, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to verify if all data is gathered: %w", err)
+	}
+	if !gathered {
+		return nil
+	}
+	if err := h.events.Send(ctx, requestID, event.EventTypeCalculationRequested, event.SourceEFNWorker, event.NewCalculationRequestedData()); err != nil {
+		return fmt.Errorf("failed to send CalculationRequested event: %w", err)
+	}
+	return nil
+}
+
+// isJobCancelled reports whether the API consumer has cancelled the job.
+func (h *Handler) isJobCancelled(ctx context.Context, requestID string) (bool, error) {
+	job, err := h.database.GetJob(ctx, requestID)
+	if err != nil {
+		return false, fmt.Errorf("failed to read job %s: %w", requestID, err)
+	}
+	return job.Status == database.StatusCancelled, nil
+}
+
+// failJob records the failure reason on the job and sends the error notification event,
+// so that the reason is available both to the subscriber callback and to report polling.
+// The stage of failure is the status of the job when it failed.
+// Nothing is done for a cancelled job, whose final notification is requested by the API.
+func (h *Handler) failJob(ctx context.Context, requestID string, status int, message string, failure database.Failure) error {
+	job, err := h.database.GetJob(ctx, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to read job %s: %w", requestID, err)
+	}
+	if job.Status == database.StatusCancelled {
+		return nil
+	}
+	failure.Stage = job.Status
+	notificationData := event.NewNotificationErrorRequestedData(requestID, status, message)
+	if err := h.database.SetJobError(ctx, requestID, notificationData.ErrorInfo, failure); err != nil {
+		return fmt.Errorf("failed to store job error: %w", err)
+	}
+	return h.events.Send(ctx, requestID, event.EventTypeNotificationErrorRequested, event.SourceEFNWorker, notificationData)
+}
@@ -18,14 +18,26 @@ package worker
 import (
 	"context"
 	"errors"
+	"net/http"
+	"slices"
 	"testing"
+	"time"
 
+	cloudevent "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/backend"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/inline"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/calculator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/cloudobservability"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/trafficvolume"
 )
 
 type mockDatabase struct {
@@ -48,11 +60,31 @@ func (m *mockDatabase) TrySetCalculationTriggered(ctx context.Context, jobID str
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *mockDatabase) TrySetNotificationSent(ctx context.Context, jobID string) (bool, error) {
-	args := m.Called(ctx, jobID)
+func (m *mockDatabase) SetNetworkElementFailure(ctx context.Context, creationMetadata database.JobAppResultMetadata, ne database.NetworkElementID, reason string) error {
+	args := m.Called(ctx, creationMetadata, ne, reason)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) SetNetworkElementTraffic(ctx context.Context, creationMetadata database.JobAppResultMetadata, ne database.NetworkElementID, traffic, totalTraffic float64) error {
+	args := m.Called(ctx, creationMetadata, ne, traffic, totalTraffic)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) SetNetworkElementTrafficEstimate(ctx context.Context, creationMetadata database.JobAppResultMetadata, ne database.NetworkElementID, method models.EstimatedNetworkElementMethod) error {
+	args := m.Called(ctx, creationMetadata, ne, method)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) IsEventProcessed(ctx context.Context, eventID, eventType string, now time.Time) (bool, error) {
+	args := m.Called(ctx, eventID, eventType, now)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *mockDatabase) SetEventProcessed(ctx context.Context, processed database.ProcessedEvent) error {
+	args := m.Called(ctx, processed)
+	return args.Error(0)
+}
+
 func TestIsAllDataGathered(t *testing.T) {
 	app1 := uuid.New()
 	app2 := uuid.New()
@@ -185,6 +217,100 @@ func TestIsAllDataGathered(t *testing.T) {
 			}},
 			expect: false,
 		},
+		{
+			name: "failed app consumption and NE count as gathered",
+			gottenJob: &database.Job{
+				JobSpec: database.JobSpec{
+					Service: []models.AppInstanceId{app1},
+				},
+			},
+			results: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					NumberOfTotalNEs: 2,
+				},
+				Result: &database.TaskResult{
+					AppInstanceFailure: reasonEnergyUnavailable,
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							EnergyConsumption:  floatPtr(1.0),
+							AppInstanceTraffic: floatPtr(1.0),
+							TotalTraffic:       floatPtr(1.0),
+						},
+						"ne2": {
+							EnergyConsumption: floatPtr(1.0),
+							Failure:           reasonTrafficUnavailable,
+						},
+					},
+				},
+			}},
+			expect: true,
+		},
+		{
+			name: "failed NE does not make up for a missing one",
+			gottenJob: &database.Job{
+				JobSpec: database.JobSpec{
+					Service: []models.AppInstanceId{app1},
+				},
+			},
+			results: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					NumberOfTotalNEs: 2,
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							Failure: reasonEnergyUnavailable,
+						},
+					},
+				},
+			}},
+			expect: false,
+		},
+		{
+			name: "missing window of an app",
+			gottenJob: &database.Job{
+				JobSpec: database.JobSpec{
+					Service: []models.AppInstanceId{app1},
+				},
+			},
+			results: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					Window:           1,
+					NumberOfWindows:  2,
+					NumberOfTotalNEs: 0,
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+				},
+			}},
+			expect: false,
+		},
+		{
+			name: "all windows gathered",
+			gottenJob: &database.Job{
+				JobSpec: database.JobSpec{
+					Service: []models.AppInstanceId{app1},
+				},
+			},
+			results: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					NumberOfWindows: 2,
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+				},
+			}, {
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					Window:          1,
+					NumberOfWindows: 2,
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+				},
+			}},
+			expect: true,
+		},
 		{
 			name:       "db error",
 			resultsErr: errors.New("db error"),
@@ -212,6 +338,577 @@ func TestIsAllDataGathered(t *testing.T) {
 	}
 }
 
+func TestHandleCancelledJob(t *testing.T) {
+	requestID := "req1"
+	appID := uuid.NewString()
+	tests := []struct {
+		name      string
+		eventType event.EventType
+		data      any
+	}{
+		{
+			name:      "gather info",
+			eventType: event.EventTypeGatherInfoRequested,
+			data:      event.NewGatherInfoData(requestID, appID),
+		},
+		{
+			name:      "app consumption",
+			eventType: event.EventTypeAppConsumptionRequested,
+			data:      event.NewAppConsumptionData(requestID, appID, nil, "k8s", 1, event.Window{}),
+		},
+		{
+			name:      "network element energy",
+			eventType: event.EventTypeNetworkElementEnergyRequested,
+			data:      event.NewNetworkElementEnergyData(requestID, appID, "", "ne1", "router", nil, 1, event.Window{}),
+		},
+		{
+			name:      "network element traffic",
+			eventType: event.EventTypeNetworkElementTrafficRequested,
+			data:      event.NewNetworkElementTrafficData(requestID, appID, nil, nil, []event.NetworkElementInfo{{NEInstanceID: "ne1"}}, event.Window{}),
+		},
+		{
+			name:      "calculation",
+			eventType: event.EventTypeCalculationRequested,
+			data:      event.NewCalculationRequestedData(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mockDatabase{}
+			db.On("GetJob", mock.Anything, requestID).Return(&database.Job{Status: database.StatusCancelled}, nil)
+			// No backend, calculator or event sender is set: any call past the cancellation check panics.
+			h := &Handler{database: db}
+
+			e := cloudevent.NewEvent()
+			e.SetID(requestID)
+			e.SetType(tt.eventType.String())
+			assert.NoError(t, e.SetData(cloudevent.ApplicationJSON, tt.data))
+
+			_, err := h.Handle(context.Background(), e)
+			assert.NoError(t, err)
+			db.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleCalculationOfFinalJob(t *testing.T) {
+	// The watchdog failed the job, or the event is redelivered once the job completed: the transition to
+	// calculating is refused and nothing is calculated or notified again.
+	for _, status := range []database.Status{database.StatusFailed, database.StatusCompleted} {
+		t.Run(string(status), func(t *testing.T) {
+			db := &mockDatabase{}
+			db.On("GetJob", mock.Anything, "req1").Return(&database.Job{Status: status}, nil)
+			db.On("SetJobStatus", mock.Anything, "req1", database.StatusCalculating).Return(false, nil)
+			sender := &mockSender{}
+			h := &Handler{database: db, events: sender}
+
+			e := cloudevent.NewEvent()
+			e.SetID("req1")
+			e.SetType(event.EventTypeCalculationRequested.String())
+
+			_, err := h.Handle(context.Background(), e)
+			assert.NoError(t, err)
+			db.AssertCalled(t, "SetJobStatus", mock.Anything, "req1", database.StatusCalculating)
+			db.AssertNotCalled(t, "GetAllJobAppResults", mock.Anything, mock.Anything)
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandleNetworkElementEnergyFailure(t *testing.T) {
+	requestID := "req1"
+	appID := uuid.New()
+	allow := true
+	partialJob := &database.Job{JobSpec: database.JobSpec{
+		Service:             []models.AppInstanceId{appID},
+		SubscriptionRequest: models.SubscriptionRequest{Config: models.Config{SubscriptionDetail: models.CreateSubscriptionDetail{AllowPartialResults: &allow}}},
+	}}
+	strictJob := &database.Job{JobSpec: database.JobSpec{Service: []models.AppInstanceId{appID}}, Status: database.StatusGathering}
+	resolved := []database.JobAppResult{{
+		JobAppResultMetadata: database.JobAppResultMetadata{JobID: requestID, AppID: appID.String(), NumberOfTotalNEs: 1},
+		Result: &database.TaskResult{
+			AppInstanceEnergyConsumption: floatPtr(1.0),
+			NetworkElements: map[string]database.NetworkElementResult{
+				"ne1": {AppInstanceTraffic: floatPtr(1.0), TotalTraffic: floatPtr(1.0), Failure: reasonEnergyUnavailable},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		job         *database.Job
+		allowGlobal bool
+		expectEvent event.EventType
+	}{
+		{
+			name:        "fails the job by default",
+			job:         strictJob,
+			expectEvent: event.EventTypeNotificationErrorRequested,
+		},
+		{
+			name:        "marks the NE as failed when the request allows partial results",
+			job:         partialJob,
+			expectEvent: event.EventTypeCalculationRequested,
+		},
+		{
+			name:        "marks the NE as failed when partial results are allowed for all jobs",
+			job:         strictJob,
+			allowGlobal: true,
+			expectEvent: event.EventTypeCalculationRequested,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CLOUDOBS_FAIL_NE", "true")
+			cloudObs, _ := cloudobservability.NewErrorDummyClient()
+			db := &mockDatabase{}
+			db.On("GetJob", mock.Anything, requestID).Return(tt.job, nil)
+			db.On("SetJobError", mock.Anything, requestID, mock.Anything, mock.Anything).Return(nil)
+			db.On("SetNetworkElementFailure", mock.Anything, mock.Anything, database.NetworkElementID{NEInstanceID: "ne1"}, reasonEnergyUnavailable).Return(nil)
+			db.On("GetAllJobAppResults", mock.Anything, requestID).Return(resolved, nil)
+			db.On("TrySetCalculationTriggered", mock.Anything, requestID).Return(true, nil)
+			sender := &mockSender{}
+			sender.On("Send", mock.Anything, requestID, tt.expectEvent, event.SourceEFNWorker, mock.Anything).Return(nil).Once()
+			h := &Handler{database: db, cloudObservability: cloudObs, events: sender, allowPartialResults: tt.allowGlobal}
+
+			e := cloudevent.NewEvent()
+			e.SetID(requestID)
+			e.SetType(event.EventTypeNetworkElementEnergyRequested.String())
+			assert.NoError(t, e.SetData(cloudevent.ApplicationJSON, event.NewNetworkElementEnergyData(requestID, appID.String(), "", "ne1", "router", nil, 1, event.Window{})))
+
+			_, err := h.Handle(context.Background(), e)
+			assert.NoError(t, err)
+			sender.AssertExpectations(t)
+			if tt.expectEvent == event.EventTypeCalculationRequested {
+				db.AssertCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, database.NetworkElementID{NEInstanceID: "ne1"}, reasonEnergyUnavailable)
+			} else {
+				db.AssertNotCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+type unavailableCloudObservability struct {
+	cloudobservability.Interface
+}
+
+func (unavailableCloudObservability) RetrieveNetworkElementEnergyConsumption(context.Context, string, string, *models.TimePeriod, string) (*float64, error) {
+	return nil, datasource.NewRetryable("cloud-observability backend unavailable: circuit breaker open", time.Minute)
+}
+
+func TestHandleNetworkElementEnergyUnavailable(t *testing.T) {
+	requestID := "req1"
+	appID := uuid.New()
+	job := &database.Job{JobSpec: database.JobSpec{Service: []models.AppInstanceId{appID}}, Status: database.StatusGathering}
+
+	db := &mockDatabase{}
+	db.On("GetJob", mock.Anything, requestID).Return(job, nil)
+	sender := &mockSender{}
+	h := &Handler{database: db, cloudObservability: unavailableCloudObservability{}, events: sender, allowPartialResults: true}
+
+	e := cloudevent.NewEvent()
+	e.SetID(requestID)
+	e.SetType(event.EventTypeNetworkElementEnergyRequested.String())
+	assert.NoError(t, e.SetData(cloudevent.ApplicationJSON, event.NewNetworkElementEnergyData(requestID, appID.String(), "", "ne1", "router", nil, 1, event.Window{})))
+
+	// The event is retried later on: the job is neither failed nor calculated without the network element.
+	_, err := h.Handle(context.Background(), e)
+	assert.True(t, datasource.IsRetryable(err))
+	sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	db.AssertNotCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+type failingTrafficVolume struct {
+	err error
+}
+
+func (f failingTrafficVolume) RetrieveTrafficVolumes(context.Context, []string, []trafficvolume.NetworkElement, *models.TimePeriod) (*trafficvolume.TrafficVolumeMeasureList, error) {
+	return nil, f.err
+}
+
+func TestHandleNetworkElementTrafficErrors(t *testing.T) {
+	requestID := "req1"
+	appID := uuid.New()
+	strictJob := &database.Job{JobSpec: database.JobSpec{Service: []models.AppInstanceId{appID}}, Status: database.StatusGathering}
+
+	tests := []struct {
+		name          string
+		err           error
+		allowPartial  bool
+		expectRetry   bool
+		expectEvent   event.EventType
+		expectStatus  int
+		expectCode    string
+		expectMissing bool
+	}{
+		{
+			name:        "retries a retryable error",
+			err:         datasource.NewThrottled("slow down", time.Second),
+			expectRetry: true,
+		},
+		{
+			name:        "retries an unclassified error",
+			err:         errors.New("connection reset"),
+			expectRetry: true,
+		},
+		{
+			name:         "fails the job on a permanent error",
+			err:          datasource.NewPermanent("no traffic data"),
+			expectEvent:  event.EventTypeNotificationErrorRequested,
+			expectStatus: 500,
+			expectCode:   "INTERNAL",
+		},
+		{
+			name:         "fails the job with a not found error",
+			err:          datasource.NewNotFound("unknown network element"),
+			expectEvent:  event.EventTypeNotificationErrorRequested,
+			expectStatus: 404,
+			expectCode:   "NOT_FOUND",
+		},
+		{
+			name:         "fails the job with a permission error",
+			err:          datasource.NewUnauthorized("token rejected"),
+			expectEvent:  event.EventTypeNotificationErrorRequested,
+			expectStatus: 403,
+			expectCode:   "PERMISSION_DENIED",
+		},
+		{
+			name:          "marks the traffic missing when partial results are allowed",
+			err:           datasource.NewPermanent("no traffic data"),
+			allowPartial:  true,
+			expectEvent:   event.EventTypeCalculationRequested,
+			expectMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mockDatabase{}
+			db.On("GetJob", mock.Anything, requestID).Return(strictJob, nil)
+			db.On("SetJobError", mock.Anything, requestID, mock.Anything, mock.Anything).Return(nil)
+			db.On("SetNetworkElementFailure", mock.Anything, mock.Anything, mock.Anything, reasonTrafficUnavailable).Return(nil)
+			db.On("GetAllJobAppResults", mock.Anything, requestID).Return([]database.JobAppResult{}, nil)
+			sender := &mockSender{}
+			if tt.expectEvent != "" {
+				sender.On("Send", mock.Anything, requestID, tt.expectEvent, event.SourceEFNWorker, mock.Anything).Return(nil).Once()
+			}
+			h := &Handler{database: db, trafficVolume: failingTrafficVolume{err: tt.err}, events: sender, allowPartialResults: tt.allowPartial}
+			if tt.expectMissing {
+				// Both network elements are resolved once marked.
+				db.ExpectedCalls = slices.DeleteFunc(db.ExpectedCalls, func(c *mock.Call) bool { return c.Method == "GetAllJobAppResults" })
+				db.On("GetAllJobAppResults", mock.Anything, requestID).Return([]database.JobAppResult{{
+					JobAppResultMetadata: database.JobAppResultMetadata{JobID: requestID, AppID: appID.String(), NumberOfTotalNEs: 2},
+					Result: &database.TaskResult{
+						AppInstanceEnergyConsumption: floatPtr(1.0),
+						NetworkElements: map[string]database.NetworkElementResult{
+							"ne1": {EnergyConsumption: floatPtr(1.0), Failure: reasonTrafficUnavailable},
+							"ne2": {EnergyConsumption: floatPtr(1.0), Failure: reasonTrafficUnavailable},
+						},
+					},
+				}}, nil)
+				db.On("TrySetCalculationTriggered", mock.Anything, requestID).Return(true, nil)
+			}
+
+			e := cloudevent.NewEvent()
+			e.SetID(requestID)
+			e.SetType(event.EventTypeNetworkElementTrafficRequested.String())
+			networkElements := []event.NetworkElementInfo{{NEInstanceID: "ne1", VendorID: "vendor"}, {NEInstanceID: "ne2", VendorID: "vendor"}}
+			assert.NoError(t, e.SetData(cloudevent.ApplicationJSON, event.NewNetworkElementTrafficData(requestID, appID.String(), []string{"10.0.0.1"}, nil, networkElements, event.Window{})))
+
+			_, err := h.Handle(context.Background(), e)
+			if tt.expectRetry {
+				assert.ErrorIs(t, err, tt.err)
+				sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			sender.AssertExpectations(t)
+			if tt.expectStatus != 0 {
+				db.AssertCalled(t, "SetJobError", mock.Anything, requestID, mock.MatchedBy(func(info models.ErrorInfo) bool {
+					return info.Status == tt.expectStatus && info.Code == tt.expectCode
+				}), mock.Anything)
+			}
+			if tt.expectMissing {
+				db.AssertNumberOfCalls(t, "SetNetworkElementFailure", 2)
+			} else {
+				db.AssertNotCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+type partialTrafficVolume struct {
+	measures []trafficvolume.TrafficVolumeMeasure
+}
+
+func (p partialTrafficVolume) RetrieveTrafficVolumes(context.Context, []string, []trafficvolume.NetworkElement, *models.TimePeriod) (*trafficvolume.TrafficVolumeMeasureList, error) {
+	return &trafficvolume.TrafficVolumeMeasureList{TrafficVolumeMeasureList: p.measures}, nil
+}
+
+func TestHandleNetworkElementTrafficMissing(t *testing.T) {
+	requestID := "req1"
+	appID := uuid.New()
+	job := &database.Job{JobSpec: database.JobSpec{Service: []models.AppInstanceId{appID}}, Status: database.StatusGathering}
+	// ne2 is measured for another vendor only: it is missing as much as ne3.
+	tv := partialTrafficVolume{measures: []trafficvolume.TrafficVolumeMeasure{
+		{NetworkElement: trafficvolume.NetworkElement{VendorIdentifier: "vendor", NEIdentifier: "ne1"}, TrafficVolumeIP: 10, TrafficVolumeAll: 100},
+		{NetworkElement: trafficvolume.NetworkElement{VendorIdentifier: "other", NEIdentifier: "ne2"}, TrafficVolumeIP: 20, TrafficVolumeAll: 200},
+	}}
+
+	tests := []struct {
+		name         string
+		method       models.EstimatedNetworkElementMethod
+		allowPartial bool
+		expectFail   bool
+	}{
+		{name: "estimates a zero share", method: models.Zero},
+		{name: "estimates an equal split", method: models.EqualSplit},
+		{name: "marks the traffic missing when partial results are allowed", allowPartial: true},
+		{name: "fails the job without retry when no estimate is configured", expectFail: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mockDatabase{}
+			db.On("GetJob", mock.Anything, requestID).Return(job, nil)
+			db.On("GetAllJobAppResults", mock.Anything, requestID).Return([]database.JobAppResult{}, nil)
+			db.On("SetNetworkElementTraffic", mock.Anything, mock.Anything, database.NetworkElementID{VendorID: "vendor", NEInstanceID: "ne1"}, 10.0, 100.0).Return(nil).Once()
+			db.On("SetNetworkElementTrafficEstimate", mock.Anything, mock.Anything, mock.Anything, tt.method).Return(nil)
+			db.On("SetNetworkElementFailure", mock.Anything, mock.Anything, mock.Anything, reasonTrafficUnavailable).Return(nil)
+			db.On("SetJobError", mock.Anything, requestID, mock.Anything, mock.Anything).Return(nil)
+			sender := &mockSender{}
+			if tt.expectFail {
+				sender.On("Send", mock.Anything, requestID, event.EventTypeNotificationErrorRequested, event.SourceEFNWorker, mock.Anything).Return(nil).Once()
+			}
+			h := &Handler{database: db, trafficVolume: tv, events: sender, allowPartialResults: tt.allowPartial, missingTraffic: tt.method}
+
+			e := cloudevent.NewEvent()
+			e.SetID(requestID)
+			e.SetType(event.EventTypeNetworkElementTrafficRequested.String())
+			networkElements := []event.NetworkElementInfo{
+				{NEInstanceID: "ne1", VendorID: "vendor"},
+				{NEInstanceID: "ne2", VendorID: "vendor"},
+				{NEInstanceID: "ne3", VendorID: "vendor"},
+			}
+			assert.NoError(t, e.SetData(cloudevent.ApplicationJSON, event.NewNetworkElementTrafficData(requestID, appID.String(), []string{"10.0.0.1"}, nil, networkElements, event.Window{})))
+
+			_, err := h.Handle(context.Background(), e)
+			db.AssertCalled(t, "SetNetworkElementTraffic", mock.Anything, mock.Anything, database.NetworkElementID{VendorID: "vendor", NEInstanceID: "ne1"}, 10.0, 100.0)
+			assert.NoError(t, err)
+			if tt.expectFail {
+				sender.AssertExpectations(t)
+				db.AssertCalled(t, "SetJobError", mock.Anything, requestID, mock.MatchedBy(func(info models.ErrorInfo) bool {
+					return info.Status == http.StatusNotFound
+				}), mock.MatchedBy(func(failure database.Failure) bool {
+					return failure.Cause == database.FailureCauseBackendError
+				}))
+				db.AssertNotCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				db.AssertNotCalled(t, "SetNetworkElementTrafficEstimate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			if tt.method != "" {
+				db.AssertCalled(t, "SetNetworkElementTrafficEstimate", mock.Anything, mock.Anything, database.NetworkElementID{VendorID: "vendor", NEInstanceID: "ne2"}, tt.method)
+				db.AssertCalled(t, "SetNetworkElementTrafficEstimate", mock.Anything, mock.Anything, database.NetworkElementID{VendorID: "vendor", NEInstanceID: "ne3"}, tt.method)
+				db.AssertNotCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				db.AssertCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, database.NetworkElementID{VendorID: "vendor", NEInstanceID: "ne2"}, reasonTrafficUnavailable)
+				db.AssertCalled(t, "SetNetworkElementFailure", mock.Anything, mock.Anything, database.NetworkElementID{VendorID: "vendor", NEInstanceID: "ne3"}, reasonTrafficUnavailable)
+			}
+		})
+	}
+}
+
+// memoryDatabase keeps a job and its results in memory, for the worker to go through a whole job.
+type memoryDatabase struct {
+	database.Interface
+	job                  database.Job
+	results              []database.JobAppResult
+	calculationTriggered bool
+}
+
+func (m *memoryDatabase) GetJob(_ context.Context, _ string) (*database.Job, error) {
+	job := m.job
+	return &job, nil
+}
+
+func (m *memoryDatabase) SetJobStatus(_ context.Context, _ string, status database.Status) (bool, error) {
+	if !database.CanTransition(m.job.Status, status) {
+		return false, nil
+	}
+	m.job.Status = status
+	return true, nil
+}
+
+func (m *memoryDatabase) SetJobResult(_ context.Context, _ string, result float64, breakdown *models.ResultBreakdown, _ *models.DataCoverage) error {
+	m.job.Result = &result
+	m.job.Breakdown = breakdown
+	m.job.Status = database.StatusNotifying
+	return nil
+}
+
+func (m *memoryDatabase) TrySetCalculationTriggered(_ context.Context, _ string) (bool, error) {
+	triggered := !m.calculationTriggered
+	m.calculationTriggered = true
+	return triggered, nil
+}
+
+func (m *memoryDatabase) GetAllJobAppResults(_ context.Context, _ string) ([]database.JobAppResult, error) {
+	return slices.Clone(m.results), nil
+}
+
+// result returns the result of the application instance and window of metadata, created if missing.
+func (m *memoryDatabase) result(metadata database.JobAppResultMetadata) *database.TaskResult {
+	for i, r := range m.results {
+		if r.AppID == metadata.AppID && r.Window == metadata.Window {
+			return m.results[i].Result
+		}
+	}
+	result := &database.TaskResult{NetworkElements: map[string]database.NetworkElementResult{}}
+	m.results = append(m.results, database.JobAppResult{JobAppResultMetadata: metadata, Result: result})
+	return result
+}
+
+func (m *memoryDatabase) CreateOrUpdateApplicationResult(_ context.Context, metadata database.JobAppResultMetadata, consumption float64) error {
+	m.result(metadata).AppInstanceEnergyConsumption = &consumption
+	return nil
+}
+
+func (m *memoryDatabase) SetNetworkElementEnergy(_ context.Context, metadata database.JobAppResultMetadata, id database.NetworkElementID, consumption float64) error {
+	result := m.result(metadata)
+	ne := result.NetworkElements[id.Key()]
+	ne.NetworkElementID = id
+	ne.EnergyConsumption = &consumption
+	result.NetworkElements[id.Key()] = ne
+	return nil
+}
+
+func (m *memoryDatabase) SetNetworkElementTraffic(_ context.Context, metadata database.JobAppResultMetadata, id database.NetworkElementID, traffic, totalTraffic float64) error {
+	result := m.result(metadata)
+	ne := result.NetworkElements[id.Key()]
+	ne.NetworkElementID = id
+	ne.AppInstanceTraffic = &traffic
+	ne.TotalTraffic = &totalTraffic
+	result.NetworkElements[id.Key()] = ne
+	return nil
+}
+
+// queueSender queues the events sent, for the test to deliver them to the worker.
+type queueSender struct {
+	events []cloudevent.Event
+}
+
+func (q *queueSender) Send(_ context.Context, id string, eventType event.EventType, source event.Source, data any, _ ...event.Option) error {
+	e := cloudevent.NewEvent()
+	e.SetID(id)
+	e.SetType(eventType.String())
+	e.SetSource(source.String())
+	if err := e.SetData(cloudevent.ApplicationJSON, data); err != nil {
+		return err
+	}
+	q.events = append(q.events, e)
+	return nil
+}
+
+// windowedBackend measures an hourly energy consumption, and a share of the traffic of the network
+// element that is higher past the first window, so that a value depends on the windows it is summed over.
+type windowedBackend struct {
+	start time.Time
+}
+
+func (b windowedBackend) hours(timePeriod *models.TimePeriod) float64 {
+	return timePeriod.EndDate.Sub(timePeriod.StartDate).Hours()
+}
+
+func (b windowedBackend) RetrieveAppEnergyConsumption(_ context.Context, _ string, timePeriod *models.TimePeriod, _ string) (*float64, error) {
+	v := b.hours(timePeriod)
+	return &v, nil
+}
+
+func (b windowedBackend) RetrieveNetworkElementEnergyConsumption(_ context.Context, _, _ string, timePeriod *models.TimePeriod, _ string) (*float64, error) {
+	v := 10 * b.hours(timePeriod)
+	return &v, nil
+}
+
+func (b windowedBackend) RetrieveTrafficVolumes(_ context.Context, _ []string, networkElements []trafficvolume.NetworkElement, timePeriod *models.TimePeriod) (*trafficvolume.TrafficVolumeMeasureList, error) {
+	share := 0.1
+	if timePeriod.StartDate.After(b.start) {
+		share = 0.4
+	}
+	total := 100 * b.hours(timePeriod)
+	measures := make([]trafficvolume.TrafficVolumeMeasure, 0, len(networkElements))
+	for _, ne := range networkElements {
+		measures = append(measures, trafficvolume.TrafficVolumeMeasure{NetworkElement: ne, TrafficVolumeIP: share * total, TrafficVolumeAll: total})
+	}
+	return &trafficvolume.TrafficVolumeMeasureList{TrafficVolumeMeasureList: measures}, nil
+}
+
+func TestInlineMatchesWorkerOverWindows(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(60 * time.Hour)
+	requestID := "req1"
+	job := database.Job{
+		JobSpec: database.JobSpec{
+			RequestId:   &requestID,
+			RequestKind: database.RequestKindCarbonFootprint,
+			Service:     []models.AppInstanceId{uuid.New(), uuid.New()},
+			TimePeriod:  &models.TimePeriod{StartDate: start, EndDate: &end},
+		},
+		Status: database.StatusCreated,
+	}
+	windowSize := 24 * time.Hour
+	assert.Len(t, job.TimeWindows(windowSize), 3)
+
+	// Both application instances are served by the same network element.
+	backends := windowedBackend{start: start}
+	orch := &mockOrchestrator{info: orchestrator.Information{
+		App: orchestrator.ApplicationInstance{IPList: []string{"10.0.0.1"}},
+		NE:  []orchestrator.NEInfo{{InstanceID: "ne1", VendorID: "vendor"}},
+	}}
+	calc := calculator.NewSimpleClient(0.5)
+
+	// Asynchronous path: the worker handles the events of the job until its result is stored.
+	db := &memoryDatabase{job: job}
+	sender := &queueSender{}
+	h := &Handler{
+		calculator:         calc,
+		cloudObservability: backends,
+		trafficVolume:      backends,
+		database:           db,
+		events:             sender,
+		orchestrator:       orch,
+		windowSize:         windowSize,
+	}
+	for _, appInstanceID := range job.Service {
+		assert.NoError(t, sender.Send(context.Background(), requestID, event.EventTypeGatherInfoRequested, event.SourceEFNWorker, event.NewGatherInfoData(requestID, appInstanceID.String())))
+	}
+	for len(sender.events) > 0 {
+		e := sender.events[0]
+		sender.events = sender.events[1:]
+		if e.Type() == event.EventTypeNotificationRequested.String() {
+			continue
+		}
+		_, err := h.Handle(context.Background(), e)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, database.StatusNotifying, db.job.Status)
+	assert.Len(t, db.results, 2*3)
+
+	// Synchronous path: the same backends are called by the inline runner.
+	clients := &backend.Clients{Orchestrator: orch, CloudObservability: backends, TrafficVolume: backends, Calculator: calc}
+	result, err := inline.NewRunner(clients, windowSize).Run(context.Background(), &job)
+	assert.NoError(t, err)
+	if assert.NotNil(t, db.job.Result) {
+		assert.InDelta(t, *db.job.Result, result.Value, 1e-9)
+	}
+
+	// Over the whole period at once, the traffic shares of the windows are not told apart.
+	whole, err := inline.NewRunner(clients, 0).Run(context.Background(), &job)
+	assert.NoError(t, err)
+	assert.NotEqual(t, result.Value, whole.Value)
+}
+
 func floatPtr(f float64) *float64 {
 	return &f
 }
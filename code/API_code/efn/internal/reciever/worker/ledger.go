@@ -0,0 +1,71 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package worker
+
+import (
+	"context"
+	"time"
+
+	cloudevent "github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+)
+
+// ledgeredEvents lists the events recorded in the ledger once handled. Their deterministic IDs identify
+// the data of an application instance over a window, so a redelivery asks the backends for data already
+// stored. A calculation is requested again under the same ID by the watchdog when it did not complete,
+// and schedule ticks are not bound to a job: neither is recorded.
+var ledgeredEvents = map[string]bool{
+	event.EventTypeGatherInfoRequested.String():            true,
+	event.EventTypeAppConsumptionRequested.String():        true,
+	event.EventTypeNetworkElementEnergyRequested.String():  true,
+	event.EventTypeNetworkElementTrafficRequested.String(): true,
+}
+
+// isProcessed reports whether e has already been handled. The ledger only saves backend calls: when it
+// cannot be read, the event is handled again.
+func (h *Handler) isProcessed(ctx context.Context, e cloudevent.Event) bool {
+	if h.ledgerTTL <= 0 || !ledgeredEvents[e.Type()] {
+		return false
+	}
+	processed, err := h.database.IsEventProcessed(ctx, e.ID(), e.Type(), time.Now().UTC())
+	if err != nil {
+		logger.FromContext(ctx).With(zap.Error(err), zap.String("eventID", e.ID())).Warn("Failed to read processed events ledger, handling event")
+		return false
+	}
+	return processed
+}
+
+// markProcessed records e in the ledger once handled. Failing to do so only costs the backend calls of
+// a redelivery, so the event is still acknowledged.
+func (h *Handler) markProcessed(ctx context.Context, e cloudevent.Event) {
+	if h.ledgerTTL <= 0 || !ledgeredEvents[e.Type()] {
+		return
+	}
+	now := time.Now().UTC()
+	processed := database.ProcessedEvent{
+		EventID:     e.ID(),
+		EventType:   e.Type(),
+		ProcessedAt: now,
+		ExpiresAt:   now.Add(h.ledgerTTL),
+	}
+	if err := h.database.SetEventProcessed(ctx, processed); err != nil {
+		logger.FromContext(ctx).With(zap.Error(err), zap.String("eventID", e.ID())).Warn("Failed to record event in processed events ledger")
+	}
+}
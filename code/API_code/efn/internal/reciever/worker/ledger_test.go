@@ -0,0 +1,126 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevent "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+)
+
+func TestHandleProcessedEventsLedger(t *testing.T) {
+	requestID := "req1"
+	appID := uuid.New()
+	eventID := event.EventIDForNE(requestID, appID.String(), "", "ne1", 0)
+	eventType := event.EventTypeNetworkElementEnergyRequested.String()
+	gathering := &database.Job{JobSpec: database.JobSpec{Service: []models.AppInstanceId{appID}}, Status: database.StatusGathering}
+	cancelled := &database.Job{JobSpec: database.JobSpec{Service: []models.AppInstanceId{appID}}, Status: database.StatusCancelled}
+
+	tests := []struct {
+		name         string
+		processed    bool
+		ledgerErr    error
+		job          *database.Job
+		expectCalled bool
+		expectRecord bool
+	}{
+		{
+			name:      "skips an event already processed",
+			processed: true,
+		},
+		{
+			name:         "records an event once handled",
+			job:          cancelled,
+			expectCalled: true,
+			expectRecord: true,
+		},
+		{
+			name:         "does not record an event to retry",
+			job:          gathering,
+			expectCalled: true,
+		},
+		{
+			name:         "handles the event when the ledger cannot be read",
+			ledgerErr:    errors.New("connection refused"),
+			job:          cancelled,
+			expectCalled: true,
+			expectRecord: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mockDatabase{}
+			db.On("IsEventProcessed", mock.Anything, eventID, eventType, mock.Anything).Return(tt.processed, tt.ledgerErr)
+			db.On("SetEventProcessed", mock.Anything, mock.Anything).Return(nil)
+			if tt.job != nil {
+				db.On("GetJob", mock.Anything, requestID).Return(tt.job, nil)
+			}
+			h := &Handler{database: db, cloudObservability: unavailableCloudObservability{}, events: &mockSender{}, ledgerTTL: time.Hour}
+
+			e := cloudevent.NewEvent()
+			e.SetID(eventID)
+			e.SetType(eventType)
+			assert.NoError(t, e.SetData(cloudevent.ApplicationJSON, event.NewNetworkElementEnergyData(requestID, appID.String(), "", "ne1", "router", nil, 1, event.Window{})))
+
+			_, err := h.Handle(context.Background(), e)
+			if tt.job == gathering {
+				assert.True(t, datasource.IsRetryable(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectCalled {
+				db.AssertCalled(t, "GetJob", mock.Anything, requestID)
+			} else {
+				db.AssertNotCalled(t, "GetJob", mock.Anything, mock.Anything)
+			}
+			if tt.expectRecord {
+				db.AssertCalled(t, "SetEventProcessed", mock.Anything, mock.MatchedBy(func(p database.ProcessedEvent) bool {
+					return p.EventID == eventID && p.EventType == eventType && p.ExpiresAt.Sub(p.ProcessedAt) == time.Hour
+				}))
+			} else {
+				db.AssertNotCalled(t, "SetEventProcessed", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestHandleCalculationNotLedgered(t *testing.T) {
+	db := &mockDatabase{}
+	db.On("GetJob", mock.Anything, "req1").Return(&database.Job{Status: database.StatusCancelled}, nil)
+	h := &Handler{database: db, ledgerTTL: time.Hour}
+
+	e := cloudevent.NewEvent()
+	e.SetID("req1")
+	e.SetType(event.EventTypeCalculationRequested.String())
+
+	// The watchdog requests a calculation that did not complete again under the same ID.
+	_, err := h.Handle(context.Background(), e)
+	assert.NoError(t, err)
+	db.AssertNotCalled(t, "IsEventProcessed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	db.AssertNotCalled(t, "SetEventProcessed", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+)
+
+// watchdogLease is the name of the lease electing the replica that sweeps the stuck jobs.
+const watchdogLease = "worker-watchdog"
+
+// instanceID returns an identifier of this replica, unique even when replicas share a host name.
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	return host + "-" + uuid.NewString()
+}
+
+// RunWatchdog sweeps the stuck jobs at every interval of the watchdog configuration until ctx is done.
+// All replicas run it; the sweep is performed by the one holding the watchdog lease.
+func (h *Handler) RunWatchdog(ctx context.Context) {
+	log := logger.FromContext(ctx)
+	if h.watchdog.Interval <= 0 {
+		log.Info("Stuck jobs watchdog disabled")
+		return
+	}
+	ticker := time.NewTicker(h.watchdog.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.SweepStuckJobs(ctx, time.Now().UTC()); err != nil {
+				log.With(zap.Error(err)).Error("Failed to sweep stuck jobs")
+			}
+		}
+	}
+}
+
+// SweepStuckJobs resumes or fails the jobs still in progress after the deadline, if this replica holds
+// the watchdog lease. The lease outlives two intervals, so that another replica takes over when the
+// holder stops sweeping.
+func (h *Handler) SweepStuckJobs(ctx context.Context, now time.Time) error {
+	log := logger.FromContext(ctx)
+	held, err := h.database.AcquireLease(ctx, watchdogLease, h.instanceID, now, 2*h.watchdog.Interval)
+	if err != nil {
+		return fmt.Errorf("failed to acquire watchdog lease: %w", err)
+	}
+	if !held {
+		log.Debug("Watchdog lease held by another replica, skipping sweep")
+		return nil
+	}
+
+	jobs, err := h.database.ListStuckJobs(ctx, now.Add(-h.watchdog.Deadline), h.watchdog.BatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list stuck jobs: %w", err)
+	}
+	for i := range jobs {
+		job := &jobs[i]
+		jobCtx := correlator.NewContext(ctx, job.XCorrelator)
+		if err := h.recoverJob(jobCtx, job, now); err != nil {
+			log.With(zap.Error(err), zap.String("requestID", *job.RequestId)).Error("Failed to recover stuck job")
+		}
+	}
+	if len(jobs) > 0 {
+		log.With(zap.Int("jobs", len(jobs))).Info("Swept stuck jobs")
+	}
+	return nil
+}
+
+// recoverJob sends again the events a stuck job is waiting for, or fails it with a timeout once it has
+// been resumed as many times as allowed.
+func (h *Handler) recoverJob(ctx context.Context, job *database.Job, now time.Time) error {
+	requestID := *job.RequestId
+	log := logger.FromContext(ctx).With(zap.String("requestID", requestID), zap.String("status", string(job.Status)), zap.Int("resumes", job.Resumes))
+
+	if job.Resumes >= h.watchdog.MaxResumes {
+		log.Warn("Job did not complete in time, failing it")
+		message := fmt.Sprintf("Report did not complete within %s", now.Sub(job.CreatedAt).Round(time.Second))
+		return h.failJob(ctx, requestID, http.StatusGatewayTimeout, message, database.Failure{Cause: database.FailureCauseTimeout})
+	}
+
+	switch {
+	case job.Status == database.StatusNotifying:
+		if job.Result == nil {
+			return fmt.Errorf("job %s is notifying without result", requestID)
+		}
+		data := event.NewNotificationRequestedData(requestID, *job.Result)
+		if err := h.events.Send(ctx, requestID, event.EventTypeNotificationRequested, event.SourceEFNWorker, data); err != nil {
+			return fmt.Errorf("failed to send NotificationRequested event: %w", err)
+		}
+	case job.CalculationTriggered:
+		if err := h.events.Send(ctx, requestID, event.EventTypeCalculationRequested, event.SourceEFNWorker, event.NewCalculationRequestedData()); err != nil {
+			return fmt.Errorf("failed to send CalculationRequested event: %w", err)
+		}
+	default:
+		if err := h.requestMissingData(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	if err := h.database.MarkJobResumed(ctx, requestID, now); err != nil {
+		return fmt.Errorf("failed to record resume of job %s: %w", requestID, err)
+	}
+	log.Info("Resumed stuck job")
+	return nil
+}
+
+// requestMissingData sends the events of the data not gathered yet for each application instance of job.
+// When everything has been gathered, the calculation that was never triggered is requested.
+func (h *Handler) requestMissingData(ctx context.Context, job *database.Job) error {
+	requestID := *job.RequestId
+	if database.CanTransition(job.Status, database.StatusGathering) {
+		if _, err := h.database.SetJobStatus(ctx, requestID, database.StatusGathering); err != nil {
+			return fmt.Errorf("failed to move job %s to gathering: %w", requestID, err)
+		}
+	}
+
+	results, err := h.database.GetAllJobAppResults(ctx, requestID)
+	if err != nil {
+		return fmt.Errorf("failed to read results of job %s: %w", requestID, err)
+	}
+	byApp := make(map[string][]database.JobAppResult, len(job.Service))
+	for _, r := range results {
+		byApp[r.AppID] = append(byApp[r.AppID], r)
+	}
+
+	windows := len(job.TimeWindows(h.windowSize))
+	missing := 0
+	for _, appInstanceID := range job.Service {
+		appID := appInstanceID.String()
+		if isAppResolved(byApp[appID], windows) {
+			continue
+		}
+		missing++
+		info, err := h.orchestrator.GatherInformation(ctx, appID)
+		if err != nil {
+			return fmt.Errorf("failed to gather application instance information with ID %s: %w", appID, err)
+		}
+		if err = h.requestAppData(ctx, job, appID, info, byApp[appID]); err != nil {
+			return err
+		}
+	}
+	if missing > 0 {
+		return nil
+	}
+
+	triggered, err := h.isAllDataGathered(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if triggered {
+		if err = h.events.Send(ctx, requestID, event.EventTypeCalculationRequested, event.SourceEFNWorker, event.NewCalculationRequestedData()); err != nil {
+			return fmt.Errorf("failed to send CalculationRequested event: %w", err)
+		}
+	}
+	return nil
+}
+
+// isAppResolved reports whether nothing is awaited any more for an application instance, whose results
+// over every one of the windows of the job have been stored and resolved.
+func isAppResolved(results []database.JobAppResult, windows int) bool {
+	if len(results) != windows {
+		return false
+	}
+	for _, r := range results {
+		if !r.IsResolved() {
+			return false
+		}
+	}
+	return true
+}
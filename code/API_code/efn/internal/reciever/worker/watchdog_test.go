@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/orchestrator"
+)
+
+func (m *mockDatabase) AcquireLease(ctx context.Context, name, holder string, now time.Time, duration time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, now, duration)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockDatabase) ListStuckJobs(ctx context.Context, before time.Time, limit int) ([]database.Job, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).([]database.Job), args.Error(1)
+}
+
+func (m *mockDatabase) MarkJobResumed(ctx context.Context, jobID string, at time.Time) error {
+	args := m.Called(ctx, jobID, at)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) SetJobStatus(ctx context.Context, jobID string, status database.Status) (bool, error) {
+	args := m.Called(ctx, jobID, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockDatabase) SetJobError(ctx context.Context, jobID string, errorInfo models.ErrorInfo, failure database.Failure) error {
+	args := m.Called(ctx, jobID, errorInfo, failure)
+	return args.Error(0)
+}
+
+type mockSender struct {
+	mock.Mock
+}
+
+func (m *mockSender) Send(ctx context.Context, requestID string, eventType event.EventType, source event.Source, data any, opts ...event.Option) error {
+	args := m.Called(ctx, requestID, eventType, source, data)
+	return args.Error(0)
+}
+
+type mockOrchestrator struct {
+	info orchestrator.Information
+}
+
+func (m *mockOrchestrator) GatherInformation(_ context.Context, _ string) (orchestrator.Information, error) {
+	return m.info, nil
+}
+
+func TestSweepStuckJobsWithoutLease(t *testing.T) {
+	now := time.Now().UTC()
+	db := &mockDatabase{}
+	db.On("AcquireLease", mock.Anything, watchdogLease, "replica-2", now, 2*time.Minute).Return(false, nil)
+	h := &Handler{database: db, instanceID: "replica-2", watchdog: config.Watchdog{Interval: time.Minute, Deadline: time.Hour}}
+
+	assert.NoError(t, h.SweepStuckJobs(context.Background(), now))
+	db.AssertExpectations(t)
+	db.AssertNotCalled(t, "ListStuckJobs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSweepStuckJobs(t *testing.T) {
+	requestID := "req1"
+	appID := uuid.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	info := orchestrator.Information{
+		App: orchestrator.ApplicationInstance{IPList: []string{"10.0.0.1"}, InfraType: "k8s"},
+		NE:  []orchestrator.NEInfo{{InstanceID: "ne1"}, {InstanceID: "ne2"}},
+	}
+	stuck := database.Job{
+		JobSpec:   database.JobSpec{RequestId: &requestID, Service: []models.AppInstanceId{appID}},
+		Status:    database.StatusGathering,
+		CreatedAt: now.Add(-time.Hour),
+	}
+
+	tests := []struct {
+		name         string
+		job          func(database.Job) database.Job
+		results      []database.JobAppResult
+		expectEvents map[string]event.EventType
+		expectFailed bool
+	}{
+		{
+			name: "nothing gathered",
+			expectEvents: map[string]event.EventType{
+				event.EventIDForApp(requestID, appID.String()):              event.EventTypeAppConsumptionRequested,
+				event.EventIDForNE(requestID, appID.String(), "", "ne1", 0): event.EventTypeNetworkElementEnergyRequested,
+				event.EventIDForNE(requestID, appID.String(), "", "ne2", 0): event.EventTypeNetworkElementEnergyRequested,
+				event.EventIDForTraffic(requestID, appID.String(), 0):       event.EventTypeNetworkElementTrafficRequested,
+			},
+		},
+		{
+			name: "one network element energy missing",
+			results: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: requestID, AppID: appID.String(), NumberOfTotalNEs: 2},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {EnergyConsumption: floatPtr(1), AppInstanceTraffic: floatPtr(1), TotalTraffic: floatPtr(2)},
+						"ne2": {AppInstanceTraffic: floatPtr(1), TotalTraffic: floatPtr(2)},
+					},
+				},
+			}},
+			expectEvents: map[string]event.EventType{
+				event.EventIDForNE(requestID, appID.String(), "", "ne2", 0): event.EventTypeNetworkElementEnergyRequested,
+			},
+		},
+		{
+			name: "calculation event lost",
+			job: func(j database.Job) database.Job {
+				j.CalculationTriggered = true
+				return j
+			},
+			expectEvents: map[string]event.EventType{requestID: event.EventTypeCalculationRequested},
+		},
+		{
+			name: "resumed too many times",
+			job: func(j database.Job) database.Job {
+				j.Resumes = 2
+				return j
+			},
+			expectEvents: map[string]event.EventType{requestID: event.EventTypeNotificationErrorRequested},
+			expectFailed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := stuck
+			if tt.job != nil {
+				job = tt.job(stuck)
+			}
+			db := &mockDatabase{}
+			db.On("AcquireLease", mock.Anything, watchdogLease, "replica-1", now, 2*time.Minute).Return(true, nil)
+			db.On("ListStuckJobs", mock.Anything, now.Add(-15*time.Minute), 10).Return([]database.Job{job}, nil)
+			db.On("GetAllJobAppResults", mock.Anything, requestID).Return(tt.results, nil).Maybe()
+			db.On("GetJob", mock.Anything, requestID).Return(&job, nil).Maybe()
+			if tt.expectFailed {
+				db.On("SetJobError", mock.Anything, requestID, mock.Anything, database.Failure{Stage: database.StatusGathering, Cause: database.FailureCauseTimeout}).Return(nil)
+			} else {
+				db.On("MarkJobResumed", mock.Anything, requestID, now).Return(nil)
+			}
+			sender := &mockSender{}
+			for id, eventType := range tt.expectEvents {
+				sender.On("Send", mock.Anything, id, eventType, event.SourceEFNWorker, mock.Anything).Return(nil).Once()
+			}
+
+			h := &Handler{
+				database:     db,
+				events:       sender,
+				orchestrator: &mockOrchestrator{info: info},
+				instanceID:   "replica-1",
+				watchdog:     config.Watchdog{Interval: time.Minute, Deadline: 15 * time.Minute, MaxResumes: 2, BatchSize: 10},
+			}
+			assert.NoError(t, h.SweepStuckJobs(context.Background(), now))
+			db.AssertExpectations(t)
+			sender.AssertExpectations(t)
+			sender.AssertNumberOfCalls(t, "Send", len(tt.expectEvents))
+		})
+	}
+}
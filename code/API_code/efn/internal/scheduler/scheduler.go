@@ -0,0 +1,159 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+	servererr "github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/server/error"
+)
+
+// Scheduler starts the runs of the periodic reports. Each run is a child job of the periodic report,
+// calculated like any other job over the period that elapsed before the run.
+type Scheduler struct {
+	database      database.Interface
+	events        event.Sender
+	leaseDuration time.Duration
+}
+
+func New(db database.Interface, sender event.Sender, leaseDuration time.Duration) *Scheduler {
+	return &Scheduler{
+		database:      db,
+		events:        sender,
+		leaseDuration: leaseDuration,
+	}
+}
+
+// Period returns the time between two runs of a periodic report.
+func Period(p models.ReportingPeriod) (time.Duration, bool) {
+	switch p {
+	case models.Hourly:
+		return time.Hour, true
+	case models.Daily:
+		return 24 * time.Hour, true
+	case models.Weekly:
+		return 7 * 24 * time.Hour, true
+	default:
+		return 0, false
+	}
+}
+
+// RunID returns the deterministic ID of the child job of a run, so that a run started again
+// after a failure reuses the job and the events of the first attempt.
+func RunID(parentID string, run int) string {
+	baseNS := uuid.NewSHA1(uuid.NameSpaceURL, []byte("camara-efn-api:run-id"))
+	return uuid.NewSHA1(baseNS, []byte(parentID+"\x00"+strconv.Itoa(run))).String()
+}
+
+// RunDue starts every run due at now. A run that cannot be started is logged and retried
+// once its lease expires, without blocking the other periodic reports.
+func (s *Scheduler) RunDue(ctx context.Context, now time.Time) error {
+	log := logger.FromContext(ctx)
+	for {
+		parent, err := s.database.ClaimDueSchedule(ctx, now, s.leaseDuration)
+		if err != nil {
+			msg := "failed to claim due periodic report"
+			log.With(zap.Error(err)).Error(msg)
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		if parent == nil {
+			return nil
+		}
+		if err = s.startRun(ctx, parent); err != nil {
+			log.With(zap.Error(err), zap.String("requestID", *parent.RequestId)).Error("failed to start run of periodic report")
+		}
+	}
+}
+
+// startRun creates the child job of the next run of parent, requests the gathering of its data
+// and moves the schedule forward.
+func (s *Scheduler) startRun(ctx context.Context, parent *database.Job) error {
+	ctx = correlator.NewContext(ctx, parent.XCorrelator)
+	log := logger.FromContext(ctx).With(zap.String("requestID", *parent.RequestId))
+
+	child := newRun(parent)
+	childID := *child.RequestId
+	if err := s.database.CreateJob(ctx, child); err != nil && !servererr.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create job of run %d: %w", child.Run, err)
+	}
+	for _, appInstanceID := range child.Service {
+		eventID := event.EventIDForApp(childID, appInstanceID.String())
+		data := event.NewGatherInfoData(childID, appInstanceID.String())
+		if err := s.events.Send(ctx, eventID, event.EventTypeGatherInfoRequested, event.SourceEFNScheduler, data); err != nil {
+			return fmt.Errorf("failed to send event %s: %w", eventID, err)
+		}
+	}
+
+	nextRunAt, ended := advance(parent)
+	if err := s.database.AdvanceSchedule(ctx, *parent.RequestId, child.Run, nextRunAt, ended); err != nil {
+		return fmt.Errorf("failed to advance schedule: %w", err)
+	}
+	log.With(zap.Int("run", child.Run), zap.String("runID", childID), zap.Bool("ended", ended)).Info("started run of periodic report")
+	return nil
+}
+
+// newRun builds the child job of the next run of parent. It covers the period ending when the run is due.
+func newRun(parent *database.Job) *database.Job {
+	run := parent.Schedule.Runs + 1
+	id := RunID(*parent.RequestId, run)
+	end := parent.Schedule.NextRunAt
+	now := time.Now().UTC()
+	return &database.Job{
+		JobSpec: database.JobSpec{
+			RequestId:           &id,
+			RequestKind:         parent.RequestKind,
+			Service:             parent.Service,
+			SubscriptionRequest: parent.SubscriptionRequest,
+			TimePeriod: &models.TimePeriod{
+				StartDate: end.Add(-parent.Schedule.Period),
+				EndDate:   &end,
+			},
+		},
+		Subject:     parent.Subject,
+		XCorrelator: parent.XCorrelator,
+		Status:      database.StatusCreated,
+		Transitions: []database.Transition{{Status: database.StatusCreated, At: now}},
+		CreatedAt:   now,
+		ParentID:    *parent.RequestId,
+		Run:         run,
+	}
+}
+
+// advance returns when the run after the current one of parent is due, and whether the current
+// run is the last one because the maximum number of events or the expiry time is reached.
+func advance(parent *database.Job) (time.Time, bool) {
+	run := parent.Schedule.Runs + 1
+	next := parent.Schedule.NextRunAt.Add(parent.Schedule.Period)
+	cfg := parent.SubscriptionRequest.Config
+	if cfg.SubscriptionMaxEvents != nil && run >= *cfg.SubscriptionMaxEvents {
+		return next, true
+	}
+	if cfg.SubscriptionExpireTime != nil && next.After(*cfg.SubscriptionExpireTime) {
+		return next, true
+	}
+	return next, false
+}
@@ -0,0 +1,168 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/event"
+)
+
+type mockDatabase struct {
+	mock.Mock
+	database.Interface
+}
+
+func (m *mockDatabase) ClaimDueSchedule(ctx context.Context, now time.Time, lease time.Duration) (*database.Job, error) {
+	args := m.Called(ctx, now, lease)
+	return args.Get(0).(*database.Job), args.Error(1)
+}
+
+func (m *mockDatabase) CreateJob(ctx context.Context, r *database.Job) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *mockDatabase) AdvanceSchedule(ctx context.Context, jobID string, run int, nextRunAt time.Time, ended bool) error {
+	args := m.Called(ctx, jobID, run, nextRunAt, ended)
+	return args.Error(0)
+}
+
+type mockSender struct {
+	mock.Mock
+}
+
+func (m *mockSender) Send(ctx context.Context, requestID string, eventType event.EventType, source event.Source, data any, opts ...event.Option) error {
+	args := m.Called(ctx, requestID, eventType, source, data)
+	return args.Error(0)
+}
+
+func TestNewRun(t *testing.T) {
+	parentID := "parent"
+	runAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	parent := &database.Job{
+		JobSpec: database.JobSpec{
+			RequestId:   &parentID,
+			RequestKind: database.RequestKindCarbonFootprint,
+			Service:     []models.AppInstanceId{uuid.New()},
+		},
+		Subject:  "subject",
+		Schedule: &database.Schedule{Period: 24 * time.Hour, NextRunAt: runAt, Runs: 2},
+	}
+
+	child := newRun(parent)
+	assert.Equal(t, RunID(parentID, 3), *child.RequestId)
+	assert.Equal(t, parentID, child.ParentID)
+	assert.Equal(t, 3, child.Run)
+	assert.Equal(t, database.StatusCreated, child.Status)
+	assert.Equal(t, database.StatusCreated, child.Transitions[0].Status)
+	assert.Equal(t, parent.Subject, child.Subject)
+	assert.Equal(t, parent.RequestKind, child.RequestKind)
+	assert.Equal(t, runAt.Add(-24*time.Hour), child.TimePeriod.StartDate)
+	assert.Equal(t, runAt, *child.TimePeriod.EndDate)
+	assert.Nil(t, child.Schedule)
+}
+
+func TestRunID(t *testing.T) {
+	assert.Equal(t, RunID("parent", 1), RunID("parent", 1))
+	assert.NotEqual(t, RunID("parent", 1), RunID("parent", 2))
+	assert.NotEqual(t, RunID("parent", 1), RunID("other", 1))
+}
+
+func TestAdvance(t *testing.T) {
+	runAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	next := runAt.Add(time.Hour)
+	maxEvents := 3
+	expireAfterNext := next.Add(time.Minute)
+	expireBeforeNext := next.Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		runs        int
+		config      models.Config
+		expectEnded bool
+	}{
+		{
+			name:   "runs left before max events",
+			runs:   1,
+			config: models.Config{SubscriptionMaxEvents: &maxEvents},
+		},
+		{
+			name:        "last run reaches max events",
+			runs:        2,
+			config:      models.Config{SubscriptionMaxEvents: &maxEvents},
+			expectEnded: true,
+		},
+		{
+			name:   "next run before expiry",
+			config: models.Config{SubscriptionExpireTime: &expireAfterNext},
+		},
+		{
+			name:        "next run after expiry",
+			config:      models.Config{SubscriptionExpireTime: &expireBeforeNext},
+			expectEnded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := &database.Job{Schedule: &database.Schedule{Period: time.Hour, NextRunAt: runAt, Runs: tt.runs}}
+			parent.SubscriptionRequest.Config = tt.config
+
+			nextRunAt, ended := advance(parent)
+			assert.Equal(t, next, nextRunAt)
+			assert.Equal(t, tt.expectEnded, ended)
+		})
+	}
+}
+
+func TestRunDue(t *testing.T) {
+	parentID := "parent"
+	app := uuid.New()
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	maxEvents := 1
+	parent := &database.Job{
+		JobSpec: database.JobSpec{
+			RequestId: &parentID,
+			Service:   []models.AppInstanceId{app},
+		},
+		Schedule: &database.Schedule{Period: time.Hour, NextRunAt: now},
+	}
+	parent.SubscriptionRequest.Config.SubscriptionMaxEvents = &maxEvents
+	childID := RunID(parentID, 1)
+
+	db := new(mockDatabase)
+	db.On("ClaimDueSchedule", mock.Anything, now, time.Minute).Return(parent, nil).Once()
+	db.On("ClaimDueSchedule", mock.Anything, now, time.Minute).Return((*database.Job)(nil), nil).Once()
+	db.On("CreateJob", mock.Anything, mock.MatchedBy(func(j *database.Job) bool { return *j.RequestId == childID })).Return(nil)
+	db.On("AdvanceSchedule", mock.Anything, parentID, 1, now.Add(time.Hour), true).Return(nil)
+	sender := new(mockSender)
+	sender.On("Send", mock.Anything, event.EventIDForApp(childID, app.String()), event.EventTypeGatherInfoRequested,
+		event.SourceEFNScheduler, event.NewGatherInfoData(childID, app.String())).Return(nil)
+
+	s := New(db, sender, time.Minute)
+	assert.NoError(t, s.RunDue(context.Background(), now))
+	db.AssertExpectations(t)
+	sender.AssertExpectations(t)
+}
@@ -18,15 +18,31 @@ package calculator
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/registry"
 )
 
 var _ Interface = &simpleClient{}
 
+func init() {
+	Backends.Register("simple", registry.WithConfig("carbon", func(conf SimpleConfig) (Interface, error) {
+		return NewSimpleClient(conf.FactorTCO2ePerKWh), nil
+	}))
+}
+
+// SimpleConfig configures the simple calculator, from the variables prefixed with CARBON.
+type SimpleConfig struct {
+	FactorTCO2ePerKWh float64 `envconfig:"FACTOR_TCO2E_PER_KWH" default:"0.00035" description:"CO2 conversion factor, in tCO2e per kWh."`
+}
+
 // simpleClient holds configuration values for calculations
 type simpleClient struct {
 	// CO2 conversion factor in tCO2e per kWh (e.g., 0.00035)
@@ -43,19 +59,26 @@ func NewSimpleClient(factor float64) *simpleClient {
 }
 
 // CalculateEnergyConsumption calculates the energy consumption (kWh) for the given application instance, taking into account both application and all network elements consumption.
+// Each entry of data covers a window of the time period, so the energy of network elements is allocated window by window.
+// Application instances and network elements marked as failed are left out.
 func (simpleClient) CalculateEnergyConsumption(ctx context.Context, data []database.JobAppResult) (*float64, error) {
 	var result float64
-
-	if err := validateInput(data[0]); err != nil {
-		return nil, err
-	}
+	sharers := countSharers(data)
 
 	// Simple calculation: sum of app instance and proportional network element energy consumption
 	for _, d := range data {
+		if err := validateInput(d); err != nil {
+			return nil, err
+		}
 		appResult := d.Result
-		result += *appResult.AppInstanceEnergyConsumption
-		for _, ne := range appResult.NetworkElements {
-			trafficShare := *ne.AppInstanceTraffic / *ne.TotalTraffic
+		if appResult.AppInstanceEnergyConsumption != nil {
+			result += *appResult.AppInstanceEnergyConsumption
+		}
+		for key, ne := range appResult.NetworkElements {
+			if ne.Failure != "" {
+				continue
+			}
+			trafficShare := trafficShare(ne, sharers[sharerKey{d.Window, ne.ID(key)}])
 			allocatedNEConsumption := *ne.EnergyConsumption * trafficShare
 			result += allocatedNEConsumption
 		}
@@ -75,16 +98,125 @@ func (c simpleClient) CalculateCarbonFootprint(ctx context.Context, data []datab
 	return &carbonFootprint, nil
 }
 
+// CalculateEnergyConsumptionBreakdown splits the energy consumption (kWh) per application instance and network element.
+func (simpleClient) CalculateEnergyConsumptionBreakdown(ctx context.Context, data []database.JobAppResult) (*models.ResultBreakdown, error) {
+	return breakdown(data, 1)
+}
+
+// CalculateCarbonFootprintBreakdown splits the carbon footprint (tCO2e) per application instance and network element,
+// applying the CO2 conversion factor to every energy share.
+func (c simpleClient) CalculateCarbonFootprintBreakdown(ctx context.Context, data []database.JobAppResult) (*models.ResultBreakdown, error) {
+	return breakdown(data, c.tCO2ePerKWh)
+}
+
+// breakdown allocates the energy of each network element to the application instance proportionally to its traffic,
+// as done for the total, and scales every value by factor. The energy is allocated window by window, and the windows
+// of an application instance are then added up; the traffic share reported is the one over all windows. Application
+// instances and network elements are sorted by identifier so that the breakdown is stable. Failed network elements
+// are left out, and the value of a failed application instance alone is zero. The share of a network element whose
+// traffic was estimated in every window is the mean of the estimated shares.
+func breakdown(data []database.JobAppResult, factor float64) (*models.ResultBreakdown, error) {
+	type neTotals struct {
+		id                                  database.NetworkElementID
+		allocated, appTraffic, totalTraffic float64
+		measured                            bool
+		estimatedShares                     []float64
+	}
+	sharers := countSharers(data)
+	apps := make(map[string]*models.ApplicationBreakdown)
+	nes := make(map[string]map[string]*neTotals)
+	for _, d := range data {
+		if err := validateInput(d); err != nil {
+			return nil, err
+		}
+		app, ok := apps[d.AppID]
+		if !ok {
+			appID, err := uuid.Parse(d.AppID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid application instance ID %q: %w", d.AppID, err)
+			}
+			app = &models.ApplicationBreakdown{AppInstanceId: appID}
+			apps[d.AppID] = app
+			nes[d.AppID] = make(map[string]*neTotals)
+		}
+
+		if d.Result.AppInstanceEnergyConsumption != nil {
+			app.Application += *d.Result.AppInstanceEnergyConsumption * factor
+		}
+		for key, ne := range d.Result.NetworkElements {
+			if ne.Failure != "" {
+				continue
+			}
+			totals, ok := nes[d.AppID][key]
+			if !ok {
+				totals = &neTotals{id: ne.ID(key)}
+				nes[d.AppID][key] = totals
+			}
+			share := trafficShare(ne, sharers[sharerKey{d.Window, totals.id}])
+			totals.allocated += *ne.EnergyConsumption * share * factor
+			if ne.TrafficEstimate != "" {
+				totals.estimatedShares = append(totals.estimatedShares, share)
+				continue
+			}
+			totals.measured = true
+			totals.appTraffic += *ne.AppInstanceTraffic
+			totals.totalTraffic += *ne.TotalTraffic
+		}
+	}
+
+	result := models.ResultBreakdown{Applications: make([]models.ApplicationBreakdown, 0, len(apps))}
+	for appID, app := range apps {
+		app.Total = app.Application
+		app.NetworkElements = make([]models.NetworkElementBreakdown, 0, len(nes[appID]))
+		for _, totals := range nes[appID] {
+			neBreakdown := models.NetworkElementBreakdown{
+				NetworkElementId: totals.id.NEInstanceID,
+				VendorId:         vendorID(totals.id),
+				Allocated:        totals.allocated,
+			}
+			if totals.measured {
+				if totals.totalTraffic > 0 {
+					neBreakdown.TrafficShare = totals.appTraffic / totals.totalTraffic
+				}
+			} else {
+				for _, share := range totals.estimatedShares {
+					neBreakdown.TrafficShare += share / float64(len(totals.estimatedShares))
+				}
+			}
+			if len(totals.estimatedShares) > 0 {
+				estimated := true
+				neBreakdown.Estimated = &estimated
+			}
+			app.NetworkElements = append(app.NetworkElements, neBreakdown)
+			app.Total += totals.allocated
+		}
+		slices.SortFunc(app.NetworkElements, func(a, b models.NetworkElementBreakdown) int {
+			return compareNetworkElements(a.NetworkElementId, a.VendorId, b.NetworkElementId, b.VendorId)
+		})
+		result.Applications = append(result.Applications, *app)
+	}
+	slices.SortFunc(result.Applications, func(a, b models.ApplicationBreakdown) int {
+		return strings.Compare(a.AppInstanceId.String(), b.AppInstanceId.String())
+	})
+	return &result, nil
+}
+
+// validateInput checks that every value of an application instance is present, unless it is marked as failed.
 func validateInput(data database.JobAppResult) error {
 	log := logger.Get().With(zap.String("requestID", data.JobID), zap.String("applicationInstanceID", data.AppID))
-	if data.Result.AppInstanceEnergyConsumption == nil {
+	if data.Result == nil {
+		msg := "Missing result for application instance"
+		log.Error(msg)
+		return fmt.Errorf("%s", msg)
+	}
+	if data.Result.AppInstanceEnergyConsumption == nil && data.Result.AppInstanceFailure == "" {
 		msg := "Missing energy consumption for application instance"
 		log.Error(msg)
 		return fmt.Errorf("%s", msg)
 	}
 
 	for _, ne := range data.Result.NetworkElements {
-		if ne.EnergyConsumption == nil || ne.TotalTraffic == nil || ne.AppInstanceTraffic == nil {
+		if ne.Failure == "" && (ne.EnergyConsumption == nil || !ne.HasTraffic()) {
 			msg := "Missing energy consumption for network element"
 			log.With(zap.Any("networkElement", ne)).Error(msg)
 			return fmt.Errorf("%s", msg)
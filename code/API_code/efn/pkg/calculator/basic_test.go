@@ -17,10 +17,13 @@ package calculator
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
 )
 
@@ -103,6 +106,26 @@ func TestCalculateEnergyConsumption(t *testing.T) {
 			}},
 			expects: floatPtr(2.1), // 2.0 + (1.0 * 0.1)
 		},
+		{
+			name: "NE without traffic is allocated nothing",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					JobID: "job1",
+					AppID: "app1",
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(2.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							EnergyConsumption:  floatPtr(1.0),
+							AppInstanceTraffic: floatPtr(0),
+							TotalTraffic:       floatPtr(0),
+						},
+					},
+				},
+			}},
+			expects: floatPtr(2.0),
+		},
 		{
 			name: "all data present, multiple apps and NEs",
 			input: []database.JobAppResult{{
@@ -185,6 +208,113 @@ func TestCalculateEnergyConsumption(t *testing.T) {
 			expects:   nil,
 			expectErr: true,
 		},
+		{
+			name: "failed NE and app instance consumption are left out",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					JobID: "job1",
+					AppID: "app1",
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							EnergyConsumption:  floatPtr(2.0),
+							AppInstanceTraffic: floatPtr(50.0),
+							TotalTraffic:       floatPtr(100.0),
+						},
+						"ne2": {
+							AppInstanceTraffic: floatPtr(10.0),
+							TotalTraffic:       floatPtr(100.0),
+							Failure:            "energy consumption not available",
+						},
+					},
+				},
+			}, {
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					JobID: "job1",
+					AppID: "app2",
+				},
+				Result: &database.TaskResult{
+					AppInstanceFailure: "energy consumption not available",
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							EnergyConsumption:  floatPtr(1.0),
+							AppInstanceTraffic: floatPtr(20.0),
+							TotalTraffic:       floatPtr(100.0),
+						},
+					},
+				},
+			}},
+			expects: floatPtr(1.0 + (2.0 * 0.5) + (1.0 * 0.2)),
+		},
+		{
+			name: "NE energy allocated window by window",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					JobID:           "job1",
+					AppID:           "app1",
+					NumberOfWindows: 2,
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							EnergyConsumption:  floatPtr(10.0),
+							AppInstanceTraffic: floatPtr(90.0),
+							TotalTraffic:       floatPtr(100.0),
+						},
+					},
+				},
+			}, {
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					JobID:           "job1",
+					AppID:           "app1",
+					Window:          1,
+					NumberOfWindows: 2,
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							EnergyConsumption:  floatPtr(2.0),
+							AppInstanceTraffic: floatPtr(10.0),
+							TotalTraffic:       floatPtr(100.0),
+						},
+					},
+				},
+			}},
+			// Not 2.0 + 12.0 * (100 / 200): the traffic share of each window applies to its own energy.
+			expects: floatPtr(1.0 + (10.0 * 0.9) + 1.0 + (2.0 * 0.1)),
+		},
+		{
+			name: "missing NE field in a later app instance",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					JobID: "job1",
+					AppID: "app1",
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements:              map[string]database.NetworkElementResult{},
+				},
+			}, {
+				JobAppResultMetadata: database.JobAppResultMetadata{
+					JobID: "job1",
+					AppID: "app2",
+				},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {
+							EnergyConsumption: floatPtr(1.0),
+						},
+					},
+				},
+			}},
+			expects:   nil,
+			expectErr: true,
+		},
 	}
 
 	client := NewSimpleClient(tCO2ePerKWh)
@@ -310,3 +440,259 @@ func TestCalculateCarbonFootprint(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateBreakdown(t *testing.T) {
+	app1 := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	app2 := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	input := []database.JobAppResult{
+		{
+			JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app2.String()},
+			Result: &database.TaskResult{
+				AppInstanceEnergyConsumption: floatPtr(2.0),
+				NetworkElements: map[string]database.NetworkElementResult{
+					"ne1": {
+						EnergyConsumption:  floatPtr(1.0),
+						AppInstanceTraffic: floatPtr(20.0),
+						TotalTraffic:       floatPtr(100.0),
+					},
+				},
+			},
+		},
+		{
+			JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String()},
+			Result: &database.TaskResult{
+				AppInstanceEnergyConsumption: floatPtr(1.0),
+				NetworkElements: map[string]database.NetworkElementResult{
+					"ne2": {
+						EnergyConsumption:  floatPtr(3.0),
+						AppInstanceTraffic: floatPtr(10.0),
+						TotalTraffic:       floatPtr(100.0),
+					},
+					"ne1": {
+						EnergyConsumption:  floatPtr(2.0),
+						AppInstanceTraffic: floatPtr(50.0),
+						TotalTraffic:       floatPtr(100.0),
+					},
+				},
+			},
+		},
+	}
+	expected := models.ResultBreakdown{Applications: []models.ApplicationBreakdown{
+		{
+			AppInstanceId: app1,
+			Total:         1.0 + (2.0 * 0.5) + (3.0 * 0.1),
+			Application:   1.0,
+			NetworkElements: []models.NetworkElementBreakdown{
+				{NetworkElementId: "ne1", TrafficShare: 0.5, Allocated: 1.0},
+				{NetworkElementId: "ne2", TrafficShare: 0.1, Allocated: 0.3},
+			},
+		},
+		{
+			AppInstanceId: app2,
+			Total:         2.0 + (1.0 * 0.2),
+			Application:   2.0,
+			NetworkElements: []models.NetworkElementBreakdown{
+				{NetworkElementId: "ne1", TrafficShare: 0.2, Allocated: 0.2},
+			},
+		},
+	}}
+
+	client := NewSimpleClient(tCO2ePerKWh)
+	ctx := context.Background()
+
+	t.Run("energy breakdown is sorted and sums up to the total", func(t *testing.T) {
+		result, err := client.CalculateEnergyConsumptionBreakdown(ctx, input)
+		assert.NoError(t, err)
+		total, err := client.CalculateEnergyConsumption(ctx, input)
+		assert.NoError(t, err)
+
+		var sum float64
+		assert.Len(t, result.Applications, len(expected.Applications))
+		for i, app := range result.Applications {
+			exp := expected.Applications[i]
+			assert.Equal(t, exp.AppInstanceId, app.AppInstanceId)
+			assert.InDelta(t, exp.Total, app.Total, 1e-9)
+			assert.InDelta(t, exp.Application, app.Application, 1e-9)
+			assert.Len(t, app.NetworkElements, len(exp.NetworkElements))
+			for j, ne := range app.NetworkElements {
+				assert.Equal(t, exp.NetworkElements[j].NetworkElementId, ne.NetworkElementId)
+				assert.InDelta(t, exp.NetworkElements[j].TrafficShare, ne.TrafficShare, 1e-9)
+				assert.InDelta(t, exp.NetworkElements[j].Allocated, ne.Allocated, 1e-9)
+				assert.Nil(t, ne.Estimated)
+			}
+			sum += app.Total
+		}
+		assert.InDelta(t, *total, sum, 1e-9)
+	})
+
+	t.Run("carbon breakdown applies the conversion factor", func(t *testing.T) {
+		result, err := client.CalculateCarbonFootprintBreakdown(ctx, input)
+		assert.NoError(t, err)
+		for i, app := range result.Applications {
+			assert.InDelta(t, expected.Applications[i].Total*tCO2ePerKWh, app.Total, 1e-12)
+		}
+	})
+
+	t.Run("estimated traffic shares are flagged in the breakdown", func(t *testing.T) {
+		estimated := []database.JobAppResult{
+			{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String()},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {EnergyConsumption: floatPtr(4.0), TrafficEstimate: models.EqualSplit},
+						"ne2": {EnergyConsumption: floatPtr(3.0), TrafficEstimate: models.Zero},
+					},
+				},
+			},
+			{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app2.String()},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(2.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": {EnergyConsumption: floatPtr(4.0), TrafficEstimate: models.EqualSplit},
+					},
+				},
+			},
+		}
+		result, err := client.CalculateEnergyConsumptionBreakdown(ctx, estimated)
+		assert.NoError(t, err)
+		total, err := client.CalculateEnergyConsumption(ctx, estimated)
+		assert.NoError(t, err)
+		// ne1 is split between both application instances, ne2 is allocated nothing.
+		assert.InDelta(t, 1.0+2.0+2.0+2.0, *total, 1e-9)
+
+		assert.Len(t, result.Applications, 2)
+		app := result.Applications[0]
+		assert.Equal(t, app1, app.AppInstanceId)
+		assert.InDelta(t, 3.0, app.Total, 1e-9)
+		assert.Len(t, app.NetworkElements, 2)
+		assert.InDelta(t, 0.5, app.NetworkElements[0].TrafficShare, 1e-9)
+		assert.InDelta(t, 2.0, app.NetworkElements[0].Allocated, 1e-9)
+		assert.Equal(t, true, *app.NetworkElements[0].Estimated)
+		assert.InDelta(t, 0.0, app.NetworkElements[1].Allocated, 1e-9)
+		assert.Equal(t, true, *app.NetworkElements[1].Estimated)
+		assert.InDelta(t, 4.0, result.Applications[1].Total, 1e-9)
+	})
+
+	t.Run("NE without traffic has a zero share that marshals to JSON", func(t *testing.T) {
+		idle := []database.JobAppResult{{
+			JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String()},
+			Result: &database.TaskResult{
+				AppInstanceEnergyConsumption: floatPtr(1.0),
+				NetworkElements: map[string]database.NetworkElementResult{
+					"ne1": {EnergyConsumption: floatPtr(4.0), AppInstanceTraffic: floatPtr(0), TotalTraffic: floatPtr(0)},
+				},
+			},
+		}}
+		result, err := client.CalculateEnergyConsumptionBreakdown(ctx, idle)
+		assert.NoError(t, err)
+		assert.Len(t, result.Applications, 1)
+		assert.InDelta(t, 1.0, result.Applications[0].Total, 1e-9)
+		assert.Equal(t, 0.0, result.Applications[0].NetworkElements[0].TrafficShare)
+		assert.Equal(t, 0.0, result.Applications[0].NetworkElements[0].Allocated)
+		_, err = json.Marshal(result)
+		assert.NoError(t, err)
+	})
+
+	t.Run("NEs of two vendors sharing an ID are kept apart", func(t *testing.T) {
+		vendorA := database.NetworkElementID{VendorID: "vendorA", NEInstanceID: "ne1"}
+		vendorB := database.NetworkElementID{VendorID: "vendorB", NEInstanceID: "ne1"}
+		shared := []database.JobAppResult{
+			{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String()},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						vendorA.Key(): {NetworkElementID: vendorA, EnergyConsumption: floatPtr(4.0), TrafficEstimate: models.EqualSplit},
+						vendorB.Key(): {NetworkElementID: vendorB, EnergyConsumption: floatPtr(2.0), AppInstanceTraffic: floatPtr(10.0), TotalTraffic: floatPtr(100.0)},
+					},
+				},
+			},
+			{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app2.String()},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						vendorB.Key(): {NetworkElementID: vendorB, EnergyConsumption: floatPtr(2.0), AppInstanceTraffic: floatPtr(30.0), TotalTraffic: floatPtr(100.0)},
+					},
+				},
+			},
+		}
+		result, err := client.CalculateEnergyConsumptionBreakdown(ctx, shared)
+		assert.NoError(t, err)
+		total, err := client.CalculateEnergyConsumption(ctx, shared)
+		assert.NoError(t, err)
+		// The NE of vendorA is served by app1 alone: the equal split allocates it whole.
+		assert.InDelta(t, 1.0+4.0+0.2+1.0+0.6, *total, 1e-9)
+
+		assert.Len(t, result.Applications, 2)
+		app := result.Applications[0]
+		assert.Equal(t, app1, app.AppInstanceId)
+		assert.Len(t, app.NetworkElements, 2)
+		assert.Equal(t, "ne1", app.NetworkElements[0].NetworkElementId)
+		assert.Equal(t, "vendorA", *app.NetworkElements[0].VendorId)
+		assert.InDelta(t, 1.0, app.NetworkElements[0].TrafficShare, 1e-9)
+		assert.InDelta(t, 4.0, app.NetworkElements[0].Allocated, 1e-9)
+		assert.Equal(t, "ne1", app.NetworkElements[1].NetworkElementId)
+		assert.Equal(t, "vendorB", *app.NetworkElements[1].VendorId)
+		assert.InDelta(t, 0.1, app.NetworkElements[1].TrafficShare, 1e-9)
+		assert.InDelta(t, 0.2, app.NetworkElements[1].Allocated, 1e-9)
+	})
+
+	t.Run("invalid application instance ID returns error", func(t *testing.T) {
+		invalid := []database.JobAppResult{input[0]}
+		invalid[0].AppID = "app1"
+		result, err := client.CalculateEnergyConsumptionBreakdown(ctx, invalid)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("windows of an application instance are added up", func(t *testing.T) {
+		windowed := []database.JobAppResult{input[0], input[0]}
+		windowed[1].Window = 1
+		windowed[1].Result = &database.TaskResult{
+			AppInstanceEnergyConsumption: floatPtr(3.0),
+			NetworkElements: map[string]database.NetworkElementResult{
+				"ne1": {
+					EnergyConsumption:  floatPtr(4.0),
+					AppInstanceTraffic: floatPtr(60.0),
+					TotalTraffic:       floatPtr(100.0),
+				},
+			},
+		}
+		result, err := client.CalculateEnergyConsumptionBreakdown(ctx, windowed)
+		assert.NoError(t, err)
+		total, err := client.CalculateEnergyConsumption(ctx, windowed)
+		assert.NoError(t, err)
+		assert.Len(t, result.Applications, 1)
+		app := result.Applications[0]
+		assert.InDelta(t, 2.0+3.0, app.Application, 1e-9)
+		assert.Len(t, app.NetworkElements, 1)
+		assert.InDelta(t, (1.0*0.2)+(4.0*0.6), app.NetworkElements[0].Allocated, 1e-9)
+		assert.InDelta(t, 80.0/200.0, app.NetworkElements[0].TrafficShare, 1e-9)
+		assert.InDelta(t, *total, app.Total, 1e-9)
+	})
+
+	t.Run("failed NE and app instance consumption are left out of the breakdown", func(t *testing.T) {
+		partial := []database.JobAppResult{input[0], input[1]}
+		partial[0].Result = &database.TaskResult{
+			AppInstanceFailure: "energy consumption not available",
+			NetworkElements:    input[0].Result.NetworkElements,
+		}
+		partial[1].Result = &database.TaskResult{
+			AppInstanceEnergyConsumption: floatPtr(1.0),
+			NetworkElements: map[string]database.NetworkElementResult{
+				"ne1": input[1].Result.NetworkElements["ne1"],
+				"ne2": {Failure: "traffic volume not available"},
+			},
+		}
+		result, err := client.CalculateEnergyConsumptionBreakdown(ctx, partial)
+		assert.NoError(t, err)
+		assert.Len(t, result.Applications, 2)
+		assert.InDelta(t, 1.0+(2.0*0.5), result.Applications[0].Total, 1e-9)
+		assert.Len(t, result.Applications[0].NetworkElements, 1)
+		assert.InDelta(t, 0, result.Applications[1].Application, 1e-9)
+		assert.InDelta(t, 1.0*0.2, result.Applications[1].Total, 1e-9)
+	})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package calculator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+)
+
+// Coverage tells how much of the expected data a result calculated from data is based on, and lists the
+// application instances and network elements marked as failed, and the network elements whose traffic was
+// estimated. The energy consumption of an application instance is one measurement, and so are the energy and
+// traffic of a network element together, in every window: an estimated network element is not one. An
+// application instance or network element missing from, or estimated in, several windows is listed once.
+func Coverage(data []database.JobAppResult) (*models.DataCoverage, error) {
+	coverage := models.DataCoverage{
+		MissingApplications:      []models.AppInstanceId{},
+		MissingNetworkElements:   []models.MissingNetworkElement{},
+		EstimatedNetworkElements: []models.EstimatedNetworkElement{},
+	}
+	expected, available := 0, 0
+	missingApps := make(map[string]bool)
+	missingNEs := make(map[[2]string]bool)
+	estimatedNEs := make(map[[2]string]bool)
+	for _, d := range data {
+		appID, err := uuid.Parse(d.AppID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid application instance ID %q: %w", d.AppID, err)
+		}
+		expected += 1 + d.NumberOfTotalNEs
+		if d.Result == nil {
+			continue
+		}
+		if d.Result.AppInstanceFailure == "" && d.Result.AppInstanceEnergyConsumption != nil {
+			available++
+		} else if !missingApps[d.AppID] {
+			missingApps[d.AppID] = true
+			coverage.MissingApplications = append(coverage.MissingApplications, appID)
+		}
+		for key, ne := range d.Result.NetworkElements {
+			id := ne.ID(key)
+			if ne.Failure == "" && ne.TrafficEstimate != "" {
+				if !estimatedNEs[[2]string{d.AppID, key}] {
+					estimatedNEs[[2]string{d.AppID, key}] = true
+					coverage.EstimatedNetworkElements = append(coverage.EstimatedNetworkElements, models.EstimatedNetworkElement{
+						AppInstanceId:    appID,
+						NetworkElementId: id.NEInstanceID,
+						VendorId:         vendorID(id),
+						Method:           ne.TrafficEstimate,
+					})
+				}
+				continue
+			}
+			if ne.Failure == "" {
+				available++
+				continue
+			}
+			if missingNEs[[2]string{d.AppID, key}] {
+				continue
+			}
+			missingNEs[[2]string{d.AppID, key}] = true
+			coverage.MissingNetworkElements = append(coverage.MissingNetworkElements, models.MissingNetworkElement{
+				AppInstanceId:    appID,
+				NetworkElementId: id.NEInstanceID,
+				VendorId:         vendorID(id),
+				Reason:           ne.Failure,
+			})
+		}
+	}
+	if expected > 0 {
+		coverage.Ratio = float64(available) / float64(expected)
+	}
+
+	slices.SortFunc(coverage.MissingApplications, func(a, b models.AppInstanceId) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	slices.SortFunc(coverage.MissingNetworkElements, func(a, b models.MissingNetworkElement) int {
+		if c := strings.Compare(a.AppInstanceId.String(), b.AppInstanceId.String()); c != 0 {
+			return c
+		}
+		return compareNetworkElements(a.NetworkElementId, a.VendorId, b.NetworkElementId, b.VendorId)
+	})
+	slices.SortFunc(coverage.EstimatedNetworkElements, func(a, b models.EstimatedNetworkElement) int {
+		if c := strings.Compare(a.AppInstanceId.String(), b.AppInstanceId.String()); c != 0 {
+			return c
+		}
+		return compareNetworkElements(a.NetworkElementId, a.VendorId, b.NetworkElementId, b.VendorId)
+	})
+	return &coverage, nil
+}
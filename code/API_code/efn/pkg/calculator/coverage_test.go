@@ -0,0 +1,181 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package calculator
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+)
+
+func TestCoverage(t *testing.T) {
+	app1 := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	app2 := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	ne := database.NetworkElementResult{
+		EnergyConsumption:  floatPtr(1.0),
+		AppInstanceTraffic: floatPtr(10.0),
+		TotalTraffic:       floatPtr(100.0),
+	}
+
+	tests := []struct {
+		name      string
+		input     []database.JobAppResult
+		expects   *models.DataCoverage
+		expectErr bool
+	}{
+		{
+			name: "all data gathered",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String(), NumberOfTotalNEs: 1},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements:              map[string]database.NetworkElementResult{"ne1": ne},
+				},
+			}},
+			expects: &models.DataCoverage{
+				Ratio:                    1,
+				MissingApplications:      []models.AppInstanceId{},
+				MissingNetworkElements:   []models.MissingNetworkElement{},
+				EstimatedNetworkElements: []models.EstimatedNetworkElement{},
+			},
+		},
+		{
+			name: "failed app instance and NEs are listed",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app2.String(), NumberOfTotalNEs: 2},
+				Result: &database.TaskResult{
+					AppInstanceFailure: "energy consumption not available",
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne2": {Failure: "traffic volume not available"},
+						"ne1": ne,
+					},
+				},
+			}, {
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String(), NumberOfTotalNEs: 2},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne3": {Failure: "energy consumption not available"},
+						"ne1": ne,
+					},
+				},
+			}},
+			expects: &models.DataCoverage{
+				Ratio:               3.0 / 6.0,
+				MissingApplications: []models.AppInstanceId{app2},
+				MissingNetworkElements: []models.MissingNetworkElement{
+					{AppInstanceId: app1, NetworkElementId: "ne3", Reason: "energy consumption not available"},
+					{AppInstanceId: app2, NetworkElementId: "ne2", Reason: "traffic volume not available"},
+				},
+				EstimatedNetworkElements: []models.EstimatedNetworkElement{},
+			},
+		},
+		{
+			name: "data missing from several windows is listed once",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String(), NumberOfWindows: 2, NumberOfTotalNEs: 1},
+				Result: &database.TaskResult{
+					AppInstanceFailure: "energy consumption not available",
+					NetworkElements:    map[string]database.NetworkElementResult{"ne1": {Failure: "traffic volume not available"}},
+				},
+			}, {
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String(), Window: 1, NumberOfWindows: 2, NumberOfTotalNEs: 1},
+				Result: &database.TaskResult{
+					AppInstanceFailure: "energy consumption not available",
+					NetworkElements:    map[string]database.NetworkElementResult{"ne1": {Failure: "energy consumption not available"}},
+				},
+			}},
+			expects: &models.DataCoverage{
+				Ratio:               0,
+				MissingApplications: []models.AppInstanceId{app1},
+				MissingNetworkElements: []models.MissingNetworkElement{
+					{AppInstanceId: app1, NetworkElementId: "ne1", Reason: "traffic volume not available"},
+				},
+				EstimatedNetworkElements: []models.EstimatedNetworkElement{},
+			},
+		},
+		{
+			name: "estimated NEs are listed once and not counted as measured",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String(), NumberOfWindows: 2, NumberOfTotalNEs: 2},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": ne,
+						"ne2": {EnergyConsumption: floatPtr(1.0), TrafficEstimate: models.EqualSplit},
+					},
+				},
+			}, {
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String(), Window: 1, NumberOfWindows: 2, NumberOfTotalNEs: 2},
+				Result: &database.TaskResult{
+					AppInstanceEnergyConsumption: floatPtr(1.0),
+					NetworkElements: map[string]database.NetworkElementResult{
+						"ne1": ne,
+						"ne2": {EnergyConsumption: floatPtr(1.0), TrafficEstimate: models.EqualSplit},
+					},
+				},
+			}},
+			expects: &models.DataCoverage{
+				Ratio:                  4.0 / 6.0,
+				MissingApplications:    []models.AppInstanceId{},
+				MissingNetworkElements: []models.MissingNetworkElement{},
+				EstimatedNetworkElements: []models.EstimatedNetworkElement{
+					{AppInstanceId: app1, NetworkElementId: "ne2", Method: models.EqualSplit},
+				},
+			},
+		},
+		{
+			name: "nothing gathered",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: app1.String()},
+				Result:               &database.TaskResult{AppInstanceFailure: "energy consumption not available"},
+			}},
+			expects: &models.DataCoverage{
+				Ratio:                    0,
+				MissingApplications:      []models.AppInstanceId{app1},
+				MissingNetworkElements:   []models.MissingNetworkElement{},
+				EstimatedNetworkElements: []models.EstimatedNetworkElement{},
+			},
+		},
+		{
+			name: "invalid application instance ID",
+			input: []database.JobAppResult{{
+				JobAppResultMetadata: database.JobAppResultMetadata{JobID: "job1", AppID: "app1"},
+			}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coverage, err := Coverage(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.Nil(t, coverage)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expects.Ratio, coverage.Ratio, 1e-9)
+			assert.Equal(t, tt.expects.MissingApplications, coverage.MissingApplications)
+			assert.Equal(t, tt.expects.MissingNetworkElements, coverage.MissingNetworkElements)
+			assert.Equal(t, tt.expects.EstimatedNetworkElements, coverage.EstimatedNetworkElements)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package calculator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+)
+
+// TrafficFallbackFail is the fallback failing, rather than estimating, the traffic of a network element missing
+// from a Traffic Volume response.
+const TrafficFallbackFail = "fail"
+
+// ParseTrafficFallback returns the method estimating the traffic of a network element missing from a Traffic
+// Volume response, as configured, or an empty method for TrafficFallbackFail.
+func ParseTrafficFallback(fallback string) (models.EstimatedNetworkElementMethod, error) {
+	switch method := models.EstimatedNetworkElementMethod(fallback); method {
+	case models.Zero, models.EqualSplit:
+		return method, nil
+	}
+	if fallback == TrafficFallbackFail {
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown missing traffic fallback %q, expected %s, %s or %s", fallback, TrafficFallbackFail, models.Zero, models.EqualSplit)
+}
+
+// sharerKey identifies a network element over a window, by vendor and network element ID.
+type sharerKey struct {
+	window int
+	ne     database.NetworkElementID
+}
+
+// countSharers counts, for each network element over each window, the application instances of data it serves.
+// An equal split divides the network element between them.
+func countSharers(data []database.JobAppResult) map[sharerKey]int {
+	sharers := make(map[sharerKey]int)
+	for _, d := range data {
+		if d.Result == nil {
+			continue
+		}
+		for key, ne := range d.Result.NetworkElements {
+			sharers[sharerKey{d.Window, ne.ID(key)}]++
+		}
+	}
+	return sharers
+}
+
+// trafficShare returns the share of a network element allocated to an application instance: the ratio of its
+// traffic when measured, or the estimated share otherwise. sharers is the number of application instances the
+// network element serves over the window. A network element that carried no traffic is allocated to nobody.
+func trafficShare(ne database.NetworkElementResult, sharers int) float64 {
+	switch ne.TrafficEstimate {
+	case models.Zero:
+		return 0
+	case models.EqualSplit:
+		return 1 / float64(max(sharers, 1))
+	}
+	if *ne.TotalTraffic == 0 {
+		return 0
+	}
+	return *ne.AppInstanceTraffic / *ne.TotalTraffic
+}
+
+// HasEstimates reports whether the traffic of any network element of data was estimated.
+func HasEstimates(data []database.JobAppResult) bool {
+	for _, d := range data {
+		if d.Result == nil {
+			continue
+		}
+		for _, ne := range d.Result.NetworkElements {
+			if ne.Failure == "" && ne.TrafficEstimate != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// vendorID returns the vendor of a network element as reported in a result, nil when unknown.
+func vendorID(ne database.NetworkElementID) *string {
+	if ne.VendorID == "" {
+		return nil
+	}
+	return &ne.VendorID
+}
+
+// compareNetworkElements orders network elements by ID, then by vendor.
+func compareNetworkElements(aID string, aVendor *string, bID string, bVendor *string) int {
+	if c := strings.Compare(aID, bID); c != 0 {
+		return c
+	}
+	var av, bv string
+	if aVendor != nil {
+		av = *aVendor
+	}
+	if bVendor != nil {
+		bv = *bVendor
+	}
+	return strings.Compare(av, bv)
+}
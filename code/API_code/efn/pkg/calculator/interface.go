@@ -18,9 +18,14 @@ package calculator
 import (
 	"context"
 
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/internal/database"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/registry"
 )
 
+// Backends holds the calculators, the one used being selected by name in the configuration.
+var Backends = registry.New[Interface]("calculator")
+
 type Interface interface {
 	// CalculateEnergyConsumption calculates the energy consumption (kWh) for the given application instance, taking into account both application and all network elements consumption.
 	CalculateEnergyConsumption(ctx context.Context, data []database.JobAppResult) (*float64, error)
@@ -28,4 +33,12 @@ type Interface interface {
 	// CalculateCarbonFootprint calculates the carbon footprint (tCO2e - tonnes of CO2 equivalent) for the given application instance.
 	// It first calculates energy consumption, then converts it to CO2 equivalent using the conversion factor.
 	CalculateCarbonFootprint(ctx context.Context, data []database.JobAppResult) (*float64, error)
+
+	// CalculateEnergyConsumptionBreakdown splits the energy consumption (kWh) per application instance and,
+	// for each of them, per network element allocated share.
+	CalculateEnergyConsumptionBreakdown(ctx context.Context, data []database.JobAppResult) (*models.ResultBreakdown, error)
+
+	// CalculateCarbonFootprintBreakdown splits the carbon footprint (tCO2e) per application instance and,
+	// for each of them, per network element allocated share.
+	CalculateCarbonFootprintBreakdown(ctx context.Context, data []database.JobAppResult) (*models.ResultBreakdown, error)
 }
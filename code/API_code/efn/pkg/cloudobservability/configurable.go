@@ -24,16 +24,21 @@ import (
 	"time"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
 )
 
 var _ Interface = &configurableClient{}
 
+func init() {
+	Backends.Register("configurable", func() (Interface, error) { return NewConfigurableClient() })
+}
+
 // configurableClient allows configuring behavior via environment variables:
 // - CLOUDOBS_CONFIG_APP_VALUE: Default app energy consumption value (default: 0.0020)
 // - CLOUDOBS_CONFIG_NE_VALUE: Default NE energy consumption value (default: 0.0010)
 // - CLOUDOBS_CONFIG_SUCCESS_COUNT: If >0, always succeed (default: 0)
 // - CLOUDOBS_CONFIG_ERROR_COUNT: If >0 and successCount=0, always fail (default: 0)
-// - CLOUDOBS_CONFIG_ERROR_TYPE: Type of error to return: "permanent" or "throttling" (default: "throttling")
+// - CLOUDOBS_CONFIG_ERROR_TYPE: Type of error to return: "throttling", "retryable", "permanent", "not-found" or "unauthorized" (default: "throttling")
 // - CLOUDOBS_CONFIG_DELAY_MS: Request processing delay in milliseconds (default: 0)
 //
 // Behavior:
@@ -82,7 +87,7 @@ func NewConfigurableClient() (*configurableClient, error) {
 
 	errorType := "throttling"
 	if val := os.Getenv("CLOUDOBS_CONFIG_ERROR_TYPE"); val != "" {
-		if val == "throttling" || val == "permanent" {
+		if _, ok := configurableErrorClasses[val]; ok {
 			errorType = val
 		}
 	}
@@ -120,11 +125,20 @@ func (c *configurableClient) shouldReturnError() (bool, error) {
 	return false, nil
 }
 
+// configurableErrorClasses maps the error types of the configurable client to their class.
+var configurableErrorClasses = map[string]datasource.Class{
+	"throttling":   datasource.ClassThrottled,
+	"retryable":    datasource.ClassRetryable,
+	"permanent":    datasource.ClassPermanent,
+	"not-found":    datasource.ClassNotFound,
+	"unauthorized": datasource.ClassUnauthorized,
+}
+
 func (c *configurableClient) makeError() error {
-	if c.errorType == "throttling" {
-		return NewThrottlingError(fmt.Sprintf("configurable throttling error (request #%d)", c.requestCount))
+	return &datasource.Error{
+		Class:   configurableErrorClasses[c.errorType],
+		Message: fmt.Sprintf("configurable %s error (request #%d)", c.errorType, c.requestCount),
 	}
-	return fmt.Errorf("configurable permanent error (request #%d)", c.requestCount)
 }
 
 func (c *configurableClient) RetrieveAppEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, appInfraType string) (*float64, error) {
@@ -143,7 +157,7 @@ func (c *configurableClient) RetrieveAppEnergyConsumption(ctx context.Context, a
 	return &value, nil
 }
 
-func (c *configurableClient) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
+func (c *configurableClient) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID, neInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
 	if c.delay > 0 {
 		select {
 		case <-time.After(c.delay):
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
 )
 
 func TestNewConfigurableClient(t *testing.T) {
@@ -245,11 +246,11 @@ func TestConfigurableClient_RetrieveAppEnergyConsumption(t *testing.T) {
 					if err == nil {
 						t.Errorf("request %d: expected error but got none", i+1)
 					} else if want.wantErrType == "throttling" {
-						if !IsThrottlingError(err) {
+						if !datasource.IsThrottled(err) {
 							t.Errorf("request %d: expected throttling error, got: %v", i+1, err)
 						}
 					} else {
-						if IsThrottlingError(err) {
+						if datasource.IsThrottled(err) {
 							t.Errorf("request %d: expected permanent error, got throttling error", i+1)
 						}
 					}
@@ -336,17 +337,17 @@ func TestConfigurableClient_RetrieveNetworkElementEnergyConsumption(t *testing.T
 			timePeriod := &models.TimePeriod{}
 
 			for i, want := range tt.wantResults {
-				result, err := client.RetrieveNetworkElementEnergyConsumption(ctx, "app-123", timePeriod, "router")
+				result, err := client.RetrieveNetworkElementEnergyConsumption(ctx, "app-123", "ne-1", timePeriod, "router")
 
 				if want.wantErr {
 					if err == nil {
 						t.Errorf("request %d: expected error but got none", i+1)
 					} else if want.wantErrType == "throttling" {
-						if !IsThrottlingError(err) {
+						if !datasource.IsThrottled(err) {
 							t.Errorf("request %d: expected throttling error, got: %v", i+1, err)
 						}
 					} else {
-						if IsThrottlingError(err) {
+						if datasource.IsThrottled(err) {
 							t.Errorf("request %d: expected permanent error, got throttling error", i+1)
 						}
 					}
@@ -369,21 +370,27 @@ func TestConfigurableClient_MakeError(t *testing.T) {
 	tests := []struct {
 		name          string
 		errorType     string
-		wantThrottle  bool
+		wantClass     datasource.Class
 		requestNumber int
 	}{
 		{
 			name:          "permanent error",
 			errorType:     "permanent",
-			wantThrottle:  false,
+			wantClass:     datasource.ClassPermanent,
 			requestNumber: 1,
 		},
 		{
 			name:          "throttling error",
 			errorType:     "throttling",
-			wantThrottle:  true,
+			wantClass:     datasource.ClassThrottled,
 			requestNumber: 5,
 		},
+		{
+			name:          "not found error",
+			errorType:     "not-found",
+			wantClass:     datasource.ClassNotFound,
+			requestNumber: 2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -399,13 +406,11 @@ func TestConfigurableClient_MakeError(t *testing.T) {
 				t.Fatal("expected error but got nil")
 			}
 
-			if IsThrottlingError(err) != tt.wantThrottle {
-				t.Errorf("IsThrottlingError() = %v, want %v", IsThrottlingError(err), tt.wantThrottle)
+			if datasource.ClassOf(err) != tt.wantClass {
+				t.Errorf("ClassOf() = %v, want %v", datasource.ClassOf(err), tt.wantClass)
 			}
 
-			expectedMsg := fmt.Sprintf("configurable %s error (request #%d)",
-				map[bool]string{true: "throttling", false: "permanent"}[tt.wantThrottle],
-				tt.requestNumber)
+			expectedMsg := fmt.Sprintf("configurable %s error (request #%d)", tt.errorType, tt.requestNumber)
 
 			if err.Error() != expectedMsg {
 				t.Errorf("error message = %v, want %v", err.Error(), expectedMsg)
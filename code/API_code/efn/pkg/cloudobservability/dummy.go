@@ -23,6 +23,10 @@ import (
 
 var _ Interface = &dummyClient{}
 
+func init() {
+	Backends.Register("dummy", func() (Interface, error) { return NewDummyClient() })
+}
+
 type dummyClient struct{}
 
 func NewDummyClient() (*dummyClient, error) {
@@ -34,7 +38,7 @@ func (k *dummyClient) RetrieveAppEnergyConsumption(ctx context.Context, appInsta
 	return &value, nil
 }
 
-func (k *dummyClient) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
+func (k *dummyClient) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID, neInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
 	value := float64(0.0010)
 	return &value, nil
 }
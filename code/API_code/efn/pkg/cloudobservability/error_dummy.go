@@ -20,8 +20,15 @@ import (
 	"os"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
 )
 
+func init() {
+	Backends.Register("error-dummy", NewErrorDummyClient)
+}
+
+// errorDummyClient fails the calls as set by CLOUDOBS_FAIL_THROTTLE and CLOUDOBS_FAIL_NE, read on every
+// call, to exercise the retries and the dead letter sink.
 type errorDummyClient struct{}
 
 func NewErrorDummyClient() (Interface, error) {
@@ -30,24 +37,24 @@ func NewErrorDummyClient() (Interface, error) {
 
 func (c *errorDummyClient) RetrieveAppEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, appInfraType string) (*float64, error) {
 	if os.Getenv("CLOUDOBS_FAIL_THROTTLE") == "true" {
-		return nil, NewThrottlingError("Max requests queued per destination 1024 exceeded")
+		return nil, datasource.NewThrottled("Max requests queued per destination 1024 exceeded", 0)
 	}
 
 	if os.Getenv("CLOUDOBS_FAIL_NE") == "true" {
-		return nil, ErrPermanentFailure
+		return nil, datasource.NewPermanent("permanent failure")
 	}
 
 	value := float64(0.0020)
 	return &value, nil
 }
 
-func (c *errorDummyClient) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
+func (c *errorDummyClient) RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID, neInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error) {
 	if os.Getenv("CLOUDOBS_FAIL_THROTTLE") == "true" {
-		return nil, NewThrottlingError("Max requests queued per destination 1024 exceeded")
+		return nil, datasource.NewThrottled("Max requests queued per destination 1024 exceeded", 0)
 	}
 
 	if os.Getenv("CLOUDOBS_FAIL_NE") == "true" {
-		return nil, ErrPermanentFailure
+		return nil, datasource.NewPermanent("permanent failure")
 	}
 
 	value := float64(0.0010)
@@ -19,12 +19,19 @@ import (
 	"context"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/registry"
 )
 
+// Backends holds the cloud observability adapters, the one used being selected by name in the configuration.
+var Backends = registry.New[Interface]("cloud observability")
+
+// Interface is implemented by the cloud observability adapters. Their errors are classified with datasource.Error,
+// an unclassified error is retried.
 type Interface interface {
 	// RetrieveAppEnergyConsumption returns the energy consumption (kWh) for the given application instance.
 	RetrieveAppEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, appInfraType string) (*float64, error)
 
-	// RetrieveNetworkElementEnergyConsumption returns the energy consumption (kWh) for the given network element.
-	RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error)
+	// RetrieveNetworkElementEnergyConsumption returns the energy consumption (kWh) for the given network element
+	// serving the application instance.
+	RetrieveNetworkElementEnergyConsumption(ctx context.Context, appInstanceID, neInstanceID string, timePeriod *models.TimePeriod, neInfraType string) (*float64, error)
 }
@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -18,6 +18,7 @@ package config
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
@@ -28,8 +29,12 @@ type Log struct {
 }
 
 type API struct {
-	Address           string `split_words:"true" default:"0.0.0.0:8080"`
-	MaxTimePeriodDays int    `split_words:"true" default:"730" description:"Maximum allowed time period in days for historical data queries. Default is 730 days (2 years)."`
+	Address             string        `split_words:"true" default:"0.0.0.0:8080"`
+	MaxTimePeriodDays   int           `split_words:"true" default:"730" description:"Maximum allowed time period in days for historical data queries. Default is 730 days (2 years)."`
+	IdempotencyKeyTTL   time.Duration `split_words:"true" default:"24h" description:"How long an idempotency key is remembered. Retries with the same key after this delay create a new report."`
+	IdempotencyKeyLease time.Duration `split_words:"true" default:"2m" description:"How long a submission in progress holds its idempotency key. A retry after this delay takes over a submission interrupted before answering."`
+	SyncMaxApplications int           `split_words:"true" default:"5" description:"Maximum number of application instances of a report calculated synchronously when asked with the Prefer header."`
+	SyncMaxWait         time.Duration `split_words:"true" default:"60s" description:"Upper bound of the wait asked with the Prefer header before falling back to the asynchronous flow."`
 }
 
 type Database struct {
@@ -48,12 +53,87 @@ type HTTP struct {
 	InsecureSkipVerify bool `split_words:"true" default:"false" description:"If true, skip TLS certificate verification for internal cluster services."`
 }
 
+// Scheduler of the periodic reports
+type Scheduler struct {
+	LeaseDuration time.Duration `split_words:"true" default:"5m" description:"How long a periodic report is reserved by the worker starting one of its runs. A run left unfinished by a crashed worker is started again after this delay."`
+}
+
+// Quota limits the load each principal puts on the calculate endpoints. A zero limit disables the check.
+type Quota struct {
+	RequestsPerMinute         int           `split_words:"true" default:"60" description:"Maximum number of calculate requests a principal can send per minute."`
+	MaxConcurrentJobs         int           `split_words:"true" default:"20" description:"Maximum number of reports of a principal being processed at the same time. Periodic reports count through their runs only."`
+	MaxApplicationsPerRequest int           `split_words:"true" default:"50" description:"Maximum number of application instances in a single calculate request."`
+	ConcurrencyRetryAfter     time.Duration `split_words:"true" default:"30s" description:"Delay suggested in the Retry-After header when the concurrent reports limit is reached."`
+}
+
+// Watchdog of the jobs that never complete, run by the worker
+type Watchdog struct {
+	Interval   time.Duration `split_words:"true" default:"1m" description:"Time between two sweeps of the stuck jobs. Zero disables the watchdog."`
+	Deadline   time.Duration `split_words:"true" default:"15m" description:"Age after which a job still in progress is considered stuck, counted again from each resume."`
+	MaxResumes int           `split_words:"true" default:"2" description:"Number of times the missing events of a stuck job are sent again before it is failed with a timeout."`
+	BatchSize  int           `split_words:"true" default:"100" description:"Maximum number of stuck jobs handled by a sweep."`
+}
+
+// Gathering of the energy and traffic data by the worker
+type Gathering struct {
+	WindowSize time.Duration `split_words:"true" default:"24h" description:"Length of the windows the time period of a report is split into, each fetched from the backends separately. Zero fetches the whole period at once."`
+}
+
+// Results of the calculations made by the worker
+type Results struct {
+	AllowPartial   bool   `split_words:"true" default:"false" description:"Calculate every report with the data available when part of it cannot be retrieved, as if all requests allowed partial results."`
+	MissingTraffic string `split_words:"true" default:"fail" description:"What to do with a network element missing from a Traffic Volume response: fail it, or estimate its share of the energy as zero or as an equal split between the application instances of the report it serves (zero, equal-split)."`
+}
+
+// Backend selects the implementation of each backend by name, and protects each external data backend
+// from the load of a replica. Every data backend has its own circuit breaker, rate limit and concurrency
+// limit, all local to the replica.
+type Backend struct {
+	Orchestrator       string        `default:"dummy" description:"Name of the orchestrator backend."`
+	CloudObservability string        `split_words:"true" description:"Name of the cloud observability backend. Empty selects it with the deprecated CLIENT_TYPE, CLOUDOBS_FAIL_NE and CLOUDOBS_FAIL_THROTTLE variables."`
+	TrafficVolume      string        `split_words:"true" description:"Name of the traffic volume backend. Empty selects it with the deprecated TRAFFIC_CLIENT_TYPE variable."`
+	Calculator         string        `default:"simple" description:"Name of the calculator."`
+	BreakerThreshold   int           `split_words:"true" default:"5" description:"Number of consecutive failed or throttled calls opening the circuit breaker of a backend. Zero disables the breaker."`
+	BreakerCooldown    time.Duration `split_words:"true" default:"30s" description:"How long an open circuit breaker rejects the calls before letting a single probe call through."`
+	RateLimit          float64       `split_words:"true" default:"20" description:"Calls per second allowed to a backend. Zero disables the rate limit."`
+	RateBurst          int           `split_words:"true" default:"10" description:"Calls allowed to a backend in a burst above the rate limit."`
+	MaxConcurrency     int           `split_words:"true" default:"8" description:"Upper bound of the calls in flight to a backend. The actual limit is halved on throttling and grows back on success. Zero disables the limit."`
+	MaxWait            time.Duration `split_words:"true" default:"2s" description:"How long a call waits for the rate or concurrency limit before the backend is reported unavailable."`
+}
+
+// Cache of the network element measurements, shared by the jobs of the worker
+type Cache struct {
+	TTL         time.Duration `split_words:"true" default:"1h" description:"How long a network element energy consumption or traffic volume is reused by the jobs asking for it again. Zero disables the cache."`
+	MaxEntries  int           `split_words:"true" default:"10000" description:"Maximum number of measurements kept in the memory of a replica."`
+	Shared      bool          `split_words:"true" default:"true" description:"Store the measurements in the database as well, so that all replicas reuse them."`
+	CallTimeout time.Duration `split_words:"true" default:"1m" description:"Upper bound of a backend call shared by the concurrent lookups of the same measurement, which continues when one of its callers gives up. Zero disables the bound."`
+}
+
+// Ledger of the events processed by the worker, acknowledging their redeliveries without handling them again
+type Ledger struct {
+	TTL time.Duration `split_words:"true" default:"24h" description:"How long a processed event is remembered. It should outlast the retries of the broker and the resumes of the watchdog. Zero disables the ledger."`
+}
+
+// Delivery of the notifications to the sinks of the subscribers
+type Delivery struct {
+	LeaseDuration time.Duration `split_words:"true" default:"2m" description:"How long an attempt to deliver a notification holds it. The delivery left by a crashed attempt is claimed again after this delay. It should exceed the timeout of the callback."`
+}
+
 type Config struct {
 	API
 	Database
 	Log
 	PDP
 	HTTP
+	Scheduler
+	Quota
+	Watchdog
+	Gathering
+	Results
+	Backend
+	Cache
+	Ledger
+	Delivery
 }
 
 func process(prefix string, spec interface{}) {
@@ -78,7 +158,34 @@ func GetConf() Config {
 	var http HTTP
 	process("http", &http)
 
-	return Config{api, db, log, policy, http}
+	var scheduler Scheduler
+	process("scheduler", &scheduler)
+
+	var quota Quota
+	process("quota", &quota)
+
+	var watchdog Watchdog
+	process("watchdog", &watchdog)
+
+	var gathering Gathering
+	process("gathering", &gathering)
+
+	var results Results
+	process("results", &results)
+
+	var backend Backend
+	process("backend", &backend)
+
+	var cache Cache
+	process("cache", &cache)
+
+	var ledger Ledger
+	process("ledger", &ledger)
+
+	var delivery Delivery
+	process("delivery", &delivery)
+
+	return Config{api, db, log, policy, http, scheduler, quota, watchdog, gathering, results, backend, cache, ledger, delivery}
 }
 
 var (
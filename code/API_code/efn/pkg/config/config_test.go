@@ -17,6 +17,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,6 +39,118 @@ func Test_Config(t *testing.T) {
 		res := GetConf().API
 		assert.Equal(t, 730, res.MaxTimePeriodDays)
 	})
+	t.Run("correctly parse API idempotency key TTL", func(t *testing.T) {
+		t.Setenv("API_IDEMPOTENCY_KEY_TTL", "1h30m")
+		res := GetConf().API
+		assert.Equal(t, 90*time.Minute, res.IdempotencyKeyTTL)
+	})
+	t.Run("use default idempotency key TTL when not set", func(t *testing.T) {
+		res := GetConf().API
+		assert.Equal(t, 24*time.Hour, res.IdempotencyKeyTTL)
+	})
+	t.Run("correctly parse API idempotency key lease", func(t *testing.T) {
+		t.Setenv("API_IDEMPOTENCY_KEY_LEASE", "90s")
+		res := GetConf().API
+		assert.Equal(t, 90*time.Second, res.IdempotencyKeyLease)
+	})
+	t.Run("use default idempotency key lease when not set", func(t *testing.T) {
+		res := GetConf().API
+		assert.Equal(t, 2*time.Minute, res.IdempotencyKeyLease)
+	})
+	t.Run("correctly parse API synchronous mode limits", func(t *testing.T) {
+		t.Setenv("API_SYNC_MAX_APPLICATIONS", "2")
+		t.Setenv("API_SYNC_MAX_WAIT", "10s")
+		res := GetConf().API
+		assert.Equal(t, 2, res.SyncMaxApplications)
+		assert.Equal(t, 10*time.Second, res.SyncMaxWait)
+	})
+	t.Run("correctly parse scheduler lease duration", func(t *testing.T) {
+		t.Setenv("SCHEDULER_LEASE_DURATION", "2m")
+		res := GetConf().Scheduler
+		assert.Equal(t, 2*time.Minute, res.LeaseDuration)
+	})
+	t.Run("correctly parse quota environment variables", func(t *testing.T) {
+		t.Setenv("QUOTA_REQUESTS_PER_MINUTE", "10")
+		t.Setenv("QUOTA_MAX_CONCURRENT_JOBS", "3")
+		t.Setenv("QUOTA_MAX_APPLICATIONS_PER_REQUEST", "7")
+		t.Setenv("QUOTA_CONCURRENCY_RETRY_AFTER", "1m")
+		res := GetConf().Quota
+		assert.Equal(t, 10, res.RequestsPerMinute)
+		assert.Equal(t, 3, res.MaxConcurrentJobs)
+		assert.Equal(t, 7, res.MaxApplicationsPerRequest)
+		assert.Equal(t, time.Minute, res.ConcurrencyRetryAfter)
+	})
+	t.Run("correctly parse watchdog environment variables", func(t *testing.T) {
+		t.Setenv("WATCHDOG_INTERVAL", "30s")
+		t.Setenv("WATCHDOG_DEADLINE", "1h")
+		t.Setenv("WATCHDOG_MAX_RESUMES", "4")
+		t.Setenv("WATCHDOG_BATCH_SIZE", "10")
+		res := GetConf().Watchdog
+		assert.Equal(t, 30*time.Second, res.Interval)
+		assert.Equal(t, time.Hour, res.Deadline)
+		assert.Equal(t, 4, res.MaxResumes)
+		assert.Equal(t, 10, res.BatchSize)
+	})
+	t.Run("correctly parse gathering window size", func(t *testing.T) {
+		t.Setenv("GATHERING_WINDOW_SIZE", "6h")
+		res := GetConf().Gathering
+		assert.Equal(t, 6*time.Hour, res.WindowSize)
+	})
+	t.Run("use default gathering window size when not set", func(t *testing.T) {
+		res := GetConf().Gathering
+		assert.Equal(t, 24*time.Hour, res.WindowSize)
+	})
+	t.Run("correctly parse results environment variables", func(t *testing.T) {
+		t.Setenv("RESULTS_ALLOW_PARTIAL", "true")
+		t.Setenv("RESULTS_MISSING_TRAFFIC", "equal-split")
+		res := GetConf().Results
+		assert.True(t, res.AllowPartial)
+		assert.Equal(t, "equal-split", res.MissingTraffic)
+	})
+	t.Run("correctly parse backend environment variables", func(t *testing.T) {
+		t.Setenv("BACKEND_ORCHESTRATOR", "kubernetes")
+		t.Setenv("BACKEND_CLOUD_OBSERVABILITY", "prometheus")
+		t.Setenv("BACKEND_TRAFFIC_VOLUME", "configurable")
+		t.Setenv("BACKEND_CALCULATOR", "simple")
+		t.Setenv("BACKEND_BREAKER_THRESHOLD", "3")
+		t.Setenv("BACKEND_BREAKER_COOLDOWN", "1m")
+		t.Setenv("BACKEND_RATE_LIMIT", "2.5")
+		t.Setenv("BACKEND_RATE_BURST", "4")
+		t.Setenv("BACKEND_MAX_CONCURRENCY", "16")
+		t.Setenv("BACKEND_MAX_WAIT", "500ms")
+		res := GetConf().Backend
+		assert.Equal(t, "kubernetes", res.Orchestrator)
+		assert.Equal(t, "prometheus", res.CloudObservability)
+		assert.Equal(t, "configurable", res.TrafficVolume)
+		assert.Equal(t, "simple", res.Calculator)
+		assert.Equal(t, 3, res.BreakerThreshold)
+		assert.Equal(t, time.Minute, res.BreakerCooldown)
+		assert.Equal(t, 2.5, res.RateLimit)
+		assert.Equal(t, 4, res.RateBurst)
+		assert.Equal(t, 16, res.MaxConcurrency)
+		assert.Equal(t, 500*time.Millisecond, res.MaxWait)
+	})
+	t.Run("correctly parse cache environment variables", func(t *testing.T) {
+		t.Setenv("CACHE_TTL", "10m")
+		t.Setenv("CACHE_MAX_ENTRIES", "50")
+		t.Setenv("CACHE_SHARED", "false")
+		t.Setenv("CACHE_CALL_TIMEOUT", "30s")
+		res := GetConf().Cache
+		assert.Equal(t, 10*time.Minute, res.TTL)
+		assert.Equal(t, 50, res.MaxEntries)
+		assert.False(t, res.Shared)
+		assert.Equal(t, 30*time.Second, res.CallTimeout)
+	})
+	t.Run("correctly parse ledger environment variables", func(t *testing.T) {
+		t.Setenv("LEDGER_TTL", "2h")
+		res := GetConf().Ledger
+		assert.Equal(t, 2*time.Hour, res.TTL)
+	})
+	t.Run("correctly parse delivery environment variables", func(t *testing.T) {
+		t.Setenv("DELIVERY_LEASE_DURATION", "90s")
+		res := GetConf().Delivery
+		assert.Equal(t, 90*time.Second, res.LeaseDuration)
+	})
 	t.Run("correctly parse database environment variables", func(t *testing.T) {
 		t.Setenv("DB_URI", "http://127.0.0.1:6969")
 		t.Setenv("DB_NAME", "thisDB")
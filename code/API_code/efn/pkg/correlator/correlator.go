@@ -0,0 +1,44 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package correlator
+
+import "context"
+
+const (
+	// Header is the HTTP header carrying the correlation ID, as defined by the CAMARA guidelines.
+	Header = "x-correlator"
+	// Extension is the CloudEvents extension carrying the correlation ID between services.
+	// Extension names are restricted to lowercase letters and digits, hence the missing dash.
+	Extension = "xcorrelator"
+	// LogKey is the name of the log field holding the correlation ID.
+	LogKey = "xCorrelator"
+)
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx carrying the correlation ID. An empty ID leaves ctx unchanged.
+func NewContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the correlation ID carried by ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package datasource
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Class tells the caller of a data source (orchestrator, cloud observability, traffic volume) what to do
+// with one of its errors, the same way whatever the data source.
+type Class string
+
+const (
+	// ClassRetryable is a transient failure: the same call may succeed later on.
+	ClassRetryable Class = "retryable"
+	// ClassThrottled is a call refused because too many are made: it may succeed later on, with fewer calls.
+	ClassThrottled Class = "throttled"
+	// ClassPermanent is a failure the same call will meet again.
+	ClassPermanent Class = "permanent"
+	// ClassNotFound is an application instance or network element the data source does not know.
+	ClassNotFound Class = "not-found"
+	// ClassUnauthorized is a call the data source does not allow.
+	ClassUnauthorized Class = "unauthorized"
+)
+
+// Error is a classified error of a data source.
+type Error struct {
+	Class   Class
+	Message string
+	// RetryAfter is the delay suggested before calling again, zero when unknown.
+	RetryAfter time.Duration
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewRetryable(message string, retryAfter time.Duration) error {
+	return &Error{Class: ClassRetryable, Message: message, RetryAfter: retryAfter}
+}
+
+func NewThrottled(message string, retryAfter time.Duration) error {
+	return &Error{Class: ClassThrottled, Message: message, RetryAfter: retryAfter}
+}
+
+func NewPermanent(message string) error {
+	return &Error{Class: ClassPermanent, Message: message}
+}
+
+func NewNotFound(message string) error {
+	return &Error{Class: ClassNotFound, Message: message}
+}
+
+func NewUnauthorized(message string) error {
+	return &Error{Class: ClassUnauthorized, Message: message}
+}
+
+// Wrap classifies err, e.g. an error of the HTTP client of an adapter.
+func Wrap(class Class, message string, err error) error {
+	return &Error{Class: class, Message: message, Err: err}
+}
+
+// ClassOf returns the class of err. An error the adapter did not classify, a timeout for instance,
+// is retryable: the retries of its event are bounded, the job fails once they are exhausted.
+func ClassOf(err error) Class {
+	var dsErr *Error
+	if errors.As(err, &dsErr) {
+		return dsErr.Class
+	}
+	return ClassRetryable
+}
+
+// IsRetryable reports whether the call failing with err can be made again later on, rather than
+// failing the job.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	class := ClassOf(err)
+	return class == ClassRetryable || class == ClassThrottled
+}
+
+func IsThrottled(err error) bool {
+	return err != nil && ClassOf(err) == ClassThrottled
+}
+
+// IsCancelled reports whether err comes from the caller giving up, rather than from the data source.
+func IsCancelled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// RetryAfter returns the delay suggested by err before calling again, zero when unknown.
+func RetryAfter(err error) time.Duration {
+	var dsErr *Error
+	if errors.As(err, &dsErr) {
+		return dsErr.RetryAfter
+	}
+	return 0
+}
+
+// HTTPStatus returns the status reported to the subscriber of a job failed by err.
+func HTTPStatus(err error) int {
+	switch ClassOf(err) {
+	case ClassNotFound:
+		return http.StatusNotFound
+	case ClassUnauthorized:
+		return http.StatusForbidden
+	case ClassPermanent:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package datasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassification(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantClass      Class
+		wantRetryable  bool
+		wantRetryAfter time.Duration
+		wantStatus     int
+	}{
+		{
+			name:           "retryable",
+			err:            NewRetryable("backend unavailable", time.Minute),
+			wantClass:      ClassRetryable,
+			wantRetryable:  true,
+			wantRetryAfter: time.Minute,
+			wantStatus:     http.StatusServiceUnavailable,
+		},
+		{
+			name:           "throttled, wrapped by the caller",
+			err:            fmt.Errorf("failed to retrieve traffic: %w", NewThrottled("too many requests", time.Second)),
+			wantClass:      ClassThrottled,
+			wantRetryable:  true,
+			wantRetryAfter: time.Second,
+			wantStatus:     http.StatusServiceUnavailable,
+		},
+		{
+			name:       "permanent",
+			err:        Wrap(ClassPermanent, "invalid response", errors.New("unexpected EOF")),
+			wantClass:  ClassPermanent,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "not found",
+			err:        NewNotFound("unknown application instance"),
+			wantClass:  ClassNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unauthorized",
+			err:        NewUnauthorized("token rejected"),
+			wantClass:  ClassUnauthorized,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:          "unclassified",
+			err:           errors.New("connection reset"),
+			wantClass:     ClassRetryable,
+			wantRetryable: true,
+			wantStatus:    http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantClass, ClassOf(tt.err))
+			assert.Equal(t, tt.wantRetryable, IsRetryable(tt.err))
+			assert.Equal(t, tt.wantClass == ClassThrottled, IsThrottled(tt.err))
+			assert.Equal(t, tt.wantRetryAfter, RetryAfter(tt.err))
+			assert.Equal(t, tt.wantStatus, HTTPStatus(tt.err))
+		})
+	}
+
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsThrottled(nil))
+}
+
+func TestWrapKeepsTheCause(t *testing.T) {
+	err := Wrap(ClassRetryable, "failed to call the orchestrator", context.DeadlineExceeded)
+
+	assert.EqualError(t, err, "failed to call the orchestrator: context deadline exceeded")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, IsCancelled(err))
+}
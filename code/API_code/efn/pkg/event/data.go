@@ -17,6 +17,7 @@ package event
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
 
@@ -30,6 +31,15 @@ type GatherInfoData struct {
 	ApplicationInstanceID string `json:"applicationInstanceId"`
 }
 
+// Window identifies the sub-window of the time period of a request that an event gathers data for.
+// The zero value is the whole period, not split.
+type Window struct {
+	// Index is the 0-based position of the window in the period.
+	Index int `json:"window,omitempty"`
+	// Count is the number of windows the period is split into.
+	Count int `json:"numberOfWindows,omitempty"`
+}
+
 // AppConsumptionData is the CloudEvent payload for app-level energy consumption requests.
 type AppConsumptionData struct {
 	RequestID             string             `json:"requestId"`
@@ -37,6 +47,7 @@ type AppConsumptionData struct {
 	TimePeriod            *models.TimePeriod `json:"timePeriod"`
 	AppInfraType          string             `json:"appInfraType"`
 	NumberOfTotalNEs      int                `json:"numberOfTotalNEs"`
+	Window
 }
 
 // NetworkElementInfo contains information about a single network element.
@@ -52,9 +63,11 @@ type NetworkElementEnergyData struct {
 	RequestID             string             `json:"requestId"`
 	ApplicationInstanceID string             `json:"applicationInstanceId"`
 	NEInstanceID          string             `json:"neInstanceId"`
+	VendorID              string             `json:"vendorId,omitempty"`
 	NEInfraType           string             `json:"neInfraType"`
 	TimePeriod            *models.TimePeriod `json:"timePeriod"`
 	NumberOfTotalNEs      int                `json:"numberOfTotalNEs"`
+	Window
 }
 
 // NetworkElementTrafficData is the CloudEvent payload for batch traffic volume requests to TrafficVolumeAPI.
@@ -64,6 +77,7 @@ type NetworkElementTrafficData struct {
 	AppInstanceIPList     []string             `json:"appInstanceIpList"`
 	TimePeriod            *models.TimePeriod   `json:"timePeriod"`
 	NetworkElements       []NetworkElementInfo `json:"networkElements"`
+	Window
 }
 
 // NotificationRequestedData is the CloudEvent payload for notification requested events.
@@ -78,6 +92,11 @@ type NotificationErrorRequestedData struct {
 	models.ErrorInfo
 }
 
+// NotificationCancelledRequestedData is the CloudEvent payload for cancellation notification events.
+type NotificationCancelledRequestedData struct {
+	RequestID string `json:"requestId"`
+}
+
 // CalculationRequestedData is the CloudEvent payload for the CalculationRequested event.
 // The data structure is intentionally empty; all data is retrieved from the database.
 type CalculationRequestedData struct{}
@@ -92,13 +111,14 @@ func NewGatherInfoData(requestID, appInstanceID string) GatherInfoData {
 }
 
 // NewAppConsumptionData returns the payload for an app-level consumption event.
-func NewAppConsumptionData(requestID, appInstanceID string, timePeriod *models.TimePeriod, appInfraType string, numberOfTotalNEs int) AppConsumptionData {
+func NewAppConsumptionData(requestID, appInstanceID string, timePeriod *models.TimePeriod, appInfraType string, numberOfTotalNEs int, window Window) AppConsumptionData {
 	return AppConsumptionData{
 		RequestID:             requestID,
 		ApplicationInstanceID: appInstanceID,
 		TimePeriod:            timePeriod,
 		AppInfraType:          appInfraType,
 		NumberOfTotalNEs:      numberOfTotalNEs,
+		Window:                window,
 	}
 }
 
@@ -110,31 +130,55 @@ func NewNotificationRequestedData(requestID string, result float64) Notification
 	}
 }
 
+// camaraCodes are the CAMARA error codes reported in the error callback for each status a job fails with.
+var camaraCodes = map[int]string{
+	http.StatusForbidden:           "PERMISSION_DENIED",
+	http.StatusNotFound:            "NOT_FOUND",
+	http.StatusTooManyRequests:     "TOO_MANY_REQUESTS",
+	http.StatusInternalServerError: "INTERNAL",
+	http.StatusServiceUnavailable:  "UNAVAILABLE",
+	http.StatusGatewayTimeout:      "TIMEOUT",
+}
+
 // NewNotificationErrorRequestedData returns the payload for an error notification event.
 func NewNotificationErrorRequestedData(requestID string, status int, message string) NotificationErrorRequestedData {
+	code, ok := camaraCodes[status]
+	if !ok {
+		code = http.StatusText(status)
+	}
 	return NotificationErrorRequestedData{
 		RequestID: requestID,
 		ErrorInfo: models.ErrorInfo{
 			Status:  status,
-			Code:    http.StatusText(status),
+			Code:    code,
 			Message: message,
 		},
 	}
 }
 
+// NewNotificationCancelledRequestedData returns the payload for a cancellation notification event.
+func NewNotificationCancelledRequestedData(requestID string) NotificationCancelledRequestedData {
+	return NotificationCancelledRequestedData{
+		RequestID: requestID,
+	}
+}
+
 // NewNetworkElementEnergyData returns the payload for a NetworkElementEnergyRequested event.
 func NewNetworkElementEnergyData(
-	requestID, appInstanceID, neInstanceID, neInfraType string,
+	requestID, appInstanceID, vendorID, neInstanceID, neInfraType string,
 	timePeriod *models.TimePeriod,
 	numberOfTotalNEs int,
+	window Window,
 ) NetworkElementEnergyData {
 	return NetworkElementEnergyData{
 		RequestID:             requestID,
 		ApplicationInstanceID: appInstanceID,
 		NEInstanceID:          neInstanceID,
+		VendorID:              vendorID,
 		NEInfraType:           neInfraType,
 		TimePeriod:            timePeriod,
 		NumberOfTotalNEs:      numberOfTotalNEs,
+		Window:                window,
 	}
 }
 
@@ -144,6 +188,7 @@ func NewNetworkElementTrafficData(
 	appInstanceIPList []string,
 	timePeriod *models.TimePeriod,
 	networkElements []NetworkElementInfo,
+	window Window,
 ) NetworkElementTrafficData {
 	return NetworkElementTrafficData{
 		RequestID:             requestID,
@@ -151,6 +196,7 @@ func NewNetworkElementTrafficData(
 		AppInstanceIPList:     appInstanceIPList,
 		TimePeriod:            timePeriod,
 		NetworkElements:       networkElements,
+		Window:                window,
 	}
 }
 
@@ -167,16 +213,39 @@ func EventIDForApp(requestID, appInstanceID string) string {
 	return uuid.NewSHA1(baseNS, []byte(name)).String()
 }
 
-// EventIDForNE returns a deterministic UUIDv5 for a single network element energy request.
-func EventIDForNE(requestID, appInstanceID, neInstanceID string) string {
+// EventIDForAppWindow returns a deterministic UUIDv5 for an app-level consumption request over a window.
+// It is EventIDForApp for the first window, so that requests over a period that is not split keep their ID.
+func EventIDForAppWindow(requestID, appInstanceID string, window int) string {
+	if window == 0 {
+		return EventIDForApp(requestID, appInstanceID)
+	}
+	baseNS := uuid.NewSHA1(uuid.NameSpaceURL, []byte("camara-efn-api:event-id"))
+	name := requestID + "\x00" + appInstanceID + windowSuffix(window)
+	return uuid.NewSHA1(baseNS, []byte(name)).String()
+}
+
+// EventIDForNE returns a deterministic UUIDv5 for a single network element energy request over a window.
+// Network elements of different vendors may share an ID: the vendor, when known, is part of the name.
+func EventIDForNE(requestID, appInstanceID, vendorID, neInstanceID string, window int) string {
 	baseNS := uuid.NewSHA1(uuid.NameSpaceURL, []byte("camara-efn-api:event-id:ne"))
-	name := requestID + "\x00" + appInstanceID + "\x00" + neInstanceID
+	name := requestID + "\x00" + appInstanceID + "\x00" + neInstanceID + windowSuffix(window)
+	if vendorID != "" {
+		name += "\x00vendor=" + vendorID
+	}
 	return uuid.NewSHA1(baseNS, []byte(name)).String()
 }
 
-// EventIDForTraffic returns a deterministic UUIDv5 for traffic volume batch requests.
-func EventIDForTraffic(requestID, appInstanceID string) string {
+// EventIDForTraffic returns a deterministic UUIDv5 for traffic volume batch requests over a window.
+func EventIDForTraffic(requestID, appInstanceID string, window int) string {
 	baseNS := uuid.NewSHA1(uuid.NameSpaceURL, []byte("camara-efn-api:event-id:traffic"))
-	name := requestID + "\x00" + appInstanceID
+	name := requestID + "\x00" + appInstanceID + windowSuffix(window)
 	return uuid.NewSHA1(baseNS, []byte(name)).String()
 }
+
+// windowSuffix distinguishes the names of the event IDs of a window. It is empty for the first window.
+func windowSuffix(window int) string {
+	if window == 0 {
+		return ""
+	}
+	return "\x00" + strconv.Itoa(window)
+}
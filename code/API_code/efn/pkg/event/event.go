@@ -16,15 +16,25 @@ limitations under the License.
 package event
 
 import (
+	"context"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
 )
 
 const (
 	partitionKey string = "partitionkey"
 )
 
+// ContextWithCorrelator returns a copy of ctx carrying the correlation ID of the received event, if any,
+// so that it is propagated to the events sent and the log lines written while handling it.
+func ContextWithCorrelator(ctx context.Context, e cloudevents.Event) context.Context {
+	id, _ := e.Extensions()[correlator.Extension].(string)
+	return correlator.NewContext(ctx, id)
+}
+
 func Event(requestID string, eventType EventType, source Source, data any, opts ...Option) (*cloudevents.Event, error) {
 	e := cloudevents.NewEvent()
 	e.SetID(requestID)
@@ -23,6 +23,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
 )
 
@@ -40,6 +41,15 @@ func WithSubject(sub string) Option {
 	return func(e *cloudevents.Event) { e.SetSubject(sub) }
 }
 
+// WithCorrelator sets the correlation ID extension. An empty ID is not set.
+func WithCorrelator(id string) Option {
+	return func(e *cloudevents.Event) {
+		if id != "" {
+			e.SetExtension(correlator.Extension, id)
+		}
+	}
+}
+
 // NewSender creates a Sender using the sink URL from K_SINK.
 // Requires a SinkBinding or K_SINK environment variable pointing to the broker.
 func NewSender() (Sender, error) {
@@ -54,8 +64,9 @@ func NewSender() (Sender, error) {
 	return &sender{client: c}, nil
 }
 
+// Send emits the event to the broker. The correlation ID carried by ctx, if any, is propagated as an extension.
 func (s *sender) Send(ctx context.Context, id string, eventType EventType, source Source, data any, opts ...Option) (err error) {
-	log := logger.Get()
+	log := logger.FromContext(ctx)
 	log.With(
 		zap.String("event-id", id),
 		zap.String("event-type", eventType.String()),
@@ -68,7 +79,7 @@ func (s *sender) Send(ctx context.Context, id string, eventType EventType, sourc
 		}
 	}()
 
-	e, err := Event(id, eventType, source, data, opts...)
+	e, err := Event(id, eventType, source, data, append([]Option{WithCorrelator(correlator.FromContext(ctx))}, opts...)...)
 	if err != nil {
 		return nil
 	}
@@ -36,11 +36,18 @@ const (
 	// EventTypeNotificationErrorRequested is sent by the EFN Worker when an error occurs during processing.
 	EventTypeNotificationErrorRequested EventType = "it.tim.efn.notification.error.requested"
 
+	// EventTypeNotificationCancelledRequested is sent by the EFN API when a job has been cancelled by the API consumer.
+	EventTypeNotificationCancelledRequested EventType = "it.tim.efn.notification.cancelled.requested"
+
 	// EventTypeNotificationSent is sent by the EFN Notify service when a notification has been sent.
 	EventTypeNotificationSent EventType = "it.tim.efn.notification.sent"
 
 	// EventTypeCalculationRequested is sent by the worker to itself to trigger calculation after all values are gathered.
 	EventTypeCalculationRequested EventType = "it.tim.efn.calculation.requested"
+
+	// EventTypeScheduleTick is sent every minute by a Knative PingSource to let the worker start the due runs
+	// of periodic reports. The type is set by Knative.
+	EventTypeScheduleTick EventType = "dev.knative.sources.ping"
 )
 
 func (s EventType) String() string {
@@ -58,6 +65,9 @@ const (
 
 	// SourceEFNNotify is the CloudEvents source for the EFN Notify service.
 	SourceEFNNotify Source = "urn:tim:efn-notify"
+
+	// SourceEFNScheduler is the CloudEvents source for the runs of periodic reports started by the worker.
+	SourceEFNScheduler Source = "urn:tim:efn-scheduler"
 )
 
 func (s Source) String() string {
@@ -16,6 +16,7 @@ limitations under the License.
 package logger
 
 import (
+	"context"
 	"log"
 	"os"
 	"runtime/debug"
@@ -25,6 +26,7 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/config"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
 )
 
 var once sync.Once
@@ -88,6 +90,16 @@ func Get() *zap.Logger {
 	return logger
 }
 
+// FromContext returns the logger annotated with the request-scoped values carried by ctx,
+// such as the correlation ID.
+func FromContext(ctx context.Context) *zap.Logger {
+	log := Get()
+	if id := correlator.FromContext(ctx); id != "" {
+		log = log.With(zap.String(correlator.LogKey, id))
+	}
+	return log
+}
+
 func IsDebug() bool {
 	return config.GetLogConfig().Level == "debug"
 }
@@ -0,0 +1,37 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+)
+
+// Correlator stores the x-correlator request header in the request context and echoes it
+// in the response, so that it can be propagated to events, log lines and callbacks.
+func Correlator() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			id := c.Request().Header.Get(correlator.Header)
+			if id != "" {
+				c.Response().Header().Set(correlator.Header, id)
+				c.SetRequest(c.Request().WithContext(correlator.NewContext(c.Request().Context(), id)))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
+)
+
+func TestCorrelator(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{
+			name:   "stores and echoes the header when present",
+			header: "b4333c46-49c0-4f62-80d7-f0ef930f1c46",
+		},
+		{
+			name: "leaves context and response untouched when absent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/calculate-energy-consumption", nil)
+			if tt.header != "" {
+				req.Header.Set(correlator.Header, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			var gotID string
+			handler := Correlator()(func(c echo.Context) error {
+				gotID = correlator.FromContext(c.Request().Context())
+				return c.NoContent(http.StatusCreated)
+			})
+
+			assert.NoError(t, handler(c))
+			assert.Equal(t, tt.header, gotID)
+			assert.Equal(t, tt.header, rec.Header().Get(correlator.Header))
+		})
+	}
+}
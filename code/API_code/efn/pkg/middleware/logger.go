@@ -22,6 +22,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"go.uber.org/zap"
 
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/correlator"
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/logger"
 )
 
@@ -48,6 +49,9 @@ func ZapLogger() echo.MiddlewareFunc {
 				zap.String("userAgent", values.UserAgent),
 				zap.Int64("latencyMicroseconds", values.Latency.Microseconds()),
 			}
+			if id := correlator.FromContext(c.Request().Context()); id != "" {
+				fields = append(fields, zap.String(correlator.LogKey, id))
+			}
 			if values.Error != nil {
 				fields = append(fields, zap.Error(values.Error))
 			}
@@ -68,11 +72,11 @@ func DebugBodyLogger() echo.MiddlewareFunc {
 		}
 	}
 
-	log := logger.Get()
 	return middleware.BodyDump(func(c echo.Context, reqBody, resBody []byte) {
 		if c.Path() == "/healthz" {
 			return
 		}
+		log := logger.FromContext(c.Request().Context())
 		var reqBodyMap, resBodyMap map[string]any
 		if len(reqBody) > 0 {
 			if err := json.Unmarshal(reqBody, &reqBodyMap); err != nil {
@@ -17,6 +17,10 @@ package orchestrator
 
 import "context"
 
+func init() {
+	Backends.Register("dummy", func() (Interface, error) { return NewDummyClient() })
+}
+
 type dummyClient struct{}
 
 func NewDummyClient() (*dummyClient, error) {
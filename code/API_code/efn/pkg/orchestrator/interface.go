@@ -15,7 +15,11 @@ limitations under the License.
 */
 package orchestrator
 
-import "context"
+import (
+	"context"
+
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/registry"
+)
 
 // Information is the consolidated view for a given AppInstanceID.
 // It keeps app/runtime details separate from network context.
@@ -46,6 +50,11 @@ type NEInfo struct {
 	InfraType string `json:"infraType"`
 }
 
+// Backends holds the orchestrator adapters, the one used being selected by name in the configuration.
+var Backends = registry.New[Interface]("orchestrator")
+
+// Interface is implemented by the orchestrator adapters. Their errors are classified with datasource.Error,
+// an unclassified error is retried.
 type Interface interface {
 	// GatherInformation resolves the Information required for energy computation for the given appInstanceID.
 	GatherInformation(ctx context.Context, appInstanceID string) (Information, error)
@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package registry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+// Factory creates a backend, reading its configuration from the environment.
+type Factory[T any] func() (T, error)
+
+// Registry holds the factories of the implementations of a kind of backend, by name, so that the
+// implementation used is chosen by configuration. Packages register their implementations when
+// initialised.
+type Registry[T any] struct {
+	kind      string
+	mu        sync.RWMutex
+	factories map[string]Factory[T]
+}
+
+// New creates an empty registry of the given kind of backend, which is used in error messages.
+func New[T any](kind string) *Registry[T] {
+	return &Registry[T]{kind: kind, factories: make(map[string]Factory[T])}
+}
+
+// Register makes a backend implementation available under name. It panics if the name is already
+// registered or the factory is nil, which are programming errors.
+func (r *Registry[T]) Register(name string, factory Factory[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if factory == nil {
+		panic(fmt.Sprintf("registry: nil factory for %s backend %q", r.kind, name))
+	}
+	if _, ok := r.factories[name]; ok {
+		panic(fmt.Sprintf("registry: %s backend %q registered twice", r.kind, name))
+	}
+	r.factories[name] = factory
+}
+
+// New creates the backend registered under name.
+func (r *Registry[T]) New(name string) (T, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[name]
+	r.mu.RUnlock()
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("unknown %s backend %q, available: %s", r.kind, name, strings.Join(r.Names(), ", "))
+	}
+	backend, err := factory()
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to create %s backend %q: %w", r.kind, name, err)
+	}
+	return backend, nil
+}
+
+// Names returns the names registered, sorted.
+func (r *Registry[T]) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WithConfig returns a factory loading the typed configuration C from the environment variables of
+// prefix, as pkg/config does, before building the backend with it.
+func WithConfig[C, T any](prefix string, build func(C) (T, error)) Factory[T] {
+	return func() (T, error) {
+		var conf C
+		if err := envconfig.Process(prefix, &conf); err != nil {
+			var zero T
+			return zero, fmt.Errorf("invalid %s configuration: %w", prefix, err)
+		}
+		return build(conf)
+	}
+}
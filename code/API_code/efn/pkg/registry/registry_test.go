@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package registry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type fixedGreeter string
+
+func (g fixedGreeter) Greet() string {
+	return string(g)
+}
+
+func TestRegistry(t *testing.T) {
+	r := New[greeter]("greeter")
+	r.Register("hello", func() (greeter, error) { return fixedGreeter("hello"), nil })
+	r.Register("broken", func() (greeter, error) { return nil, errors.New("missing endpoint") })
+
+	t.Run("creates the backend registered under a name", func(t *testing.T) {
+		g, err := r.New("hello")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", g.Greet())
+	})
+	t.Run("lists the names available for an unknown name", func(t *testing.T) {
+		_, err := r.New("bonjour")
+		assert.EqualError(t, err, `unknown greeter backend "bonjour", available: broken, hello`)
+	})
+	t.Run("reports the error of the factory", func(t *testing.T) {
+		_, err := r.New("broken")
+		assert.EqualError(t, err, `failed to create greeter backend "broken": missing endpoint`)
+	})
+	t.Run("refuses a name registered twice", func(t *testing.T) {
+		assert.Panics(t, func() {
+			r.Register("hello", func() (greeter, error) { return fixedGreeter("hi"), nil })
+		})
+	})
+}
+
+func TestWithConfig(t *testing.T) {
+	type greeterConfig struct {
+		Greeting string `default:"hello"`
+		Repeat   int    `default:"1"`
+	}
+	factory := WithConfig("greeter", func(conf greeterConfig) (greeter, error) {
+		greeting := ""
+		for range conf.Repeat {
+			greeting += conf.Greeting
+		}
+		return fixedGreeter(greeting), nil
+	})
+
+	t.Run("loads the defaults", func(t *testing.T) {
+		g, err := factory()
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", g.Greet())
+	})
+	t.Run("loads the variables of the prefix", func(t *testing.T) {
+		t.Setenv("GREETER_GREETING", "hi")
+		t.Setenv("GREETER_REPEAT", "2")
+		g, err := factory()
+		assert.NoError(t, err)
+		assert.Equal(t, "hihi", g.Greet())
+	})
+	t.Run("rejects an invalid configuration", func(t *testing.T) {
+		t.Setenv("GREETER_REPEAT", "twice")
+		_, err := factory()
+		assert.ErrorContains(t, err, "invalid greeter configuration")
+	})
+}
@@ -62,6 +62,8 @@ func statusCodeFromError(err error) int {
 	// Convert your errors to http status code
 	case IsNotFound(err):
 		return http.StatusNotFound
+	case IsAlreadyExists(err):
+		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
 	}
@@ -20,7 +20,10 @@ import (
 	"fmt"
 )
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+)
 
 func NewNotFound(object string) error {
 	return fmt.Errorf("%w: %s", ErrNotFound, object)
@@ -29,3 +32,11 @@ func NewNotFound(object string) error {
 func IsNotFound(err error) bool {
 	return errors.Is(err, ErrNotFound)
 }
+
+func NewAlreadyExists(object string) error {
+	return fmt.Errorf("%w: %s", ErrAlreadyExists, object)
+}
+
+func IsAlreadyExists(err error) bool {
+	return errors.Is(err, ErrAlreadyExists)
+}
@@ -24,16 +24,21 @@ import (
 	"time"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/datasource"
 )
 
 var _ Interface = &configurableClient{}
 
+func init() {
+	Backends.Register("configurable", func() (Interface, error) { return NewConfigurableClient() })
+}
+
 // configurableClient allows configuring behavior via environment variables:
 // - TRAFFIC_CONFIG_IP_VOLUME: Default app instance IP traffic volume in Mbps (default: 100.0)
 // - TRAFFIC_CONFIG_ALL_VOLUME: Default total NE traffic volume in Mbps (default: 1000.0)
 // - TRAFFIC_CONFIG_SUCCESS_COUNT: If >0, always succeed (default: 0)
 // - TRAFFIC_CONFIG_ERROR_COUNT: If >0 and successCount=0, always fail (default: 0)
-// - TRAFFIC_CONFIG_ERROR_TYPE: Type of error to return: "permanent" or "throttling" (default: "throttling")
+// - TRAFFIC_CONFIG_ERROR_TYPE: Type of error to return: "throttling", "retryable", "permanent", "not-found" or "unauthorized" (default: "throttling")
 // - TRAFFIC_CONFIG_DELAY_MS: Request processing delay in milliseconds (default: 0)
 //
 // Behavior:
@@ -82,7 +87,7 @@ func NewConfigurableClient() (*configurableClient, error) {
 
 	errorType := "throttling"
 	if val := os.Getenv("TRAFFIC_CONFIG_ERROR_TYPE"); val != "" {
-		if val == "throttling" || val == "permanent" {
+		if _, ok := configurableErrorClasses[val]; ok {
 			errorType = val
 		}
 	}
@@ -120,11 +125,20 @@ func (c *configurableClient) shouldReturnError() (bool, error) {
 	return false, nil
 }
 
+// configurableErrorClasses maps the error types of the configurable client to their class.
+var configurableErrorClasses = map[string]datasource.Class{
+	"throttling":   datasource.ClassThrottled,
+	"retryable":    datasource.ClassRetryable,
+	"permanent":    datasource.ClassPermanent,
+	"not-found":    datasource.ClassNotFound,
+	"unauthorized": datasource.ClassUnauthorized,
+}
+
 func (c *configurableClient) makeError() error {
-	if c.errorType == "throttling" {
-		return NewThrottlingError(fmt.Sprintf("configurable throttling error (request #%d)", c.requestCount))
+	return &datasource.Error{
+		Class:   configurableErrorClasses[c.errorType],
+		Message: fmt.Sprintf("configurable %s error (request #%d)", c.errorType, c.requestCount),
 	}
-	return fmt.Errorf("configurable permanent error (request #%d)", c.requestCount)
 }
 
 func (c *configurableClient) RetrieveTrafficVolumes(ctx context.Context, appInstanceIPList []string, networkElements []NetworkElement, timePeriod *models.TimePeriod) (*TrafficVolumeMeasureList, error) {
@@ -23,6 +23,10 @@ import (
 
 var _ Interface = &dummyClient{}
 
+func init() {
+	Backends.Register("dummy", func() (Interface, error) { return NewDummyClient() })
+}
+
 type dummyClient struct{}
 
 func NewDummyClient() (*dummyClient, error) {
@@ -19,6 +19,7 @@ import (
 	"context"
 
 	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/api/models"
+	"github.com/camaraproject/EnergyFootprintNotification_PI/code/API_code/efn/pkg/registry"
 )
 
 // NetworkElement identifies a specific network element by vendor and NE identifier.
@@ -39,6 +40,11 @@ type TrafficVolumeMeasureList struct {
 	TrafficVolumeMeasureList []TrafficVolumeMeasure `json:"TrafficVolumeMeasureList"`
 }
 
+// Backends holds the traffic volume adapters, the one used being selected by name in the configuration.
+var Backends = registry.New[Interface]("traffic volume")
+
+// Interface is implemented by the traffic volume adapters. Their errors are classified with datasource.Error,
+// an unclassified error is retried.
 type Interface interface {
 	// RetrieveTrafficVolumes retrieves traffic volume measurements for a list of network elements.
 	// It returns both the app instance IP volume and total NE volume for each network element.
@@ -0,0 +1,39 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package trafficvolume
+
+// Match pairs the network elements requested with their measure in the response, by vendor and network element
+// identifier together: network elements of different vendors may share an identifier. It also returns the
+// network elements requested that the response has no measure for, in the order requested.
+func Match(requested []NetworkElement, response *TrafficVolumeMeasureList) (map[NetworkElement]TrafficVolumeMeasure, []NetworkElement) {
+	measures := make(map[NetworkElement]TrafficVolumeMeasure, len(requested))
+	if response != nil {
+		for _, measure := range response.TrafficVolumeMeasureList {
+			measures[measure.NetworkElement] = measure
+		}
+	}
+	matched := make(map[NetworkElement]TrafficVolumeMeasure, len(requested))
+	var missing []NetworkElement
+	for _, ne := range requested {
+		measure, ok := measures[ne]
+		if !ok {
+			missing = append(missing, ne)
+			continue
+		}
+		matched[ne] = measure
+	}
+	return matched, missing
+}
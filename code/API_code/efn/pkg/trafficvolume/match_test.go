@@ -0,0 +1,46 @@
+/*
+Copyright (C) 2022-2025 Contributors | TIM S.p.A. to CAMARA a Series of LF Projects, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package trafficvolume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	ne1 := NetworkElement{VendorIdentifier: "vendor-1", NEIdentifier: "ne-1"}
+	ne2 := NetworkElement{VendorIdentifier: "vendor-2", NEIdentifier: "ne-2"}
+	ne3 := NetworkElement{VendorIdentifier: "vendor-2", NEIdentifier: "ne-3"}
+	response := &TrafficVolumeMeasureList{TrafficVolumeMeasureList: []TrafficVolumeMeasure{
+		{NetworkElement: ne1, TrafficVolumeIP: 10, TrafficVolumeAll: 100},
+		// Same identifier as ne2, another vendor.
+		{NetworkElement: NetworkElement{VendorIdentifier: "vendor-1", NEIdentifier: "ne-2"}, TrafficVolumeIP: 20, TrafficVolumeAll: 200},
+		{NetworkElement: ne3, TrafficVolumeIP: 30, TrafficVolumeAll: 300},
+	}}
+
+	matched, missing := Match([]NetworkElement{ne1, ne2, ne3}, response)
+
+	assert.Equal(t, map[NetworkElement]TrafficVolumeMeasure{
+		ne1: {NetworkElement: ne1, TrafficVolumeIP: 10, TrafficVolumeAll: 100},
+		ne3: {NetworkElement: ne3, TrafficVolumeIP: 30, TrafficVolumeAll: 300},
+	}, matched)
+	assert.Equal(t, []NetworkElement{ne2}, missing)
+
+	matched, missing = Match([]NetworkElement{ne1}, nil)
+	assert.Empty(t, matched)
+	assert.Equal(t, []NetworkElement{ne1}, missing)
+}